@@ -0,0 +1,424 @@
+package gosms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	maxAlphanumericLength int  = 11
+	maxShortCodeLength    int  = 6
+	minShortCodeLength    int  = 3
+	minE164Length         int  = 7
+	maxE164Length         int  = 15
+	semiOctetBits         int  = 4
+	semiOctetFiller       byte = 0x0F
+	typeOfAddressBase     byte = 0x80
+)
+
+var (
+	// ErrInvalidNumber indicates that a phone number cannot be parsed into E.164
+	ErrInvalidNumber = errors.New("the phone number is invalid")
+
+	// ErrInvalidAlphanumeric indicates that an alphanumeric address is empty
+	ErrInvalidAlphanumeric = errors.New("the alphanumeric address is invalid")
+
+	// ErrAlphanumericTooLong indicates that an alphanumeric address does not fit in the 11 septets of an address field
+	ErrAlphanumericTooLong = errors.New("the alphanumeric address is longer than 11 GSM characters")
+
+	// ErrAlphanumericNotGSM indicates that an alphanumeric address has characters outside the GSM alphabet
+	ErrAlphanumericNotGSM = errors.New("the alphanumeric address has characters outside the GSM alphabet")
+
+	// ErrUnsupportedRegion indicates that a national number was given without a known default region
+	ErrUnsupportedRegion = errors.New("the region is not supported")
+
+	// ErrAlphanumericRecipient indicates that a message was addressed to an alphanumeric address
+	ErrAlphanumericRecipient = errors.New("messages cannot be sent to alphanumeric addresses")
+)
+
+// AddressType classifies an SMS address
+type AddressType int
+
+const (
+	// AddressTypeInternational is a phone number in E.164 format
+	AddressTypeInternational AddressType = iota
+
+	// AddressTypeShortCode is a short code of up to 6 digits
+	AddressTypeShortCode
+
+	// AddressTypeAlphanumeric is an alphanumeric sender ID of up to 11 GSM characters
+	AddressTypeAlphanumeric
+
+	// AddressTypeNational is a phone number without a country code, as received in a PDU
+	AddressTypeNational
+)
+
+// TypeOfNumber is the type of number (TON) of an address, as used in PDUs and SMPP
+type TypeOfNumber byte
+
+const (
+	// TypeOfNumberUnknown is used when the type of number is not known
+	TypeOfNumberUnknown TypeOfNumber = 0x00
+
+	// TypeOfNumberInternational is used for numbers with a country code
+	TypeOfNumberInternational TypeOfNumber = 0x01
+
+	// TypeOfNumberNational is used for numbers without a country code
+	TypeOfNumberNational TypeOfNumber = 0x02
+
+	// TypeOfNumberNetworkSpecific is used for numbers specific to a network
+	TypeOfNumberNetworkSpecific TypeOfNumber = 0x03
+
+	// TypeOfNumberSubscriber is used for subscriber numbers
+	TypeOfNumberSubscriber TypeOfNumber = 0x04
+
+	// TypeOfNumberAlphanumeric is used for alphanumeric addresses
+	TypeOfNumberAlphanumeric TypeOfNumber = 0x05
+
+	// TypeOfNumberAbbreviated is used for abbreviated numbers
+	TypeOfNumberAbbreviated TypeOfNumber = 0x06
+)
+
+// NumberingPlan is the numbering plan indicator (NPI) of an address, as used in PDUs and SMPP
+type NumberingPlan byte
+
+const (
+	// NumberingPlanUnknown is used when the numbering plan is not known
+	NumberingPlanUnknown NumberingPlan = 0x00
+
+	// NumberingPlanISDN is the ISDN/telephone numbering plan (E.164)
+	NumberingPlanISDN NumberingPlan = 0x01
+
+	// NumberingPlanData is the data numbering plan (X.121)
+	NumberingPlanData NumberingPlan = 0x03
+
+	// NumberingPlanTelex is the telex numbering plan
+	NumberingPlanTelex NumberingPlan = 0x04
+
+	// NumberingPlanNational is the national numbering plan
+	NumberingPlanNational NumberingPlan = 0x08
+
+	// NumberingPlanPrivate is the private numbering plan
+	NumberingPlanPrivate NumberingPlan = 0x09
+)
+
+// region describes how numbers are dialled within a region, and how many
+// digits its national significant numbers have
+type region struct {
+	countryCode         string
+	trunkPrefix         string
+	internationalPrefix string
+	minNationalLength   int
+	maxNationalLength   int
+}
+
+// regions are keyed by ISO 3166-1 alpha-2 code
+var regions = map[string]region{
+	"AR": {"54", "0", "00", 10, 11},
+	"AT": {"43", "0", "00", 4, 13},
+	"AU": {"61", "0", "0011", 9, 9},
+	"BE": {"32", "0", "00", 8, 9},
+	"BR": {"55", "0", "00", 10, 11},
+	"CA": {"1", "1", "011", 10, 10},
+	"CH": {"41", "0", "00", 9, 9},
+	"CN": {"86", "0", "00", 9, 11},
+	"CZ": {"420", "", "00", 9, 9},
+	"DE": {"49", "0", "00", 5, 13},
+	"DK": {"45", "", "00", 8, 8},
+	"EG": {"20", "0", "00", 8, 10},
+	"ES": {"34", "", "00", 9, 9},
+	"FI": {"358", "0", "00", 5, 12},
+	"FR": {"33", "0", "00", 9, 9},
+	"GB": {"44", "0", "00", 9, 10},
+	"GR": {"30", "", "00", 10, 10},
+	"HK": {"852", "", "001", 8, 8},
+	"IE": {"353", "0", "00", 7, 9},
+	"IL": {"972", "0", "00", 8, 9},
+	"IN": {"91", "0", "00", 10, 10},
+	"IT": {"39", "", "00", 6, 11},
+	"JP": {"81", "0", "010", 9, 10},
+	"KR": {"82", "0", "001", 8, 10},
+	"MX": {"52", "", "00", 10, 10},
+	"NG": {"234", "0", "009", 7, 10},
+	"NL": {"31", "0", "00", 9, 9},
+	"NO": {"47", "", "00", 8, 8},
+	"NZ": {"64", "0", "00", 8, 10},
+	"PH": {"63", "0", "00", 8, 10},
+	"PL": {"48", "", "00", 9, 9},
+	"PT": {"351", "", "00", 9, 9},
+	"RU": {"7", "8", "810", 10, 10},
+	"SE": {"46", "0", "00", 7, 9},
+	"SG": {"65", "", "000", 8, 8},
+	"TR": {"90", "0", "00", 10, 10},
+	"US": {"1", "1", "011", 10, 10},
+	"ZA": {"27", "0", "00", 9, 9},
+}
+
+// Address is a validated SMS address
+type Address struct {
+	value       string
+	addressType AddressType
+}
+
+// ParseAddress parses address, normalising phone numbers into E.164. National
+// numbers are interpreted using the dialling rules of defaultRegion, an ISO
+// 3166-1 alpha-2 code such as "US", and must have as many digits as national
+// numbers in that region. Numbers of up to 6 digits without a country
+// code are short codes, and anything other than a phone number is alphanumeric.
+func ParseAddress(address string, defaultRegion string) (Address, error) {
+	address = strings.TrimSpace(address)
+
+	number, isNumber := stripNumberFormatting(address)
+	if !isNumber {
+		return parseAlphanumeric(address)
+	}
+
+	// numbers with a plus sign are already international
+	if strings.HasPrefix(number, "+") {
+		return newInternationalAddress(number[1:])
+	}
+
+	if len(number) >= minShortCodeLength && len(number) <= maxShortCodeLength {
+		return Address{value: number, addressType: AddressTypeShortCode}, nil
+	}
+
+	numberRegion, ok := regions[strings.ToUpper(defaultRegion)]
+	if !ok {
+		return Address{}, ErrUnsupportedRegion
+	}
+	if strings.HasPrefix(number, numberRegion.internationalPrefix) {
+		return newInternationalAddress(strings.TrimPrefix(number, numberRegion.internationalPrefix))
+	}
+	if numberRegion.trunkPrefix != "" {
+		number = strings.TrimPrefix(number, numberRegion.trunkPrefix)
+	}
+	if len(number) < numberRegion.minNationalLength || len(number) > numberRegion.maxNationalLength {
+		return Address{}, ErrInvalidNumber
+	}
+	return newInternationalAddress(numberRegion.countryCode + number)
+}
+
+// GetValue returns the address in E.164 format for phone numbers, or as given otherwise
+func (a *Address) GetValue() string {
+	return a.value
+}
+
+// GetType returns the classification of the address
+func (a *Address) GetType() AddressType {
+	return a.addressType
+}
+
+// GetDigits returns the digits of a phone number or short code, without a plus sign
+func (a *Address) GetDigits() string {
+	return strings.TrimPrefix(a.value, "+")
+}
+
+// GetTypeOfNumber returns the type of number of the address
+func (a *Address) GetTypeOfNumber() TypeOfNumber {
+	switch a.addressType {
+	case AddressTypeInternational:
+		return TypeOfNumberInternational
+	case AddressTypeAlphanumeric:
+		return TypeOfNumberAlphanumeric
+	case AddressTypeNational:
+		return TypeOfNumberNational
+	}
+	return TypeOfNumberUnknown
+}
+
+// GetNumberingPlan returns the numbering plan of the address
+func (a *Address) GetNumberingPlan() NumberingPlan {
+	if a.addressType == AddressTypeAlphanumeric {
+		return NumberingPlanUnknown
+	}
+	return NumberingPlanISDN
+}
+
+// String returns the address in E.164 format for phone numbers, or as given otherwise
+func (a Address) String() string {
+	return a.value
+}
+
+// MarshalTPAddress encodes the address as a TP-OA or TP-DA field, as described
+// in 3GPP TS 23.040. The field starts with the number of useful semi-octets and
+// the type of address. Phone numbers follow as swapped semi-octets, and
+// alphanumeric addresses as packed GSM septets.
+func (a *Address) MarshalTPAddress() ([]byte, error) {
+	typeOfAddress := typeOfAddressBase | byte(a.GetTypeOfNumber())<<semiOctetBits | byte(a.GetNumberingPlan())
+
+	if a.addressType == AddressTypeAlphanumeric {
+		// check again, since the address may not have come from ParseAddress
+		if _, err := parseAlphanumeric(a.value); err != nil {
+			return nil, err
+		}
+		septets, err := NewGSM().(*GSM).EncodeSeptets(a.value)
+		if err != nil {
+			return nil, ErrAlphanumericNotGSM
+		}
+		semiOctets := (len(septets)*septetBits + semiOctetBits - 1) / semiOctetBits
+		field := []byte{byte(semiOctets), typeOfAddress}
+		return append(field, PackSeptets(septets, 0)...), nil
+	}
+
+	digits := a.GetDigits()
+	if len(digits) == 0 {
+		return nil, ErrInvalidNumber
+	}
+	field := []byte{byte(len(digits)), typeOfAddress}
+	return append(field, encodeSemiOctets(digits)...), nil
+}
+
+// unmarshalTPAddress decodes a TP-OA or TP-DA field from the start of data, and
+// returns the number of bytes it takes up
+func unmarshalTPAddress(data []byte) (Address, int, error) {
+	if len(data) < 2 {
+		return Address{}, 0, ErrInvalidTPDU
+	}
+	semiOctets := int(data[0])
+	length := 2 + (semiOctets+1)/2
+	if length > len(data) {
+		return Address{}, 0, ErrInvalidTPDU
+	}
+	value := data[2:length]
+
+	switch TypeOfNumber(data[1]>>uint(semiOctetBits)) & 0x07 {
+	case TypeOfNumberAlphanumeric:
+		gsm := NewGSM().(*GSM)
+		address, err := gsm.DecodeSeptets(UnpackSeptets(value, 0, semiOctets*semiOctetBits/septetBits))
+		if err != nil {
+			return Address{}, 0, err
+		}
+		return Address{value: address, addressType: AddressTypeAlphanumeric}, length, nil
+	case TypeOfNumberInternational:
+		return Address{value: "+" + decodeSemiOctets(value, semiOctets), addressType: AddressTypeInternational}, length, nil
+	}
+
+	digits := decodeSemiOctets(value, semiOctets)
+	if len(digits) <= maxShortCodeLength {
+		return Address{value: digits, addressType: AddressTypeShortCode}, length, nil
+	}
+	return Address{value: digits, addressType: AddressTypeNational}, length, nil
+}
+
+// decodeSemiOctets decodes count digits from semi-octets with the first digit in
+// the low nibble of each octet
+func decodeSemiOctets(semiOctets []byte, count int) string {
+	digits := make([]byte, 0, count)
+
+	for idx := 0; idx < count; idx++ {
+		digit := semiOctets[idx/2] >> uint(semiOctetBits*(idx%2)) & semiOctetFiller
+		if digit > 9 {
+			break
+		}
+		digits = append(digits, '0'+digit)
+	}
+	return string(digits)
+}
+
+// encodeSemiOctets encodes digits as semi-octets with the first digit in the low
+// nibble of each octet, padding an odd number of digits with 0xF
+func encodeSemiOctets(digits string) []byte {
+	semiOctets := make([]byte, (len(digits)+1)/2)
+
+	for idx := range semiOctets {
+		low := digits[2*idx] - '0'
+		high := semiOctetFiller
+		if 2*idx+1 < len(digits) {
+			high = digits[2*idx+1] - '0'
+		}
+		semiOctets[idx] = high<<uint(semiOctetBits) | low
+	}
+	return semiOctets
+}
+
+// stripNumberFormatting removes the spaces, dashes, dots, slashes and brackets
+// used to format phone numbers, and returns false if address is not a phone number
+func stripNumberFormatting(address string) (string, bool) {
+	var number strings.Builder
+
+	for idx, char := range address {
+		switch {
+		case char >= '0' && char <= '9':
+			number.WriteRune(char)
+		case char == '+' && idx == 0:
+			number.WriteRune(char)
+		case strings.ContainsRune(" -./()", char):
+			continue
+		default:
+			return "", false
+		}
+	}
+	return number.String(), number.Len() > 0
+}
+
+// newInternationalAddress returns an international address for digits, which start with a country code
+func newInternationalAddress(digits string) (Address, error) {
+	if len(digits) < minE164Length || len(digits) > maxE164Length || strings.HasPrefix(digits, "0") {
+		return Address{}, ErrInvalidNumber
+	}
+	for _, char := range digits {
+		if char < '0' || char > '9' {
+			return Address{}, ErrInvalidNumber
+		}
+	}
+	return Address{value: "+" + digits, addressType: AddressTypeInternational}, nil
+}
+
+// parseAlphanumeric returns an alphanumeric address of at most 11 GSM characters.
+// Characters from the GSM extension table take up two of the 11 characters.
+func parseAlphanumeric(address string) (Address, error) {
+	codePoints, err := countCodePoints(address, NewGSM())
+	switch {
+	case err != nil:
+		return Address{}, ErrAlphanumericNotGSM
+	case codePoints == 0:
+		return Address{}, ErrInvalidAlphanumeric
+	case codePoints > maxAlphanumericLength:
+		return Address{}, ErrAlphanumericTooLong
+	}
+	return Address{value: address, addressType: AddressTypeAlphanumeric}, nil
+}
+
+// InvalidAddressError indicates that the sender or a recipient of a message is invalid
+type InvalidAddressError struct {
+	address string
+	err     error
+}
+
+// Error returns a description of the error
+func (e *InvalidAddressError) Error() string {
+	return fmt.Sprintf("invalid address %q: %s", e.address, e.err)
+}
+
+// GetAddress returns the invalid address
+func (e *InvalidAddressError) GetAddress() string {
+	return e.address
+}
+
+// Unwrap returns the reason the address is invalid
+func (e *InvalidAddressError) Unwrap() error {
+	return e.err
+}
+
+// normaliseAddresses validates the sender and recipients of a message and
+// returns them in E.164 format. Recipients cannot be alphanumeric.
+func (s *Splitter) normaliseAddresses(from string, to []string) (string, []string, error) {
+	sender, err := ParseAddress(from, s.defaultRegion)
+	if err != nil {
+		return "", nil, &InvalidAddressError{address: from, err: err}
+	}
+
+	recipients := make([]string, 0, len(to))
+	for _, receiver := range to {
+		recipient, err := ParseAddress(receiver, s.defaultRegion)
+		if err == nil && recipient.addressType == AddressTypeAlphanumeric {
+			err = ErrAlphanumericRecipient
+		}
+		if err != nil {
+			return "", nil, &InvalidAddressError{address: receiver, err: err}
+		}
+		recipients = append(recipients, recipient.value)
+	}
+	return sender.value, recipients, nil
+}
@@ -0,0 +1,215 @@
+package gosms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddress(t *testing.T) {
+	var TestParseAddress = []struct {
+		name                  string
+		address               string
+		defaultRegion         string
+		expectedValue         string
+		expectedType          AddressType
+		expectedTypeOfNumber  TypeOfNumber
+		expectedNumberingPlan NumberingPlan
+	}{
+		{"E.164", "+14155550123", "", "+14155550123", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"formatted international", "+44 (20) 7946-0958", "", "+442079460958", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"US national", "(415) 555-0123", "US", "+14155550123", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"US national with trunk prefix", "1 415 555 0123", "us", "+14155550123", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"US international prefix", "011 44 20 7946 0958", "US", "+442079460958", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"GB national with trunk prefix", "020 7946 0958", "GB", "+442079460958", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"GB international prefix", "00 1 415 555 0123", "GB", "+14155550123", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"IT national keeps leading zero", "06 1234 5678", "IT", "+390612345678", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"RU national with trunk prefix", "8 912 345-67-89", "RU", "+79123456789", AddressTypeInternational, TypeOfNumberInternational, NumberingPlanISDN},
+		{"short code", "12345", "", "12345", AddressTypeShortCode, TypeOfNumberUnknown, NumberingPlanISDN},
+		{"short code with region", "898-932", "US", "898932", AddressTypeShortCode, TypeOfNumberUnknown, NumberingPlanISDN},
+		{"alphanumeric", "TextNow", "", "TextNow", AddressTypeAlphanumeric, TypeOfNumberAlphanumeric, NumberingPlanUnknown},
+		{"alphanumeric with spaces", " My Bank 24 ", "", "My Bank 24", AddressTypeAlphanumeric, TypeOfNumberAlphanumeric, NumberingPlanUnknown},
+		{"alphanumeric at maximum length", "ABCDEFGHIJK", "", "ABCDEFGHIJK", AddressTypeAlphanumeric, TypeOfNumberAlphanumeric, NumberingPlanUnknown},
+	}
+
+	for _, tt := range TestParseAddress {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.address, tt.defaultRegion)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedValue, address.GetValue())
+			assert.Equal(t, tt.expectedValue, address.String())
+			assert.Equal(t, tt.expectedType, address.GetType())
+			assert.Equal(t, tt.expectedTypeOfNumber, address.GetTypeOfNumber())
+			assert.Equal(t, tt.expectedNumberingPlan, address.GetNumberingPlan())
+		})
+	}
+}
+
+func TestParseAddressReturnsError(t *testing.T) {
+	var TestParseAddressReturnsError = []struct {
+		name          string
+		address       string
+		defaultRegion string
+		expectedErr   error
+	}{
+		{"empty", "", "", ErrInvalidAlphanumeric},
+		{"plus sign only", "+", "", ErrInvalidNumber},
+		{"international too long", "+1234567890123456", "", ErrInvalidNumber},
+		{"international too short", "+123456", "", ErrInvalidNumber},
+		{"country code starting with zero", "+0123456789", "", ErrInvalidNumber},
+		{"national without region", "4155550123", "", ErrUnsupportedRegion},
+		{"national with unknown region", "4155550123", "XX", ErrUnsupportedRegion},
+		{"US national too short", "1234567", "US", ErrInvalidNumber},
+		{"US national with trunk prefix too short", "1 415 555 012", "US", ErrInvalidNumber},
+		{"GB national too long", "020 7946 09581", "GB", ErrInvalidNumber},
+		{"alphanumeric too long", "ABCDEFGHIJKL", "", ErrAlphanumericTooLong},
+		{"alphanumeric with extension characters", "ABCDEFGHIJ[", "", ErrAlphanumericTooLong},
+		{"alphanumeric outside the GSM alphabet", "Ünïcødé™", "", ErrAlphanumericNotGSM},
+	}
+
+	for _, tt := range TestParseAddressReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAddress(tt.address, tt.defaultRegion)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestAddressGetDigits(t *testing.T) {
+	international, _ := ParseAddress("+14155550123", "")
+	shortCode, _ := ParseAddress("12345", "")
+
+	assert.Equal(t, "14155550123", international.GetDigits())
+	assert.Equal(t, "12345", shortCode.GetDigits())
+}
+
+func TestSplitValidatesAddresses(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetValidateAddresses(true)
+	splitter.SetDefaultRegion("US")
+
+	smsParts, err := splitter.Split("TextNow", []string{"(415) 555-0123"}, "message")
+	assert.NoError(t, err)
+	assert.Equal(t, "TextNow", smsParts[0].GetFrom())
+	assert.Equal(t, "+14155550123", smsParts[0].GetTo())
+
+	// recipients which normalise to the same number are only sent to once
+	splitter.SetPerRecipient(true)
+	smsParts, err = splitter.Split("12345", []string{"415-555-0123", "+14155550123"}, "message")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(smsParts))
+	assert.Equal(t, "12345", smsParts[0].GetFrom())
+	assert.Equal(t, "+14155550123", smsParts[0].GetTo())
+}
+
+func TestSplitReturnsInvalidAddressError(t *testing.T) {
+	var TestSplitReturnsInvalidAddressError = []struct {
+		name            string
+		from            string
+		to              []string
+		perRecipient    bool
+		expectedAddress string
+		expectedErr     error
+	}{
+		{"invalid sender", "A very long sender", []string{"+14155550123"}, false, "A very long sender", ErrAlphanumericTooLong},
+		{"invalid recipient", "12345", []string{"+14155550123", "+1"}, false, "+1", ErrInvalidNumber},
+		{"alphanumeric recipient", "12345", []string{"TextNow"}, false, "TextNow", ErrAlphanumericRecipient},
+		{"invalid recipient per recipient", "12345", []string{"4155550123"}, true, "4155550123", ErrUnsupportedRegion},
+	}
+
+	for _, tt := range TestSplitReturnsInvalidAddressError {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetValidateAddresses(true)
+			splitter.SetPerRecipient(tt.perRecipient)
+
+			smsParts, err := splitter.Split(tt.from, tt.to, "message")
+			assert.Nil(t, smsParts)
+
+			invalidAddress, ok := err.(*InvalidAddressError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedAddress, invalidAddress.GetAddress())
+			assert.True(t, errors.Is(err, tt.expectedErr))
+			assert.Contains(t, err.Error(), tt.expectedAddress)
+		})
+	}
+}
+
+// this test ensures that addresses are not validated unless validation is enabled
+func TestSplitDoesNotValidateAddressesByDefault(t *testing.T) {
+	splitter := NewSplitter()
+
+	smsParts, err := splitter.Split("not a valid sender", []string{"nobody"}, "message")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nobody", smsParts[0].GetTo())
+}
+
+func TestAddressMarshalTPAddress(t *testing.T) {
+	var TestAddressMarshalTPAddress = []struct {
+		name     string
+		address  string
+		expected []byte
+	}{
+		{
+			"international number with an odd number of digits",
+			"+14155550123",
+			[]byte{0x0B, 0x91, 0x41, 0x51, 0x55, 0x05, 0x21, 0xF3},
+		},
+		{
+			"international number with an even number of digits",
+			"+442079460958",
+			[]byte{0x0C, 0x91, 0x44, 0x02, 0x97, 0x64, 0x90, 0x85},
+		},
+		{
+			"short code",
+			"12345",
+			[]byte{0x05, 0x81, 0x21, 0x43, 0xF5},
+		},
+		{
+			// 7 septets take up 49 bits, which is 13 semi-octets
+			"alphanumeric",
+			"TextNow",
+			[]byte{0x0D, 0xD0, 0xD4, 0x32, 0x9E, 0xEE, 0x7C, 0xDF, 0x01},
+		},
+		{
+			"alphanumeric at maximum length",
+			"ABCDEFGHIJK",
+			[]byte{0x14, 0xD0, 0x41, 0xE1, 0x90, 0x58, 0x34, 0x1E, 0x91, 0x49, 0xE5, 0x12},
+		},
+	}
+
+	for _, tt := range TestAddressMarshalTPAddress {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.address, "")
+			assert.NoError(t, err)
+
+			field, err := address.MarshalTPAddress()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, field)
+		})
+	}
+}
+
+// this test ensures that addresses which would not survive delivery cannot be encoded
+func TestAddressMarshalTPAddressReturnsError(t *testing.T) {
+	var TestAddressMarshalTPAddressReturnsError = []struct {
+		name        string
+		address     Address
+		expectedErr error
+	}{
+		{"empty number", Address{}, ErrInvalidNumber},
+		{"alphanumeric too long", Address{value: "ABCDEFGHIJKL", addressType: AddressTypeAlphanumeric}, ErrAlphanumericTooLong},
+		{"alphanumeric outside the GSM alphabet", Address{value: "Bank™", addressType: AddressTypeAlphanumeric}, ErrAlphanumericNotGSM},
+		{"empty alphanumeric", Address{addressType: AddressTypeAlphanumeric}, ErrInvalidAlphanumeric},
+	}
+
+	for _, tt := range TestAddressMarshalTPAddressReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.address.MarshalTPAddress()
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
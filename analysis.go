@@ -0,0 +1,123 @@
+package gosms
+
+// Analysis describes how a message would be split, without building SMSs
+type Analysis struct {
+	encoder       Encoder
+	codePoints    int
+	segments      int
+	remaining     int
+	fallbackChars []rune
+	substitutions []Substitution
+}
+
+// GetEncoder returns the encoder that would be used to split the message
+func (a *Analysis) GetEncoder() Encoder {
+	return a.encoder
+}
+
+// GetCodePoints returns the number of code points in the encoded message
+func (a *Analysis) GetCodePoints() int {
+	return a.codePoints
+}
+
+// GetSegments returns the number of SMS parts the message would be split into
+func (a *Analysis) GetSegments() int {
+	return a.segments
+}
+
+// GetRemaining returns the number of code points still available in the last SMS part
+func (a *Analysis) GetRemaining() int {
+	return a.remaining
+}
+
+// GetFallbackChars returns the characters which cannot be encoded with GSM,
+// and so force a fallback to another encoder, in order of appearance
+func (a *Analysis) GetFallbackChars() []rune {
+	return a.fallbackChars
+}
+
+// GetSubstitutions returns the characters replaced by transliteration
+func (a *Analysis) GetSubstitutions() []Substitution {
+	return a.substitutions
+}
+
+// Analyze previews how message would be split, using the same rules as Split,
+// including the segment limit and truncation. A message sent whole as a
+// payload is a single segment, with the code points remaining before the SMSC
+// needs another part. It is cheap enough to run on every keystroke of a
+// message being composed.
+func (s *Splitter) Analyze(message string) (Analysis, error) {
+	prepared, err := s.prepareMessage(message)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	codePoints, err := countCodePoints(prepared.message, prepared.encoder)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	lastPartCodePoints, err := countCodePoints(prepared.parts[len(prepared.parts)-1], prepared.encoder)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	segments := len(prepared.parts)
+	if s.concatenation == ConcatenationPayload {
+		segments = 1
+	}
+
+	return Analysis{
+		encoder:       prepared.encoder,
+		codePoints:    codePoints,
+		segments:      segments,
+		remaining:     prepared.partLength - lastPartCodePoints,
+		fallbackChars: s.getFallbackChars(prepared.message),
+		substitutions: prepared.substitutions,
+	}, nil
+}
+
+// getFallbackChars returns the unique characters of message which no GSM encoder
+// considered by the splitter can encode
+func (s *Splitter) getFallbackChars(message string) []rune {
+	var fallbackChars []rune
+	seen := make(map[rune]bool)
+
+	gsmEncoders := s.getGSMEncoders()
+	if len(gsmEncoders) == 0 {
+		gsmEncoders = []Encoder{NewGSM()}
+	}
+
+	for _, char := range message {
+		if seen[char] {
+			continue
+		}
+		seen[char] = true
+
+		encodable := false
+		for _, encoder := range gsmEncoders {
+			if _, err := encoder.GetCodePoints(char); err == nil {
+				encodable = true
+				break
+			}
+		}
+		if !encodable {
+			fallbackChars = append(fallbackChars, char)
+		}
+	}
+	return fallbackChars
+}
+
+// countCodePoints returns the number of code points used to represent message
+func countCodePoints(message string, encoder Encoder) (int, error) {
+	var codePoints int
+
+	for _, char := range message {
+		charPoints, err := encoder.GetCodePoints(char)
+		if err != nil {
+			return 0, ErrNotEncodable
+		}
+		codePoints += charPoints
+	}
+	return codePoints, nil
+}
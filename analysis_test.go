@@ -0,0 +1,191 @@
+package gosms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test ensures that Analyze reports the encoder, code points, segments,
+// remaining code points and fallback characters of a message
+func TestAnalyze(t *testing.T) {
+	var TestAnalyze = []struct {
+		name                  string
+		message               string
+		autoSelect            bool
+		expectedEncoder       string
+		expectedCodePoints    int
+		expectedSegments      int
+		expectedRemaining     int
+		expectedFallbackChars []rune
+	}{
+		{
+			"empty message",
+			"",
+			false,
+			EncoderNameGSM,
+			0,
+			1,
+			160,
+			nil,
+		},
+		{
+			"short GSM message",
+			"Hello [world]",
+			false,
+			EncoderNameGSM,
+			15,
+			1,
+			145,
+			nil,
+		},
+		{
+			"full GSM message",
+			strings.Repeat("x", 160),
+			false,
+			EncoderNameGSM,
+			160,
+			1,
+			0,
+			nil,
+		},
+		{
+			"concatenated GSM message",
+			strings.Repeat("x", 161),
+			false,
+			EncoderNameGSM,
+			161,
+			2,
+			145,
+			nil,
+		},
+		{
+			"UTF-16 fallback",
+			"Don’t panic — it’s fine",
+			false,
+			EncoderNameUTF16,
+			23,
+			1,
+			47,
+			[]rune{'’', '—'},
+		},
+		{
+			"auto-selected national language",
+			"Işık ’",
+			true,
+			EncoderNameUTF16,
+			6,
+			1,
+			64,
+			[]rune{'’'},
+		},
+		{
+			"auto-selected national language without fallback",
+			"Işık",
+			true,
+			"GSM (Turkish single shift)",
+			6,
+			1,
+			149,
+			nil,
+		},
+	}
+
+	for _, tt := range TestAnalyze {
+		splitter := NewSplitter()
+		splitter.SetAutoSelect(tt.autoSelect)
+
+		analysis, err := splitter.Analyze(tt.message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when analyzing the message for test '%s'", err, tt.name)
+		}
+
+		assert.Equal(t, tt.expectedEncoder, analysis.GetEncoder().GetEncoderName(), tt.name)
+		assert.Equal(t, tt.expectedCodePoints, analysis.GetCodePoints(), tt.name)
+		assert.Equal(t, tt.expectedSegments, analysis.GetSegments(), tt.name)
+		assert.Equal(t, tt.expectedRemaining, analysis.GetRemaining(), tt.name)
+		assert.Equal(t, tt.expectedFallbackChars, analysis.GetFallbackChars(), tt.name)
+	}
+}
+
+// this test ensures that Analyze never disagrees with Split
+func TestAnalyzeAgreesWithSplit(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	for _, base := range []string{"x", "word ", "[", "😂", "你", "👨‍👩‍👧 ", "ş"} {
+		for _, autoSelect := range []bool{false, true} {
+			for length := 0; length < 400; length += 13 {
+				message := strings.Repeat(base, length)
+
+				splitter := NewSplitter()
+				splitter.SetAutoSelect(autoSelect)
+
+				analysis, err := splitter.Analyze(message)
+				assert.Nil(t, err)
+				SMSs, err := splitter.Split(from, []string{to}, message)
+				assert.Nil(t, err)
+
+				assert.Equal(t, len(SMSs), analysis.GetSegments(), message)
+				assert.Equal(t, SMSs[0].GetEncoder().GetEncoderName(), analysis.GetEncoder().GetEncoderName(), message)
+			}
+		}
+	}
+}
+
+// this test ensures that Analyze fails when the message cannot be encoded
+func TestAnalyzeFails(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetEncoder(NewGSM())
+
+	analysis, err := splitter.Analyze("你好")
+
+	assert.Nil(t, analysis.GetEncoder())
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+}
+
+// this test ensures that Analyze agrees with Split on segment limits, truncation and concatenation
+func TestAnalyzeMatchesSplit(t *testing.T) {
+	var TestAnalyzeMatchesSplit = []struct {
+		name             string
+		maxSegments      int
+		truncate         bool
+		concatenation    Concatenation
+		expectedSegments int
+		expectedErr      error
+	}{
+		{"within the limit", 3, false, ConcatenationUDH, 3, nil},
+		{"rejected", 2, false, ConcatenationUDH, 0, &TooManySegmentsError{segments: 3, maxSegments: 2}},
+		{"truncated", 2, true, ConcatenationUDH, 2, nil},
+		{"SAR capacity", 2, false, ConcatenationSAR, 2, nil},
+		{"payload", 3, false, ConcatenationPayload, 1, nil},
+		{"payload rejected", 2, false, ConcatenationPayload, 0, &TooManySegmentsError{segments: 3, maxSegments: 2}},
+	}
+
+	message := strings.Repeat("word ", 64)
+	for _, tt := range TestAnalyzeMatchesSplit {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetMaxSegments(tt.maxSegments)
+			splitter.SetTruncate(tt.truncate)
+			splitter.SetConcatenation(tt.concatenation)
+
+			analysis, analyzeErr := splitter.Analyze(message)
+			smsParts, splitErr := splitter.Split("from", []string{"to"}, message)
+			assert.Equal(t, tt.expectedErr, analyzeErr)
+			assert.Equal(t, tt.expectedErr, splitErr)
+			if tt.expectedErr != nil {
+				return
+			}
+
+			assert.Equal(t, tt.expectedSegments, analysis.GetSegments())
+			assert.Equal(t, len(smsParts), analysis.GetSegments())
+			var content string
+			for _, sms := range smsParts {
+				content += sms.GetContent()
+			}
+			assert.Equal(t, len(content), analysis.GetCodePoints())
+		})
+	}
+}
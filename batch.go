@@ -0,0 +1,26 @@
+package gosms
+
+// Batch groups the SMSs generated for each recipient of a message
+type Batch struct {
+	recipients []string
+	parts      map[string][]SMS
+}
+
+// GetRecipients returns the recipients of the batch in the order they were given
+func (b *Batch) GetRecipients() []string {
+	return b.recipients
+}
+
+// GetParts returns the SMSs generated for recipient
+func (b *Batch) GetParts(recipient string) []SMS {
+	return b.parts[recipient]
+}
+
+// GetSMSs returns the SMSs of every recipient, grouped by recipient
+func (b *Batch) GetSMSs() []SMS {
+	var smsParts []SMS
+	for _, recipient := range b.recipients {
+		smsParts = append(smsParts, b.parts[recipient]...)
+	}
+	return smsParts
+}
@@ -0,0 +1,88 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const batchMessage = "This message is long enough that it has to be split into several parts."
+
+// recordingReferenceAllocator allocates consecutive references and records who they were allocated to
+type recordingReferenceAllocator struct {
+	recipients []string
+}
+
+func (a *recordingReferenceAllocator) Allocate(from string, to string, content string, shortReference bool) (uint16, error) {
+	a.recipients = append(a.recipients, to)
+	return uint16(len(a.recipients)), nil
+}
+
+func TestSplitPerRecipient(t *testing.T) {
+	var TestSplitPerRecipient = []struct {
+		name               string
+		to                 []string
+		expectedRecipients []string
+	}{
+		{"single recipient", []string{"alice"}, []string{"alice"}},
+		{"several recipients", []string{"alice", "bob", "carol"}, []string{"alice", "bob", "carol"}},
+		{"duplicate recipients", []string{"alice", "bob", "alice"}, []string{"alice", "bob"}},
+		{"no recipients", []string{}, nil},
+	}
+
+	for _, tt := range TestSplitPerRecipient {
+		t.Run(tt.name, func(t *testing.T) {
+			allocator := &recordingReferenceAllocator{}
+			splitter := NewSplitter()
+			splitter.SetMessageBytes(40)
+			splitter.SetReferenceAllocator(allocator)
+
+			batch, err := splitter.SplitPerRecipient("from", tt.to, batchMessage)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRecipients, batch.GetRecipients())
+			assert.Equal(t, tt.expectedRecipients, allocator.recipients)
+
+			for idx, recipient := range batch.GetRecipients() {
+				smsParts := batch.GetParts(recipient)
+				assert.Equal(t, 2, len(smsParts))
+
+				var content string
+				for _, sms := range smsParts {
+					assert.Equal(t, "from", sms.GetFrom())
+					assert.Equal(t, recipient, sms.GetTo())
+					assert.Equal(t, byte(idx+1), sms.udh[3]) // each recipient has its own reference number
+					content += sms.GetContent()
+				}
+				assert.Equal(t, batchMessage, content)
+			}
+			assert.Equal(t, 2*len(tt.expectedRecipients), len(batch.GetSMSs()))
+		})
+	}
+}
+
+func TestSplitInPerRecipientMode(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetPerRecipient(true)
+
+	smsParts, err := splitter.Split("from", []string{"alice", "bob"}, batchMessage)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(smsParts))
+	for idx, expectedTo := range []string{"alice", "alice", "bob", "bob"} {
+		assert.Equal(t, expectedTo, smsParts[idx].GetTo())
+	}
+}
+
+func TestSplitPerRecipientReturnsError(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetMaxSegments(1)
+
+	batch, err := splitter.SplitPerRecipient("from", []string{"alice", "bob"}, batchMessage)
+
+	_, ok := err.(*TooManySegmentsError)
+	assert.True(t, ok)
+	assert.Equal(t, 0, len(batch.GetRecipients()))
+	assert.Nil(t, batch.GetParts("alice"))
+}
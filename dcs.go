@@ -0,0 +1,296 @@
+package gosms
+
+import "errors"
+
+const (
+	dataCodingGroupMask      byte = 0xC0
+	dataCodingGeneral        byte = 0x00
+	dataCodingAutoDelete     byte = 0x40
+	dataCodingCompressedMask byte = 0x20
+	dataCodingHasClassMask   byte = 0x10
+	dataCodingCharsetMask    byte = 0x0C
+	dataCodingCharsetShift   uint = 2
+	dataCodingClassMask      byte = 0x03
+
+	dataCodingExtendedGroupMask  byte = 0xF0
+	dataCodingWaitingDiscard     byte = 0xC0
+	dataCodingWaitingStoreGSM    byte = 0xD0
+	dataCodingWaitingStoreUCS2   byte = 0xE0
+	dataCodingMessageClass       byte = 0xF0
+	dataCodingWaitingActiveMask  byte = 0x08
+	dataCodingWaitingTypeMask    byte = 0x03
+	dataCodingMessageClass8Bit   byte = 0x04
+	dataCodingReservedGroupStart byte = 0x80
+)
+
+// ErrInvalidDCS indicates that a combination of data coding options cannot be encoded in a TP-DCS octet
+var ErrInvalidDCS = errors.New("the data coding options cannot be combined")
+
+// DataCodingGroup is the coding group of a TP-DCS octet, as described in 3GPP TS 23.038
+type DataCodingGroup int
+
+const (
+	// DataCodingGroupGeneral is the general data coding group
+	DataCodingGroupGeneral DataCodingGroup = iota
+
+	// DataCodingGroupAutomaticDeletion is the general data coding group for messages deleted once read
+	DataCodingGroupAutomaticDeletion
+
+	// DataCodingGroupMessageWaitingDiscard indicates waiting messages, and the SMS may be discarded
+	DataCodingGroupMessageWaitingDiscard
+
+	// DataCodingGroupMessageWaitingStore indicates waiting messages, and the SMS should be stored
+	DataCodingGroupMessageWaitingStore
+
+	// DataCodingGroupMessageClass is the data coding and message class group
+	DataCodingGroupMessageClass
+)
+
+// CharacterSet is the alphabet of the user data of an SMS
+type CharacterSet int
+
+const (
+	// CharacterSetGSM is the GSM 7-bit default alphabet
+	CharacterSetGSM CharacterSet = iota
+
+	// CharacterSet8Bit is 8-bit data, which gosms encodes as Latin-1
+	CharacterSet8Bit
+
+	// CharacterSetUCS2 is UCS-2, which gosms encodes as UTF-16
+	CharacterSetUCS2
+)
+
+// MessageClass tells the recipient how to handle an SMS
+type MessageClass int
+
+const (
+	// MessageClassNone means the SMS has no message class
+	MessageClassNone MessageClass = iota
+
+	// MessageClass0 is a flash SMS, which is displayed immediately and not stored
+	MessageClass0
+
+	// MessageClass1 is stored on the mobile equipment
+	MessageClass1
+
+	// MessageClass2 is stored on the SIM
+	MessageClass2
+
+	// MessageClass3 is passed to the terminal equipment
+	MessageClass3
+)
+
+// IndicationType is the kind of message a message waiting DCS indicates
+type IndicationType int
+
+const (
+	// IndicationVoicemail indicates waiting voicemail
+	IndicationVoicemail IndicationType = iota
+
+	// IndicationFax indicates waiting faxes
+	IndicationFax
+
+	// IndicationEmail indicates waiting email
+	IndicationEmail
+
+	// IndicationOther indicates other waiting messages
+	IndicationOther
+)
+
+// DCS is a TP-DCS octet, which describes how the user data of an SMS is coded
+type DCS struct {
+	group            DataCodingGroup
+	characterSet     CharacterSet
+	messageClass     MessageClass
+	compressed       bool
+	indicationActive bool
+	indicationType   IndicationType
+}
+
+// NewDCS returns a DCS in the general data coding group
+func NewDCS(characterSet CharacterSet, messageClass MessageClass) DCS {
+	return DCS{group: DataCodingGroupGeneral, characterSet: characterSet, messageClass: messageClass}
+}
+
+// NewMessageClassDCS returns a DCS in the data coding and message class group,
+// which only supports GSM and 8-bit data and requires a message class
+func NewMessageClassDCS(characterSet CharacterSet, messageClass MessageClass) DCS {
+	return DCS{group: DataCodingGroupMessageClass, characterSet: characterSet, messageClass: messageClass}
+}
+
+// NewMessageWaitingDCS returns a DCS which sets or clears a message waiting
+// indication. Messages which are not stored must be GSM coded.
+func NewMessageWaitingDCS(characterSet CharacterSet, store bool, indicationType IndicationType, active bool) DCS {
+	group := DataCodingGroupMessageWaitingDiscard
+	if store {
+		group = DataCodingGroupMessageWaitingStore
+	}
+	return DCS{group: group, characterSet: characterSet, indicationType: indicationType, indicationActive: active}
+}
+
+// GetGroup returns the coding group of the DCS
+func (d *DCS) GetGroup() DataCodingGroup {
+	return d.group
+}
+
+// GetCharacterSet returns the character set of the DCS
+func (d *DCS) GetCharacterSet() CharacterSet {
+	return d.characterSet
+}
+
+// GetMessageClass returns the message class of the DCS
+func (d *DCS) GetMessageClass() MessageClass {
+	return d.messageClass
+}
+
+// GetCompressed returns true if the user data is compressed
+func (d *DCS) GetCompressed() bool {
+	return d.compressed
+}
+
+// SetCompressed sets whether the user data is compressed, which is only possible in the general coding groups
+func (d *DCS) SetCompressed(compressed bool) {
+	d.compressed = compressed
+}
+
+// GetAutomaticDeletion returns true if the SMS should be deleted once read
+func (d *DCS) GetAutomaticDeletion() bool {
+	return d.group == DataCodingGroupAutomaticDeletion
+}
+
+// SetAutomaticDeletion sets whether the SMS should be deleted once read, which is only possible in the general coding groups
+func (d *DCS) SetAutomaticDeletion(automaticDeletion bool) {
+	switch {
+	case automaticDeletion && d.group == DataCodingGroupGeneral:
+		d.group = DataCodingGroupAutomaticDeletion
+	case !automaticDeletion && d.group == DataCodingGroupAutomaticDeletion:
+		d.group = DataCodingGroupGeneral
+	}
+}
+
+// GetIndicationType returns the kind of message a message waiting DCS indicates
+func (d *DCS) GetIndicationType() IndicationType {
+	return d.indicationType
+}
+
+// GetIndicationActive returns true if a message waiting DCS sets the indication rather than clearing it
+func (d *DCS) GetIndicationActive() bool {
+	return d.indicationActive
+}
+
+// Marshal encodes the DCS as a TP-DCS octet, and returns ErrInvalidDCS if its
+// options are not supported by its coding group
+func (d *DCS) Marshal() (byte, error) {
+	if d.characterSet < CharacterSetGSM || d.characterSet > CharacterSetUCS2 ||
+		d.messageClass < MessageClassNone || d.messageClass > MessageClass3 {
+		return 0, ErrInvalidDCS
+	}
+
+	switch d.group {
+	case DataCodingGroupGeneral, DataCodingGroupAutomaticDeletion:
+		value := dataCodingGeneral
+		if d.group == DataCodingGroupAutomaticDeletion {
+			value = dataCodingAutoDelete
+		}
+		if d.compressed {
+			value |= dataCodingCompressedMask
+		}
+		if d.messageClass != MessageClassNone {
+			value |= dataCodingHasClassMask | byte(d.messageClass-MessageClass0)
+		}
+		return value | byte(d.characterSet)<<dataCodingCharsetShift, nil
+	case DataCodingGroupMessageWaitingDiscard, DataCodingGroupMessageWaitingStore:
+		if d.compressed || d.messageClass != MessageClassNone ||
+			d.indicationType < IndicationVoicemail || d.indicationType > IndicationOther {
+			return 0, ErrInvalidDCS
+		}
+		var value byte
+		switch {
+		case d.characterSet == CharacterSetGSM && d.group == DataCodingGroupMessageWaitingDiscard:
+			value = dataCodingWaitingDiscard
+		case d.characterSet == CharacterSetGSM:
+			value = dataCodingWaitingStoreGSM
+		case d.characterSet == CharacterSetUCS2 && d.group == DataCodingGroupMessageWaitingStore:
+			value = dataCodingWaitingStoreUCS2
+		default:
+			return 0, ErrInvalidDCS
+		}
+		if d.indicationActive {
+			value |= dataCodingWaitingActiveMask
+		}
+		return value | byte(d.indicationType), nil
+	case DataCodingGroupMessageClass:
+		if d.compressed || d.messageClass == MessageClassNone || d.characterSet == CharacterSetUCS2 {
+			return 0, ErrInvalidDCS
+		}
+		value := dataCodingMessageClass | byte(d.messageClass-MessageClass0)
+		if d.characterSet == CharacterSet8Bit {
+			value |= dataCodingMessageClass8Bit
+		}
+		return value, nil
+	}
+	return 0, ErrInvalidDCS
+}
+
+// Unmarshal decodes a DCS from a TP-DCS octet, and returns
+// ErrUnsupportedDataCoding for reserved coding groups and character sets
+func (d *DCS) Unmarshal(value byte) error {
+	if value < dataCodingReservedGroupStart {
+		characterSet := CharacterSet((value & dataCodingCharsetMask) >> dataCodingCharsetShift)
+		if characterSet > CharacterSetUCS2 {
+			return ErrUnsupportedDataCoding
+		}
+
+		*d = DCS{
+			group:        DataCodingGroupGeneral,
+			characterSet: characterSet,
+			compressed:   value&dataCodingCompressedMask != 0,
+		}
+		if value&dataCodingGroupMask == dataCodingAutoDelete {
+			d.group = DataCodingGroupAutomaticDeletion
+		}
+		if value&dataCodingHasClassMask != 0 {
+			d.messageClass = MessageClass0 + MessageClass(value&dataCodingClassMask)
+		}
+		return nil
+	}
+
+	waiting := DCS{
+		indicationActive: value&dataCodingWaitingActiveMask != 0,
+		indicationType:   IndicationType(value & dataCodingWaitingTypeMask),
+	}
+	switch value & dataCodingExtendedGroupMask {
+	case dataCodingWaitingDiscard:
+		waiting.group = DataCodingGroupMessageWaitingDiscard
+		waiting.characterSet = CharacterSetGSM
+	case dataCodingWaitingStoreGSM:
+		waiting.group = DataCodingGroupMessageWaitingStore
+		waiting.characterSet = CharacterSetGSM
+	case dataCodingWaitingStoreUCS2:
+		waiting.group = DataCodingGroupMessageWaitingStore
+		waiting.characterSet = CharacterSetUCS2
+	case dataCodingMessageClass:
+		*d = NewMessageClassDCS(CharacterSetGSM, MessageClass0+MessageClass(value&dataCodingClassMask))
+		if value&dataCodingMessageClass8Bit != 0 {
+			d.characterSet = CharacterSet8Bit
+		}
+		return nil
+	default:
+		return ErrUnsupportedDataCoding
+	}
+	*d = waiting
+	return nil
+}
+
+// getCharacterSet returns the TP-DCS character set of encoder
+func getCharacterSet(encoder Encoder) (CharacterSet, error) {
+	switch encoder.(type) {
+	case *GSM:
+		return CharacterSetGSM, nil
+	case *Latin1:
+		return CharacterSet8Bit, nil
+	case *UTF16:
+		return CharacterSetUCS2, nil
+	}
+	return 0, ErrUnsupportedDataCoding
+}
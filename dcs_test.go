@@ -0,0 +1,127 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDCSMarshal(t *testing.T) {
+	compressed := NewDCS(CharacterSetUCS2, MessageClass1)
+	compressed.SetCompressed(true)
+	automaticDeletion := NewDCS(CharacterSet8Bit, MessageClassNone)
+	automaticDeletion.SetAutomaticDeletion(true)
+
+	var TestDCSMarshal = []struct {
+		name     string
+		dcs      DCS
+		expected byte
+	}{
+		{"GSM", NewDCS(CharacterSetGSM, MessageClassNone), 0x00},
+		{"8-bit", NewDCS(CharacterSet8Bit, MessageClassNone), 0x04},
+		{"UCS-2", NewDCS(CharacterSetUCS2, MessageClassNone), 0x08},
+		{"flash", NewDCS(CharacterSetGSM, MessageClass0), 0x10},
+		{"SIM specific", NewDCS(CharacterSet8Bit, MessageClass2), 0x16},
+		{"TE specific", NewDCS(CharacterSetUCS2, MessageClass3), 0x1B},
+		{"compressed", compressed, 0x39},
+		{"automatic deletion", automaticDeletion, 0x44},
+		{"voicemail waiting", NewMessageWaitingDCS(CharacterSetGSM, false, IndicationVoicemail, true), 0xC8},
+		{"email cleared", NewMessageWaitingDCS(CharacterSetGSM, true, IndicationEmail, false), 0xD2},
+		{"other waiting UCS-2", NewMessageWaitingDCS(CharacterSetUCS2, true, IndicationOther, true), 0xEB},
+		{"message class flash", NewMessageClassDCS(CharacterSetGSM, MessageClass0), 0xF0},
+		{"message class 8-bit", NewMessageClassDCS(CharacterSet8Bit, MessageClass1), 0xF5},
+	}
+
+	for _, tt := range TestDCSMarshal {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.dcs.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+
+			var dcs DCS
+			assert.NoError(t, dcs.Unmarshal(value))
+			assert.Equal(t, tt.dcs, dcs)
+		})
+	}
+}
+
+func TestDCSMarshalReturnsErrorForInvalidCombinations(t *testing.T) {
+	compressedWaiting := NewMessageWaitingDCS(CharacterSetGSM, true, IndicationFax, true)
+	compressedWaiting.SetCompressed(true)
+
+	var TestDCSMarshalReturnsErrorForInvalidCombinations = []struct {
+		name string
+		dcs  DCS
+	}{
+		{"unknown character set", NewDCS(CharacterSet(3), MessageClassNone)},
+		{"unknown message class", NewDCS(CharacterSetGSM, MessageClass(5))},
+		{"discarded UCS-2 message waiting", NewMessageWaitingDCS(CharacterSetUCS2, false, IndicationFax, true)},
+		{"8-bit message waiting", NewMessageWaitingDCS(CharacterSet8Bit, true, IndicationFax, true)},
+		{"unknown indication type", NewMessageWaitingDCS(CharacterSetGSM, true, IndicationType(4), true)},
+		{"compressed message waiting", compressedWaiting},
+		{"message class without a class", NewMessageClassDCS(CharacterSetGSM, MessageClassNone)},
+		{"message class UCS-2", NewMessageClassDCS(CharacterSetUCS2, MessageClass0)},
+	}
+
+	for _, tt := range TestDCSMarshalReturnsErrorForInvalidCombinations {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.dcs.Marshal()
+			assert.Equal(t, ErrInvalidDCS, err)
+		})
+	}
+}
+
+func TestDCSUnmarshal(t *testing.T) {
+	var TestDCSUnmarshal = []struct {
+		name                 string
+		value                byte
+		expectedGroup        DataCodingGroup
+		expectedCharacterSet CharacterSet
+		expectedMessageClass MessageClass
+	}{
+		// reserved bits are ignored
+		{"message waiting with reserved bit", 0xC4, DataCodingGroupMessageWaitingDiscard, CharacterSetGSM, MessageClassNone},
+		{"message class with reserved bit", 0xF8, DataCodingGroupMessageClass, CharacterSetGSM, MessageClass0},
+		{"automatic deletion with class", 0x52, DataCodingGroupAutomaticDeletion, CharacterSetGSM, MessageClass2},
+	}
+
+	for _, tt := range TestDCSUnmarshal {
+		t.Run(tt.name, func(t *testing.T) {
+			var dcs DCS
+			assert.NoError(t, dcs.Unmarshal(tt.value))
+			assert.Equal(t, tt.expectedGroup, dcs.GetGroup())
+			assert.Equal(t, tt.expectedCharacterSet, dcs.GetCharacterSet())
+			assert.Equal(t, tt.expectedMessageClass, dcs.GetMessageClass())
+		})
+	}
+}
+
+func TestDCSUnmarshalReturnsErrorForReservedValues(t *testing.T) {
+	for _, value := range []byte{0x0C, 0x4C, 0x80, 0xBF} {
+		var dcs DCS
+		assert.Equal(t, ErrUnsupportedDataCoding, dcs.Unmarshal(value))
+	}
+}
+
+func TestDCSSetAutomaticDeletion(t *testing.T) {
+	dcs := NewDCS(CharacterSetGSM, MessageClassNone)
+	dcs.SetAutomaticDeletion(true)
+	assert.True(t, dcs.GetAutomaticDeletion())
+	dcs.SetAutomaticDeletion(false)
+	assert.False(t, dcs.GetAutomaticDeletion())
+	assert.Equal(t, DataCodingGroupGeneral, dcs.GetGroup())
+
+	// only the general coding groups support automatic deletion
+	waiting := NewMessageWaitingDCS(CharacterSetGSM, true, IndicationVoicemail, true)
+	waiting.SetAutomaticDeletion(true)
+	assert.False(t, waiting.GetAutomaticDeletion())
+}
+
+func TestUnmarshalUserDataReturnsErrorForCompressedData(t *testing.T) {
+	dcs := NewDCS(CharacterSetGSM, MessageClassNone)
+	dcs.SetCompressed(true)
+
+	_, err := unmarshalUserData("from", "to", dcs, false, 1, []byte{0x00})
+
+	assert.Equal(t, ErrUnsupportedDataCoding, err)
+}
@@ -0,0 +1,162 @@
+package gosms
+
+import "time"
+
+// SMSDeliver is an SMS-DELIVER TPDU, which carries a message from an SMSC to a mobile station
+type SMSDeliver struct {
+	sms                SMS
+	smsc               Address
+	originator         Address
+	timestamp          time.Time
+	protocolIdentifier byte
+	dataCoding         DCS
+	udh                UDH
+	moreMessagesToSend bool
+	statusReport       bool
+	replyPath          bool
+}
+
+// GetSMS returns the decoded SMS, which can be passed to a Reassembler
+func (d *SMSDeliver) GetSMS() SMS {
+	return d.sms
+}
+
+// GetSMSC returns the address of the SMSC, if the PDU included one
+func (d *SMSDeliver) GetSMSC() Address {
+	return d.smsc
+}
+
+// GetOriginator returns the TP-OA of the SMSDeliver
+func (d *SMSDeliver) GetOriginator() Address {
+	return d.originator
+}
+
+// GetTimestamp returns the TP-SCTS of the SMSDeliver, in the time zone of the SMSC
+func (d *SMSDeliver) GetTimestamp() time.Time {
+	return d.timestamp
+}
+
+// GetProtocolIdentifier returns the TP-PID of the SMSDeliver
+func (d *SMSDeliver) GetProtocolIdentifier() byte {
+	return d.protocolIdentifier
+}
+
+// GetDataCoding returns the TP-DCS of the SMSDeliver
+func (d *SMSDeliver) GetDataCoding() DCS {
+	return d.dataCoding
+}
+
+// GetUDH returns the parsed UDH of the SMSDeliver, which is empty if it has none
+func (d *SMSDeliver) GetUDH() UDH {
+	return d.udh
+}
+
+// GetMoreMessagesToSend returns true if the SMSC has more messages waiting for the mobile station
+func (d *SMSDeliver) GetMoreMessagesToSend() bool {
+	return d.moreMessagesToSend
+}
+
+// GetStatusReport returns the TP-SRI of the SMSDeliver
+func (d *SMSDeliver) GetStatusReport() bool {
+	return d.statusReport
+}
+
+// GetReplyPath returns the TP-RP of the SMSDeliver
+func (d *SMSDeliver) GetReplyPath() bool {
+	return d.replyPath
+}
+
+// UnmarshalPDU decodes an SMSDeliver from a PDU which starts with the SMSC
+// address, as read from a modem in PDU mode
+func (d *SMSDeliver) UnmarshalPDU(data []byte) error {
+	smsc, length, err := unmarshalSMSCAddress(data)
+	if err != nil {
+		return err
+	}
+	if err := d.Unmarshal(data[length:]); err != nil {
+		return err
+	}
+	d.smsc = smsc
+	return nil
+}
+
+// Unmarshal decodes an SMSDeliver from a TPDU
+func (d *SMSDeliver) Unmarshal(data []byte) error {
+	if len(data) < 1 || MessageType(data[0]&messageTypeMask) != MessageTypeDeliver {
+		return ErrInvalidTPDU
+	}
+	firstOctet := data[0]
+
+	originator, length, err := unmarshalTPAddress(data[1:])
+	if err != nil {
+		return err
+	}
+	idx := 1 + length
+	if idx+2+timestampLength >= len(data) {
+		return ErrInvalidTPDU
+	}
+	protocolIdentifier := data[idx]
+	var dataCoding DCS
+	if err := dataCoding.Unmarshal(data[idx+1]); err != nil {
+		return err
+	}
+	timestamp, err := decodeTimestamp(data[idx+2:])
+	if err != nil {
+		return err
+	}
+	idx += 2 + timestampLength
+
+	hasUDH := firstOctet&userDataHeaderMask != 0
+	sms, err := unmarshalUserData(originator.value, "", dataCoding, hasUDH, int(data[idx]), data[idx+1:])
+	if err != nil {
+		return err
+	}
+
+	var udh UDH
+	if hasUDH {
+		udh.Unmarshal([]byte(sms.udh))
+	}
+
+	*d = SMSDeliver{
+		sms:                sms,
+		originator:         originator,
+		timestamp:          timestamp,
+		protocolIdentifier: protocolIdentifier,
+		dataCoding:         dataCoding,
+		udh:                udh,
+		// the bit is set when there are no more messages to send
+		moreMessagesToSend: firstOctet&moreMessagesToSendMask == 0,
+		statusReport:       firstOctet&statusReportMask != 0,
+		replyPath:          firstOctet&replyPathMask != 0,
+	}
+	return nil
+}
+
+// unmarshalSMSCAddress decodes the SMSC address at the start of a PDU, whose
+// length is given in octets rather than semi-octets, and returns the number of
+// bytes it takes up
+func unmarshalSMSCAddress(data []byte) (Address, int, error) {
+	if len(data) < 1 {
+		return Address{}, 0, ErrInvalidTPDU
+	}
+	length := int(data[0])
+	if length == 0 {
+		return Address{}, 1, nil
+	}
+	if length+1 > len(data) {
+		return Address{}, 0, ErrInvalidTPDU
+	}
+
+	// convert the length to semi-octets, dropping a trailing filler
+	semiOctets := (length - 1) * 2
+	if semiOctets > 0 && data[length]>>uint(semiOctetBits) == semiOctetFiller {
+		semiOctets--
+	}
+	field := append([]byte{byte(semiOctets)}, data[1:length+1]...)
+
+	smsc, _, err := unmarshalTPAddress(field)
+	if err != nil {
+		return Address{}, 0, err
+	}
+	return smsc, length + 1, nil
+}
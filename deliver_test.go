@@ -0,0 +1,173 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// marshalDeliver builds an SMS-DELIVER TPDU for an SMS generated by a Splitter
+func marshalDeliver(t *testing.T, sms SMS, originator string, timestamp time.Time) []byte {
+	address, err := ParseAddress(originator, "")
+	assert.NoError(t, err)
+	originatorField, err := address.MarshalTPAddress()
+	assert.NoError(t, err)
+	dcs, err := sms.GetDataCoding()
+	assert.NoError(t, err)
+	dataCoding, err := dcs.Marshal()
+	assert.NoError(t, err)
+	userDataLength, userData, err := marshalUserData(sms)
+	assert.NoError(t, err)
+
+	firstOctet := byte(MessageTypeDeliver) | moreMessagesToSendMask
+	if sms.GetUDH() != "" {
+		firstOctet |= userDataHeaderMask
+	}
+
+	tpdu := append([]byte{firstOctet}, originatorField...)
+	tpdu = append(tpdu, 0x00, dataCoding)
+	tpdu = append(tpdu, encodeTimestamp(timestamp)...)
+	tpdu = append(tpdu, userDataLength)
+	return append(tpdu, userData...)
+}
+
+// this test decodes the well known "hellohello" SMS-DELIVER, including its SMSC address
+func TestSMSDeliverUnmarshalPDU(t *testing.T) {
+	pdu := []byte{
+		0x07, 0x91, 0x72, 0x83, 0x01, 0x00, 0x10, 0xF5, 0x04, 0x0B, 0xC8, 0x72, 0x38, 0x88, 0x09, 0x00,
+		0xF1, 0x00, 0x00, 0x99, 0x30, 0x92, 0x51, 0x61, 0x95, 0x80, 0x0A, 0xE8, 0x32, 0x9B, 0xFD, 0x46,
+		0x97, 0xD9, 0xEC, 0x37,
+	}
+
+	var deliver SMSDeliver
+	assert.NoError(t, deliver.UnmarshalPDU(pdu))
+
+	smsc := deliver.GetSMSC()
+	assert.Equal(t, "+27381000015", smsc.GetValue())
+	originator := deliver.GetOriginator()
+	assert.Equal(t, "27838890001", originator.GetValue())
+	assert.Equal(t, AddressTypeNational, originator.GetType())
+
+	timestamp := deliver.GetTimestamp()
+	_, offset := timestamp.Zone()
+	assert.True(t, time.Date(1999, 3, 29, 13, 16, 59, 0, time.UTC).Equal(timestamp))
+	assert.Equal(t, 2*60*60, offset)
+
+	assert.Equal(t, byte(0x00), deliver.GetProtocolIdentifier())
+	assert.Equal(t, NewDCS(CharacterSetGSM, MessageClassNone), deliver.GetDataCoding())
+	assert.False(t, deliver.GetMoreMessagesToSend())
+	assert.False(t, deliver.GetStatusReport())
+	assert.False(t, deliver.GetReplyPath())
+	assert.Equal(t, 0, len(deliver.GetUDH().InformationElements))
+
+	sms := deliver.GetSMS()
+	assert.Equal(t, "hellohello", sms.GetContent())
+	assert.Equal(t, "27838890001", sms.GetFrom())
+	assert.Equal(t, EncoderNameGSM, sms.GetEncoder().GetEncoderName())
+}
+
+func TestSMSDeliverUnmarshalPDUWithoutSMSC(t *testing.T) {
+	pdu := []byte{
+		0x00, 0x04, 0x0B, 0xC8, 0x72, 0x38, 0x88, 0x09, 0x00, 0xF1, 0x00, 0x00, 0x99, 0x30, 0x92, 0x51,
+		0x61, 0x95, 0x80, 0x0A, 0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37,
+	}
+
+	var deliver SMSDeliver
+	assert.NoError(t, deliver.UnmarshalPDU(pdu))
+
+	smsc := deliver.GetSMSC()
+	assert.Equal(t, "", smsc.GetValue())
+	sms := deliver.GetSMS()
+	assert.Equal(t, "hellohello", sms.GetContent())
+}
+
+// this test ensures that decoded parts of every encoding can be reassembled
+func TestSMSDeliverReassembly(t *testing.T) {
+	turkish, _ := NewNationalGSM(NationalLanguageTurkish, NationalLanguageTurkish)
+	timestamp := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", -4*60*60))
+
+	var TestSMSDeliverReassembly = []struct {
+		name           string
+		encoder        Encoder
+		originator     string
+		message        string
+		expectedIEs    int
+		expectedCoding CharacterSet
+	}{
+		{"GSM", NewGSM(), "+14155550123", "This message has [extended] characters and is long enough to be split into parts.", 1, CharacterSetGSM},
+		{"GSM national language", turkish, "+905321234567", "Şişli'de buluşalım mı? Saat üçte geliyorum, gecikirsem ararım.", 3, CharacterSetGSM},
+		{"UTF-16", NewUTF16(), "12345", "This message has emoji 😀 and is long enough to be split into parts.", 1, CharacterSetUCS2},
+		{"alphanumeric originator", NewGSM(), "TextNow", "This message comes from an alphanumeric sender and has to be split.", 1, CharacterSetGSM},
+	}
+
+	for _, tt := range TestSMSDeliverReassembly {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			splitter.SetMessageBytes(40)
+			smsParts, err := splitter.Split(tt.originator, []string{"+14155550199"}, tt.message)
+			assert.NoError(t, err)
+			assert.True(t, len(smsParts) > 1)
+
+			reassembler := NewReassembler()
+			var message string
+			var complete bool
+
+			// deliver the parts in reverse order
+			for idx := len(smsParts) - 1; idx >= 0; idx-- {
+				var deliver SMSDeliver
+				assert.NoError(t, deliver.Unmarshal(marshalDeliver(t, smsParts[idx], tt.originator, timestamp)))
+
+				dataCoding := deliver.GetDataCoding()
+				assert.Equal(t, tt.expectedCoding, dataCoding.GetCharacterSet())
+				assert.Equal(t, tt.expectedIEs, len(deliver.GetUDH().InformationElements))
+				assert.True(t, timestamp.Equal(deliver.GetTimestamp()))
+				originator := deliver.GetOriginator()
+				assert.Equal(t, tt.originator, originator.GetValue())
+
+				sms := deliver.GetSMS()
+				assert.Equal(t, smsParts[idx].GetContent(), sms.GetContent())
+				assert.Equal(t, smsParts[idx].GetUDH(), sms.GetUDH())
+
+				message, complete, err = reassembler.Add(sms)
+				assert.NoError(t, err)
+			}
+			assert.True(t, complete)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestSMSDeliverUnmarshalReturnsErrorForMalformedTPDU(t *testing.T) {
+	var TestSMSDeliverUnmarshalReturnsErrorForMalformedTPDU = []struct {
+		name string
+		tpdu []byte
+	}{
+		{"empty", []byte{}},
+		{"SMS-SUBMIT", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00, 0x00}},
+		{"truncated address", []byte{0x04, 0x0B, 0xC8, 0x72, 0x38}},
+		{"truncated timestamp", []byte{0x04, 0x0B, 0xC8, 0x72, 0x38, 0x88, 0x09, 0x00, 0xF1, 0x00, 0x00, 0x99, 0x30, 0x92}},
+		{"missing user data length", []byte{0x04, 0x0B, 0xC8, 0x72, 0x38, 0x88, 0x09, 0x00, 0xF1, 0x00, 0x00, 0x99, 0x30, 0x92, 0x51, 0x61, 0x95, 0x80}},
+		{"user data shorter than its length", []byte{0x04, 0x0B, 0xC8, 0x72, 0x38, 0x88, 0x09, 0x00, 0xF1, 0x00, 0x00, 0x99, 0x30, 0x92, 0x51, 0x61, 0x95, 0x80, 0x0A, 0xE8}},
+	}
+
+	for _, tt := range TestSMSDeliverUnmarshalReturnsErrorForMalformedTPDU {
+		t.Run(tt.name, func(t *testing.T) {
+			var deliver SMSDeliver
+			assert.Equal(t, ErrInvalidTPDU, deliver.Unmarshal(tt.tpdu))
+		})
+	}
+}
+
+func TestSMSDeliverUnmarshalPDUReturnsErrorForTruncatedSMSC(t *testing.T) {
+	var TestSMSDeliverUnmarshalPDUReturnsErrorForTruncatedSMSC = [][]byte{
+		{},
+		{0x07, 0x91, 0x72, 0x83},
+	}
+
+	for _, pdu := range TestSMSDeliverUnmarshalPDUReturnsErrorForTruncatedSMSC {
+		var deliver SMSDeliver
+		assert.Equal(t, ErrInvalidTPDU, deliver.UnmarshalPDU(pdu))
+	}
+}
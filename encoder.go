@@ -2,12 +2,16 @@ package gosms
 
 import (
 	"errors"
+	"unicode"
 	"unicode/utf16"
 )
 
 // ErrNotEncodable indicates that the supplied string or character cannot be encoded with the given encoder
 var ErrNotEncodable = errors.New("one or more characters cannot be encoded with the given encoder")
 
+// ErrNotDecodable indicates that the supplied bytes cannot be decoded with the given encoder
+var ErrNotDecodable = errors.New("the supplied bytes cannot be decoded with the given encoder")
+
 const (
 	// EncoderNameGSM is the GSM Encoder Name
 	EncoderNameGSM string = "GSM"
@@ -15,10 +19,20 @@ const (
 	// EncoderNameUTF16 is the UTF-16 Encoder Name
 	EncoderNameUTF16 string = "UTF-16"
 
-	codePointBitsGSM   int  = 7
-	codePointBitsUTF16 int  = 16
-	highSurrogateStart rune = 0xD800
-	highSurrogateEnd   rune = 0xDBFF
+	// EncoderNameUCS2 is the UCS-2 Encoder Name
+	EncoderNameUCS2 string = "UCS-2"
+
+	// EncoderNameLatin1 is the Latin-1 Encoder Name
+	EncoderNameLatin1 string = "ISO-8859-1"
+
+	codePointBitsGSM    int  = 7
+	codePointBitsUTF16  int  = 16
+	codePointBitsLatin1 int  = 8
+	highSurrogateStart  rune = 0xD800
+	highSurrogateEnd    rune = 0xDBFF
+
+	supplementaryPlaneStart rune = 0x10000
+	latin1End               rune = 0xFF
 )
 
 // Encoder encapsulates encoder specific fields
@@ -27,67 +41,227 @@ type Encoder interface {
 	GetCodePointBits() int
 	GetCodePoints(rune) (int, error)
 	CheckEncodability(string) bool
+	Encode(string) ([]byte, error)
+	Decode([]byte) (string, error)
 }
 
 // GSM implements the Encoder interface
-type GSM struct{}
+type GSM struct {
+	lockingShift NationalLanguage
+	singleShift  NationalLanguage
+}
 
 // NewGSM returns a new gsm
 func NewGSM() Encoder {
 	return &GSM{}
 }
 
+// NewNationalGSM returns a new gsm which uses the national language locking shift
+// table in place of the default alphabet, and the national language single shift
+// table in place of the extension table. NationalLanguageDefault keeps the default table.
+func NewNationalGSM(lockingShift NationalLanguage, singleShift NationalLanguage) (Encoder, error) {
+	if _, ok := gsmLockingShiftTables[lockingShift]; !ok {
+		return nil, ErrUnsupportedLanguage
+	}
+	if _, ok := gsmSingleShiftTables[singleShift]; !ok {
+		return nil, ErrUnsupportedLanguage
+	}
+	return &GSM{lockingShift: lockingShift, singleShift: singleShift}, nil
+}
+
 // GetCodePointBits returns the number of bits that make a single GSM code point
 func (s *GSM) GetCodePointBits() int {
 	return codePointBitsGSM
 }
 
-// GetEncoderName returns the GSM encoder name
+// GetEncoderName returns the GSM encoder name, including any national language tables
 func (s *GSM) GetEncoderName() string {
+	switch {
+	case s.lockingShift != NationalLanguageDefault && s.singleShift != NationalLanguageDefault:
+		return EncoderNameGSM + " (" + s.lockingShift.String() + " locking shift, " + s.singleShift.String() + " single shift)"
+	case s.lockingShift != NationalLanguageDefault:
+		return EncoderNameGSM + " (" + s.lockingShift.String() + " locking shift)"
+	case s.singleShift != NationalLanguageDefault:
+		return EncoderNameGSM + " (" + s.singleShift.String() + " single shift)"
+	}
 	return EncoderNameGSM
 }
 
+// GetLockingShift returns the national language of the table replacing the default alphabet
+func (s *GSM) GetLockingShift() NationalLanguage {
+	return s.lockingShift
+}
+
+// GetSingleShift returns the national language of the table replacing the extension table
+func (s *GSM) GetSingleShift() NationalLanguage {
+	return s.singleShift
+}
+
 // GetCodePoints returns the number of code points used to represent char in GSM
 func (s *GSM) GetCodePoints(char rune) (int, error) {
-	codePoints, isGSM := gsmCodePoints[char]
-	if !isGSM {
-		return 0, ErrNotEncodable
+	if s.lockingShift == NationalLanguageDefault && s.singleShift == NationalLanguageDefault {
+		codePoints, isGSM := gsmCodePoints[char]
+		if !isGSM {
+			return 0, ErrNotEncodable
+		}
+		return codePoints, nil
+	}
+
+	if _, ok := gsmLockingShiftSeptets[s.lockingShift][char]; ok {
+		return 1, nil
 	}
-	return codePoints, nil
+	if _, ok := gsmSingleShiftSeptets[s.singleShift][char]; ok {
+		return 2, nil
+	}
+	return 0, ErrNotEncodable
 }
 
 // CheckEncodability returns true if str is encodable and false otherwise
 func (s *GSM) CheckEncodability(str string) bool {
 	runeSet := []rune(str)
 	for _, char := range runeSet {
-        _, err := s.GetCodePoints(char)
-        if err != nil {
-            return false
-        }
-    }
-    return true
+		_, err := s.GetCodePoints(char)
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeSeptets returns str as unpacked GSM septets, one septet per byte.
+// Characters from the extension table are preceded by an escape septet.
+func (s *GSM) EncodeSeptets(str string) ([]byte, error) {
+	var septets []byte
+
+	for _, char := range str {
+		if septet, ok := gsmLockingShiftSeptets[s.lockingShift][char]; ok {
+			septets = append(septets, septet)
+			continue
+		}
+		if septet, ok := gsmSingleShiftSeptets[s.singleShift][char]; ok {
+			septets = append(septets, gsmEscape, septet)
+			continue
+		}
+		return nil, ErrNotEncodable
+	}
+	return septets, nil
+}
+
+// DecodeSeptets returns the string represented by unpacked GSM septets
+func (s *GSM) DecodeSeptets(septets []byte) (string, error) {
+	var runes []rune
+	alphabet := gsmLockingShiftTables[s.lockingShift]
+	extension := gsmSingleShiftTables[s.singleShift]
+
+	for idx := 0; idx < len(septets); idx++ {
+		septet := septets[idx] & gsmSeptetMask
+		if septet != gsmEscape {
+			if alphabet[septet] == 0 {
+				return "", ErrNotDecodable
+			}
+			runes = append(runes, alphabet[septet])
+			continue
+		}
+
+		// an escape must be followed by a septet from the extension table
+		idx++
+		if idx >= len(septets) {
+			return "", ErrNotDecodable
+		}
+		septet = septets[idx] & gsmSeptetMask
+		char := extension[septet]
+		if char == 0 {
+			// unknown extensions are displayed using the default alphabet
+			char = alphabet[septet]
+		}
+		if char == 0 {
+			return "", ErrNotDecodable
+		}
+		runes = append(runes, char)
+	}
+	return string(runes), nil
+}
+
+// Encode returns str as packed GSM septets. As described in 3GPP 23.038, 7
+// spare bits in the last octet are filled with a carriage return rather than
+// zeros, which would read as '@', and a trailing carriage return which ends on
+// an octet boundary is doubled so that it is not taken for padding.
+func (s *GSM) Encode(str string) ([]byte, error) {
+	septets, err := s.EncodeSeptets(str)
+	if err != nil {
+		return nil, err
+	}
+	switch len(septets) % byteLength {
+	case byteLength - 1:
+		septets = append(septets, gsmCarriageReturn)
+	case 0:
+		if len(septets) > 0 && septets[len(septets)-1] == gsmCarriageReturn {
+			septets = append(septets, gsmCarriageReturn)
+		}
+	}
+	return PackSeptets(septets, 0), nil
+}
+
+// Decode returns the string represented by packed GSM septets. When the final
+// septet exactly fills the last octet and is a carriage return, it is treated
+// as padding. Use UnpackSeptets and DecodeSeptets when the septet count is known.
+func (s *GSM) Decode(data []byte) (string, error) {
+	count := (len(data) * byteLength) / septetBits
+	septets := UnpackSeptets(data, 0, count)
+	if count > 0 && (len(data)*byteLength)%septetBits == 0 && septets[count-1] == gsmCarriageReturn {
+		septets = septets[:count-1]
+	}
+	return s.DecodeSeptets(septets)
 }
 
 // UTF16 implements the Encoder interface
-type UTF16 struct{}
+type UTF16 struct {
+	ucs2        bool
+	replacement rune
+}
 
 // NewUTF16 returns a new UTF16
 func NewUTF16() Encoder {
 	return &UTF16{}
 }
 
+// NewUCS2 returns a new UTF16 which rejects characters outside of the basic
+// multilingual plane, for SMSCs which cannot handle surrogate pairs
+func NewUCS2() Encoder {
+	return &UTF16{ucs2: true}
+}
+
+// NewUCS2WithReplacement returns a new UTF16 which replaces characters outside
+// of the basic multilingual plane with replacement. If replacement is itself
+// outside of the basic multilingual plane, the unicode replacement character is used.
+func NewUCS2WithReplacement(replacement rune) Encoder {
+	if !isBasicMultilingualPlane(replacement) {
+		replacement = unicode.ReplacementChar
+	}
+	return &UTF16{ucs2: true, replacement: replacement}
+}
+
 // GetCodePointBits returns the number of bits that make a single UTF-16 code point
 func (s *UTF16) GetCodePointBits() int {
 	return codePointBitsUTF16
 }
 
-// GetEncoderName returns the UTF-16 encoder name
+// GetEncoderName returns the UTF-16 or UCS-2 encoder name
 func (s *UTF16) GetEncoderName() string {
+	if s.ucs2 {
+		return EncoderNameUCS2
+	}
 	return EncoderNameUTF16
 }
 
 // GetCodePoints returns the number of code points used to represent char in UTF-16
 func (s *UTF16) GetCodePoints(char rune) (int, error) {
+	if s.ucs2 {
+		if _, err := s.toUCS2(char); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
 	utf16Rune, _ := utf16.EncodeRune(char)
 	if utf16Rune >= highSurrogateStart && utf16Rune <= highSurrogateEnd {
 		return 2, nil
@@ -97,6 +271,137 @@ func (s *UTF16) GetCodePoints(char rune) (int, error) {
 
 // CheckEncodability returns true if str is encodable and false otherwise
 func (s *UTF16) CheckEncodability(str string) bool {
+	if s.ucs2 {
+		for _, char := range str {
+			if _, err := s.toUCS2(char); err != nil {
+				return false
+			}
+		}
+	}
 	// golang strings are all UTF-8, so all characters are in the unicode character set
-    return true
+	return true
+}
+
+// Encode returns str as big-endian UTF-16 octets. In UCS-2 mode characters
+// outside of the basic multilingual plane are replaced or rejected.
+func (s *UTF16) Encode(str string) ([]byte, error) {
+	runes := []rune(str)
+	if s.ucs2 {
+		for idx, char := range runes {
+			ucs2Char, err := s.toUCS2(char)
+			if err != nil {
+				return nil, err
+			}
+			runes[idx] = ucs2Char
+		}
+	}
+
+	codeUnits := utf16.Encode(runes)
+	data := make([]byte, 0, len(codeUnits)*2)
+	for _, codeUnit := range codeUnits {
+		data = append(data, byte(codeUnit>>byteLength), byte(codeUnit))
+	}
+	return data, nil
+}
+
+// Decode returns the string represented by big-endian UTF-16 octets. Unpaired
+// surrogates are decoded as the unicode replacement character. In UCS-2 mode
+// surrogates are not combined, and are replaced or rejected.
+func (s *UTF16) Decode(data []byte) (string, error) {
+	if len(data)%2 != 0 {
+		return "", ErrNotDecodable
+	}
+	codeUnits := make([]uint16, 0, len(data)/2)
+	for idx := 0; idx < len(data); idx += 2 {
+		codeUnits = append(codeUnits, uint16(data[idx])<<byteLength|uint16(data[idx+1]))
+	}
+	if !s.ucs2 {
+		return string(utf16.Decode(codeUnits)), nil
+	}
+
+	runes := make([]rune, 0, len(codeUnits))
+	for _, codeUnit := range codeUnits {
+		char := rune(codeUnit)
+		if utf16.IsSurrogate(char) {
+			if s.replacement == 0 {
+				return "", ErrNotDecodable
+			}
+			char = s.replacement
+		}
+		runes = append(runes, char)
+	}
+	return string(runes), nil
+}
+
+// toUCS2 returns the character used to represent char in UCS-2
+func (s *UTF16) toUCS2(char rune) (rune, error) {
+	if isBasicMultilingualPlane(char) {
+		return char, nil
+	}
+	if s.replacement == 0 {
+		return 0, ErrNotEncodable
+	}
+	return s.replacement, nil
+}
+
+// isBasicMultilingualPlane returns true if char can be represented by a single UTF-16 code unit
+func isBasicMultilingualPlane(char rune) bool {
+	return char >= 0 && char < supplementaryPlaneStart && !utf16.IsSurrogate(char)
+}
+
+// Latin1 implements the Encoder interface for 8-bit ISO-8859-1
+type Latin1 struct{}
+
+// NewLatin1 returns a new Latin1
+func NewLatin1() Encoder {
+	return &Latin1{}
+}
+
+// GetCodePointBits returns the number of bits that make a single Latin-1 code point
+func (s *Latin1) GetCodePointBits() int {
+	return codePointBitsLatin1
+}
+
+// GetEncoderName returns the Latin-1 encoder name
+func (s *Latin1) GetEncoderName() string {
+	return EncoderNameLatin1
+}
+
+// GetCodePoints returns the number of code points used to represent char in Latin-1
+func (s *Latin1) GetCodePoints(char rune) (int, error) {
+	if char < 0 || char > latin1End {
+		return 0, ErrNotEncodable
+	}
+	return 1, nil
+}
+
+// CheckEncodability returns true if str is encodable and false otherwise
+func (s *Latin1) CheckEncodability(str string) bool {
+	for _, char := range str {
+		if _, err := s.GetCodePoints(char); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode returns str as Latin-1 octets
+func (s *Latin1) Encode(str string) ([]byte, error) {
+	data := make([]byte, 0, len(str))
+	for _, char := range str {
+		if _, err := s.GetCodePoints(char); err != nil {
+			return nil, err
+		}
+		data = append(data, byte(char))
+	}
+	return data, nil
+}
+
+// Decode returns the string represented by Latin-1 octets
+func (s *Latin1) Decode(data []byte) (string, error) {
+	runes := make([]rune, 0, len(data))
+	for _, octet := range data {
+		runes = append(runes, rune(octet))
+	}
+	return string(runes), nil
 }
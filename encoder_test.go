@@ -1,4 +1,3 @@
-
 package gosms
 
 import (
@@ -62,3 +61,231 @@ func TestUTF16(t *testing.T) {
 	encodable := encoder.CheckEncodability("你")
 	assert.True(t, encodable)
 }
+
+// this test ensures that GSM strings are encoded into packed septets and decoded back
+func TestGSMEncodeDecode(t *testing.T) {
+	encoder := NewGSM()
+
+	var TestGSMEncodeDecode = []struct {
+		name     string
+		message  string
+		septets  []byte
+		expected []byte
+	}{
+		{
+			"empty message",
+			"",
+			nil,
+			[]byte{},
+		},
+		{
+			"default alphabet",
+			"hellohello",
+			[]byte("hellohello"),
+			[]byte{0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37},
+		},
+		{
+			"characters outside of ascii",
+			"@£Δ",
+			[]byte{0x00, 0x01, 0x10},
+			[]byte{0x80, 0x00, 0x04},
+		},
+		{
+			"extension table characters are escaped",
+			"[€{",
+			[]byte{0x1B, 0x3C, 0x1B, 0x65, 0x1B, 0x28},
+			[]byte{0x1B, 0xDE, 0xA6, 0xBC, 0x41, 0x01},
+		},
+	}
+
+	for _, tt := range TestGSMEncodeDecode {
+		septets, err := encoder.(*GSM).EncodeSeptets(tt.message)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.septets, septets, tt.name)
+
+		encoded, err := encoder.Encode(tt.message)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expected, encoded, tt.name)
+
+		decoded, err := encoder.Decode(encoded)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.message, decoded, tt.name)
+	}
+
+	// check that a trailing '@' is not taken for padding, which is a carriage return
+	var TestGSMEncodeDecodePadding = []struct {
+		name            string
+		message         string
+		expected        []byte
+		expectedDecoded string
+	}{
+		{
+			"8 septets ending in @",
+			"1234567@",
+			[]byte{0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x00},
+			"1234567@",
+		},
+		{
+			"16 septets ending in @",
+			"123456712345678@",
+			[]byte{0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x62, 0xB2, 0x19, 0xAD, 0x66, 0xBB, 0xE1, 0x00},
+			"123456712345678@",
+		},
+		{
+			"7 septets padded with a carriage return",
+			"1234567",
+			[]byte{0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x1A},
+			"1234567",
+		},
+		{
+			"8 septets ending in a carriage return, which is doubled",
+			"1234567\r",
+			[]byte{0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x1A, 0x0D},
+			"1234567\r\r",
+		},
+	}
+
+	for _, tt := range TestGSMEncodeDecodePadding {
+		encoded, err := encoder.Encode(tt.message)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expected, encoded, tt.name)
+
+		decoded, err := encoder.Decode(encoded)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expectedDecoded, decoded, tt.name)
+	}
+
+	// check that every GSM character survives a round trip
+	for char, expectedCodePoints := range gsmCodePoints {
+		septets, err := encoder.(*GSM).EncodeSeptets(string(char))
+		assert.Nil(t, err)
+		assert.Equal(t, expectedCodePoints, len(septets))
+
+		decoded, err := encoder.(*GSM).DecodeSeptets(septets)
+		assert.Nil(t, err)
+		assert.Equal(t, string(char), decoded)
+	}
+
+	// check that non-GSM characters cannot be encoded
+	encoded, err := encoder.Encode("你")
+	assert.Nil(t, encoded)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+
+	// check that a trailing escape cannot be decoded
+	decoded, err := encoder.(*GSM).DecodeSeptets([]byte{0x41, 0x1B})
+	assert.Equal(t, "", decoded)
+	assert.EqualError(t, err, ErrNotDecodable.Error())
+
+	// check that unknown extensions fall back to the default alphabet
+	decoded, err = encoder.(*GSM).DecodeSeptets([]byte{0x1B, 0x41})
+	assert.Nil(t, err)
+	assert.Equal(t, "A", decoded)
+}
+
+// this test ensures that UTF16 strings are encoded into big-endian octets and decoded back
+func TestUTF16EncodeDecode(t *testing.T) {
+	encoder := NewUTF16()
+
+	encoded, err := encoder.Encode("A你😂")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00, 0x41, 0x4F, 0x60, 0xD8, 0x3D, 0xDE, 0x02}, encoded)
+
+	decoded, err := encoder.Decode(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, "A你😂", decoded)
+
+	// check that an odd number of octets cannot be decoded
+	decoded, err = encoder.Decode([]byte{0x00, 0x41, 0x00})
+	assert.Equal(t, "", decoded)
+	assert.EqualError(t, err, ErrNotDecodable.Error())
+}
+
+// this test ensures that UCS-2 encoders reject or replace characters outside of the basic multilingual plane
+func TestUCS2(t *testing.T) {
+	strict := NewUCS2()
+	replacing := NewUCS2WithReplacement('?')
+
+	// check that the right constants are returned from GetCodePointBits and GetEncoderName
+	assert.Equal(t, codePointBitsUTF16, strict.GetCodePointBits())
+	assert.Equal(t, EncoderNameUCS2, strict.GetEncoderName())
+	assert.Equal(t, EncoderNameUCS2, replacing.GetEncoderName())
+
+	// check that basic multilingual plane characters are encoded as in UTF-16
+	for _, encoder := range []Encoder{strict, replacing} {
+		codePoints, err := encoder.GetCodePoints('你')
+		assert.Equal(t, 1, codePoints)
+		assert.Nil(t, err)
+		assert.True(t, encoder.CheckEncodability("A你"))
+
+		encoded, err := encoder.Encode("A你")
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x00, 0x41, 0x4F, 0x60}, encoded)
+	}
+
+	// check that supplementary plane characters are rejected in strict mode
+	codePoints, err := strict.GetCodePoints('😂')
+	assert.Zero(t, codePoints)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+	assert.False(t, strict.CheckEncodability("A😂"))
+
+	encoded, err := strict.Encode("A😂")
+	assert.Nil(t, encoded)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+
+	decoded, err := strict.Decode([]byte{0xD8, 0x3D, 0xDE, 0x02})
+	assert.Equal(t, "", decoded)
+	assert.EqualError(t, err, ErrNotDecodable.Error())
+
+	// check that supplementary plane characters are replaced in replacement mode
+	codePoints, err = replacing.GetCodePoints('😂')
+	assert.Equal(t, 1, codePoints)
+	assert.Nil(t, err)
+	assert.True(t, replacing.CheckEncodability("A😂"))
+
+	encoded, err = replacing.Encode("A😂")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00, 0x41, 0x00, 0x3F}, encoded)
+
+	decoded, err = replacing.Decode([]byte{0x00, 0x41, 0xD8, 0x3D, 0xDE, 0x02})
+	assert.Nil(t, err)
+	assert.Equal(t, "A??", decoded)
+
+	// check that supplementary plane replacements fall back to the replacement character
+	encoded, err = NewUCS2WithReplacement('😂').Encode("😂")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xFF, 0xFD}, encoded)
+}
+
+// this test ensures that all Latin1 related functions work as expected
+func TestLatin1(t *testing.T) {
+	encoder := NewLatin1()
+
+	// check that the right constants are returned from GetCodePointBits and GetEncoderName
+	assert.Equal(t, codePointBitsLatin1, encoder.GetCodePointBits())
+	assert.Equal(t, EncoderNameLatin1, encoder.GetEncoderName())
+
+	// check that code points are reported accurately for Latin-1 characters
+	codePoints, err := encoder.GetCodePoints('þ')
+	assert.Equal(t, 1, codePoints)
+	assert.Nil(t, err)
+
+	codePoints, err = encoder.GetCodePoints('€')
+	assert.Zero(t, codePoints)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+
+	assert.True(t, encoder.CheckEncodability("Þorn ÿ"))
+	assert.False(t, encoder.CheckEncodability("5 €"))
+
+	// check that Latin-1 strings are encoded into single octets and decoded back
+	encoded, err := encoder.Encode("Aþÿ")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x41, 0xFE, 0xFF}, encoded)
+
+	decoded, err := encoder.Decode(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, "Aþÿ", decoded)
+
+	encoded, err = encoder.Encode("5 €")
+	assert.Nil(t, encoded)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+}
@@ -0,0 +1,225 @@
+package gosms
+
+import "unicode"
+
+// graphemeProperty is a simplified Grapheme_Cluster_Break property from UAX #29
+type graphemeProperty int
+
+const (
+	graphemeOther graphemeProperty = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRegionalIndicator
+	graphemePrepend
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+)
+
+const (
+	zeroWidthNonJoiner rune = 0x200C
+	zeroWidthJoiner    rune = 0x200D
+	hangulSyllableBase rune = 0xAC00
+	hangulSyllableEnd  rune = 0xD7A3
+	hangulTCount       rune = 28
+)
+
+// emojiModifiers are the Fitzpatrick skin tone modifiers
+var emojiModifiers = &unicode.RangeTable{
+	R32: []unicode.Range32{
+		{Lo: 0x1F3FB, Hi: 0x1F3FF, Stride: 1},
+	},
+}
+
+// tagCharacters are used to build emoji tag sequences, such as subdivision flags
+var tagCharacters = &unicode.RangeTable{
+	R32: []unicode.Range32{
+		{Lo: 0xE0020, Hi: 0xE007F, Stride: 1},
+	},
+}
+
+// regionalIndicators pair up into flags
+var regionalIndicators = &unicode.RangeTable{
+	R32: []unicode.Range32{
+		{Lo: 0x1F1E6, Hi: 0x1F1FF, Stride: 1},
+	},
+}
+
+// prependCharacters are the characters which attach to the character after them
+var prependCharacters = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0600, Hi: 0x0605, Stride: 1},
+		{Lo: 0x06DD, Hi: 0x06DD, Stride: 1},
+		{Lo: 0x070F, Hi: 0x070F, Stride: 1},
+		{Lo: 0x08E2, Hi: 0x08E2, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x110BD, Hi: 0x110BD, Stride: 1},
+		{Lo: 0x110CD, Hi: 0x110CD, Stride: 1},
+	},
+}
+
+// extendedPictographics approximates the Extended_Pictographic property, which
+// covers emoji and the symbols that can be joined into emoji sequences
+var extendedPictographics = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00A9, Hi: 0x00A9, Stride: 1},
+		{Lo: 0x00AE, Hi: 0x00AE, Stride: 1},
+		{Lo: 0x203C, Hi: 0x203C, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21A9, Hi: 0x21AA, Stride: 1},
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x2388, Hi: 0x2388, Stride: 1},
+		{Lo: 0x23CF, Hi: 0x23CF, Stride: 1},
+		{Lo: 0x23E9, Hi: 0x23F3, Stride: 1},
+		{Lo: 0x23F8, Hi: 0x23FA, Stride: 1},
+		{Lo: 0x24C2, Hi: 0x24C2, Stride: 1},
+		{Lo: 0x25AA, Hi: 0x25AB, Stride: 1},
+		{Lo: 0x25B6, Hi: 0x25B6, Stride: 1},
+		{Lo: 0x25C0, Hi: 0x25C0, Stride: 1},
+		{Lo: 0x25FB, Hi: 0x25FE, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27BF, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2B05, Hi: 0x2B07, Stride: 1},
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+		{Lo: 0x2B50, Hi: 0x2B50, Stride: 1},
+		{Lo: 0x2B55, Hi: 0x2B55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303D, Hi: 0x303D, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F000, Hi: 0x1F1E5, Stride: 1},
+		{Lo: 0x1F200, Hi: 0x1F3FA, Stride: 1},
+		{Lo: 0x1F400, Hi: 0x1FAFF, Stride: 1},
+		{Lo: 0x1FC00, Hi: 0x1FFFD, Stride: 1},
+	},
+}
+
+// getGraphemeProperty returns the grapheme cluster break property of char
+func getGraphemeProperty(char rune) graphemeProperty {
+	switch {
+	case char == '\r':
+		return graphemeCR
+	case char == '\n':
+		return graphemeLF
+	case char == zeroWidthJoiner:
+		return graphemeZWJ
+	case char == zeroWidthNonJoiner:
+		return graphemeExtend
+	case unicode.In(char, tagCharacters, emojiModifiers, unicode.Mn, unicode.Me):
+		return graphemeExtend
+	case unicode.In(char, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		if unicode.Is(prependCharacters, char) {
+			return graphemePrepend
+		}
+		return graphemeControl
+	case unicode.Is(regionalIndicators, char):
+		return graphemeRegionalIndicator
+	case unicode.Is(prependCharacters, char):
+		return graphemePrepend
+	case unicode.Is(unicode.Mc, char):
+		return graphemeSpacingMark
+	case char >= 0x1100 && char <= 0x115F, char >= 0xA960 && char <= 0xA97C:
+		return graphemeL
+	case char >= 0x1160 && char <= 0x11A7, char >= 0xD7B0 && char <= 0xD7C6:
+		return graphemeV
+	case char >= 0x11A8 && char <= 0x11FF, char >= 0xD7CB && char <= 0xD7FB:
+		return graphemeT
+	case char >= hangulSyllableBase && char <= hangulSyllableEnd:
+		if (char-hangulSyllableBase)%hangulTCount == 0 {
+			return graphemeLV
+		}
+		return graphemeLVT
+	}
+	return graphemeOther
+}
+
+// isExtendedPictographic returns true if char is an emoji or pictographic symbol
+func isExtendedPictographic(char rune) bool {
+	return unicode.Is(extendedPictographics, char)
+}
+
+// graphemeClusters splits message into extended grapheme clusters as described
+// in UAX #29. Emoji ZWJ sequences, flags, skin tone modifiers and combining marks
+// stay attached to the characters they modify.
+func graphemeClusters(message []rune) [][]rune {
+	var clusters [][]rune
+	var regionalIndicatorCount int // consecutive regional indicators before char
+	var emojiSequence bool         // an emoji followed by Extend characters precedes char
+	var emojiJoined bool           // an emoji sequence followed by a ZWJ precedes char
+
+	start := 0
+	for idx := 1; idx <= len(message); idx++ {
+		prev := message[idx-1]
+		prevProperty := getGraphemeProperty(prev)
+
+		// track the state needed by the emoji and regional indicator rules
+		emojiJoined = emojiSequence && prevProperty == graphemeZWJ
+		if isExtendedPictographic(prev) {
+			emojiSequence = true
+		} else if prevProperty != graphemeExtend {
+			emojiSequence = false
+		}
+		if prevProperty == graphemeRegionalIndicator {
+			regionalIndicatorCount++
+		} else {
+			regionalIndicatorCount = 0
+		}
+
+		if idx < len(message) && !isGraphemeBreak(prevProperty, message[idx], regionalIndicatorCount, emojiJoined) {
+			continue
+		}
+		clusters = append(clusters, message[start:idx])
+		start = idx
+	}
+	return clusters
+}
+
+// isGraphemeBreak returns true if there is a grapheme cluster boundary between
+// a character with prevProperty and char
+func isGraphemeBreak(prevProperty graphemeProperty, char rune, regionalIndicatorCount int, emojiJoined bool) bool {
+	property := getGraphemeProperty(char)
+
+	switch {
+	// GB3: do not break within CRLF
+	case prevProperty == graphemeCR && property == graphemeLF:
+		return false
+	// GB4, GB5: break around controls
+	case prevProperty == graphemeCR, prevProperty == graphemeLF, prevProperty == graphemeControl:
+		return true
+	case property == graphemeCR, property == graphemeLF, property == graphemeControl:
+		return true
+	// GB6, GB7, GB8: do not break Hangul syllable sequences
+	case prevProperty == graphemeL && (property == graphemeL || property == graphemeV || property == graphemeLV || property == graphemeLVT):
+		return false
+	case (prevProperty == graphemeLV || prevProperty == graphemeV) && (property == graphemeV || property == graphemeT):
+		return false
+	case (prevProperty == graphemeLVT || prevProperty == graphemeT) && property == graphemeT:
+		return false
+	// GB9, GB9a, GB9b: do not break before extenders or spacing marks, or after prepend characters
+	case property == graphemeExtend, property == graphemeZWJ, property == graphemeSpacingMark:
+		return false
+	case prevProperty == graphemePrepend:
+		return false
+	// GB11: do not break within emoji ZWJ sequences
+	case emojiJoined && isExtendedPictographic(char):
+		return false
+	// GB12, GB13: do not break within regional indicator pairs
+	case prevProperty == graphemeRegionalIndicator && property == graphemeRegionalIndicator:
+		return regionalIndicatorCount%2 == 0
+	}
+	// GB999: otherwise, break everywhere
+	return true
+}
@@ -0,0 +1,90 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test ensures that messages are split into extended grapheme clusters
+func TestGraphemeClusters(t *testing.T) {
+	var TestGraphemeClusters = []struct {
+		name     string
+		message  string
+		expected []string
+	}{
+		{
+			"empty message",
+			"",
+			nil,
+		},
+		{
+			"plain characters",
+			"abc",
+			[]string{"a", "b", "c"},
+		},
+		{
+			"CRLF stays together",
+			"a\r\nb",
+			[]string{"a", "\r\n", "b"},
+		},
+		{
+			"combining marks attach to their base",
+			"éạ̀",
+			[]string{"é", "ạ̀"},
+		},
+		{
+			"skin tone modifiers attach to their emoji",
+			"👍🏽👍",
+			[]string{"👍🏽", "👍"},
+		},
+		{
+			"ZWJ sequences stay together",
+			"👨‍👩‍👧x",
+			[]string{"👨‍👩‍👧", "x"},
+		},
+		{
+			"variation selectors attach to their base",
+			"❤️‍🔥!",
+			[]string{"❤️‍🔥", "!"},
+		},
+		{
+			"ZWJ does not join non-emoji",
+			"a‍b",
+			[]string{"a‍", "b"},
+		},
+		{
+			"regional indicators pair into flags",
+			"🇨🇦🇺🇸🇫",
+			[]string{"🇨🇦", "🇺🇸", "🇫"},
+		},
+		{
+			"tag sequences stay together",
+			"🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F🏴",
+			[]string{"🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F", "🏴"},
+		},
+		{
+			"spacing marks attach to their base",
+			"कि",
+			[]string{"कि"},
+		},
+		{
+			"hangul jamo form syllables",
+			"각한",
+			[]string{"각", "한"},
+		},
+		{
+			"controls break clusters",
+			"́\tá",
+			[]string{"́", "\t", "á"},
+		},
+	}
+
+	for _, tt := range TestGraphemeClusters {
+		var clusters []string
+		for _, cluster := range graphemeClusters([]rune(tt.message)) {
+			clusters = append(clusters, string(cluster))
+		}
+		assert.Equal(t, tt.expected, clusters, tt.name)
+	}
+}
@@ -139,3 +139,36 @@ var gsmCodePoints = map[rune]int{
 	937:  1, // Ω
 	8364: 2, // €
 }
+
+const (
+	gsmEscape         byte = 0x1B
+	gsmCarriageReturn byte = 0x0D
+	gsmSeptetMask     byte = 0x7F
+	gsmAlphabetSize   int  = 128
+)
+
+// gsmDefaultAlphabet maps GSM 03.38 septets to their unicode characters
+var gsmDefaultAlphabet = [gsmAlphabetSize]rune{
+	'@', '£', '$', '¥', 'è', 'é', 'ù', 'ì', 'ò', 'Ç', '\n', 'Ø', 'ø', '\r', 'Å', 'å',
+	'Δ', '_', 'Φ', 'Γ', 'Λ', 'Ω', 'Π', 'Ψ', 'Σ', 'Θ', 'Ξ', 0, 'Æ', 'æ', 'ß', 'É',
+	' ', '!', '"', '#', '¤', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'¡', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 'Ä', 'Ö', 'Ñ', 'Ü', '§',
+	'¿', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 'ä', 'ö', 'ñ', 'ü', 'à',
+}
+
+// gsmExtensionTable maps the septets that follow an escape to their unicode characters
+var gsmExtensionTable = [gsmAlphabetSize]rune{
+	0x0A: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2F: '\\',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x65: '€',
+}
@@ -53,65 +53,107 @@ func canSplitAfter(char rune) bool {
 	return unicode.IsControl(char) || unicode.IsSpace(char) || unicode.IsPunct(char)
 }
 
+// splitUnit is an indivisible run of characters and its size in code points
+type splitUnit struct {
+	chars      []rune
+	codePoints int
+}
+
+// getSplitUnits groups message into grapheme clusters which should not be split.
+// Clusters larger than messageLength are broken back down into single characters.
+func getSplitUnits(message []rune, encoder Encoder, messageLength int) ([]splitUnit, error) {
+	var units []splitUnit
+
+	for _, cluster := range graphemeClusters(message) {
+		var clusterUnits []splitUnit
+		var clusterPoints int
+
+		for _, char := range cluster {
+			// Some encodings have variable lengthed characters
+			charPoints, err := encoder.GetCodePoints(char)
+			if err != nil {
+				return nil, ErrNotEncodable
+			}
+			clusterPoints += charPoints
+			clusterUnits = append(clusterUnits, splitUnit{chars: []rune{char}, codePoints: charPoints})
+		}
+
+		if clusterPoints > messageLength {
+			units = append(units, clusterUnits...)
+		} else {
+			units = append(units, splitUnit{chars: cluster, codePoints: clusterPoints})
+		}
+	}
+	return units, nil
+}
+
 // SplitMessage splits a message into parts with a maximum length of messageLength
-// code points. Word splitting is avoided.
+// code points. Word splitting is avoided, and grapheme clusters such as emoji
+// sequences, flags and accented characters are kept whole whenever they fit.
 func SplitMessage(message []rune, encoder Encoder, messageLength int) ([]string, error) {
 	var messageParts []string
 	var messagePart []rune
+	var partUnits []int // the length in characters of each unit in messagePart
 	var codePoints int
 	var lastSplitPoint = -1 // no valid split point
 
-	for idx := 0; idx < len(message); idx++ {
-		var char = message[idx]
+	units, err := getSplitUnits(message, encoder, messageLength)
+	if err != nil {
+		return nil, err
+	}
 
-		// Some encodings have variable lengthed characters
-		charPoints, err := encoder.GetCodePoints(char)
-		if err != nil {
-			return nil, ErrNotEncodable
-		}
+	for idx := 0; idx < len(units); idx++ {
+		var unit = units[idx]
 
-		codePoints += charPoints
+		codePoints += unit.codePoints
 
 		// check for split point
-		if canSplitBefore(char) {
-			lastSplitPoint = len(messagePart)
+		if canSplitBefore(unit.chars[0]) {
+			lastSplitPoint = len(partUnits)
 		}
 
 		// if the SMS is full
 		if codePoints > messageLength {
 			// if the split is impossible
-			if len(messagePart) == 0 {
+			if len(partUnits) == 0 {
 				return nil, ErrNotSplittable
 			}
 
-			// split at the last valid point
+			// split at the last valid point. Split points are unit indices, so
+			// surrogate pairs and escaped characters are never divided.
 			if lastSplitPoint == -1 {
-				lastSplitPoint = len(messagePart)
+				lastSplitPoint = len(partUnits)
 			}
 
-			// recover dropped characters
-			idx -= (len(messagePart) - lastSplitPoint)
-			messagePart = messagePart[0:lastSplitPoint]
+			// recover dropped units
+			idx -= (len(partUnits) - lastSplitPoint)
+			partLength := 0
+			for _, unitLength := range partUnits[0:lastSplitPoint] {
+				partLength += unitLength
+			}
+			messagePart = messagePart[0:partLength]
 
 			// save message part
 			messageParts = append(messageParts, string(messagePart))
 
 			// reset
 			messagePart = nil
+			partUnits = nil
 			codePoints = 0
 			lastSplitPoint = -1
 
-			// try adding char again with fresh message part
+			// try adding unit again with fresh message part
 			idx--
 			continue
 		}
 
-		// add char to message part
-		messagePart = append(messagePart, char)
+		// add unit to message part
+		messagePart = append(messagePart, unit.chars...)
+		partUnits = append(partUnits, len(unit.chars))
 
 		// check for split point
-		if canSplitAfter(char) {
-			lastSplitPoint = len(messagePart)
+		if canSplitAfter(unit.chars[0]) {
+			lastSplitPoint = len(partUnits)
 		}
 	}
 
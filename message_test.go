@@ -214,6 +214,59 @@ func TestSplitMessage(t *testing.T) {
 				"🙂",
 			},
 		},
+		{
+			"Don't split emoji ZWJ sequences",
+			[]rune("👨\u200D👩\u200D👧👨\u200D👩\u200D👧"),
+			NewUTF16(),
+			10,
+			[]string{
+				"👨\u200D👩\u200D👧",
+				"👨\u200D👩\u200D👧",
+			},
+		},
+		{
+			"Don't split flags",
+			[]rune("🇨🇦🇨🇦🇨🇦"),
+			NewUTF16(),
+			6,
+			[]string{
+				"🇨🇦",
+				"🇨🇦",
+				"🇨🇦",
+			},
+		},
+		{
+			"Don't split skin tone modifiers",
+			[]rune("👍🏽👍🏽👍🏽"),
+			NewUTF16(),
+			6,
+			[]string{
+				"👍🏽",
+				"👍🏽",
+				"👍🏽",
+			},
+		},
+		{
+			"Don't split combining accents",
+			[]rune("cafe\u0301e\u0301e\u0301"),
+			NewUTF16(),
+			5,
+			[]string{
+				"cafe\u0301",
+				"e\u0301e\u0301",
+			},
+		},
+		{
+			"Split clusters that are longer than a message part",
+			[]rune("x👨\u200D👩\u200D👧"),
+			NewUTF16(),
+			4,
+			[]string{
+				"x👨\u200D",
+				"👩\u200D",
+				"👧",
+			},
+		},
 		{
 			"Fail on generally impossible split",
 			[]rune("X"),
@@ -264,3 +317,57 @@ func TestSplitMessageFailsForUnencodableMessage(t *testing.T) {
 	assert.Nil(t, split)
 	assert.EqualError(t, ErrNotEncodable, err.Error())
 }
+
+// this test ensures that no part produced by SplitMessage ends with half of a
+// UTF-16 surrogate pair or with a GSM escape septet, for every message length
+func TestSplitMessageNeverSplitsEncodedCharacters(t *testing.T) {
+	var TestSplitMessageNeverSplitsEncodedCharacters = []struct {
+		name    string
+		message string
+		encoder Encoder
+	}{
+		{
+			"UTF-16 surrogate pairs without split points",
+			strings.Repeat("a😂😂b😂", 20),
+			NewUTF16(),
+		},
+		{
+			"UTF-16 surrogate pairs with split points",
+			strings.Repeat("😂 a😂, ", 20),
+			NewUTF16(),
+		},
+		{
+			"GSM escapes without split points",
+			strings.Repeat("a[€{b", 20),
+			NewGSM(),
+		},
+		{
+			"GSM escapes with split points",
+			strings.Repeat("[] {a}, ", 20),
+			NewGSM(),
+		},
+	}
+
+	for _, tt := range TestSplitMessageNeverSplitsEncodedCharacters {
+		for messageLength := 2; messageLength < 40; messageLength++ {
+			messages, err := SplitMessage([]rune(tt.message), tt.encoder, messageLength)
+			assert.Nil(t, err, tt.name)
+			assert.Equal(t, tt.message, strings.Join(messages, ""), tt.name)
+
+			for _, message := range messages {
+				// check the encoded part directly
+				data, err := tt.encoder.Encode(message)
+				assert.Nil(t, err)
+				if gsm, isGSM := tt.encoder.(*GSM); isGSM {
+					septets, _ := gsm.EncodeSeptets(message)
+					assert.True(t, len(septets) <= messageLength, tt.name)
+					assert.NotEqual(t, gsmEscape, septets[len(septets)-1], tt.name)
+				} else {
+					assert.True(t, len(data)/2 <= messageLength, tt.name)
+					lastCodeUnit := rune(data[len(data)-2])<<byteLength | rune(data[len(data)-1])
+					assert.False(t, lastCodeUnit >= highSurrogateStart && lastCodeUnit <= highSurrogateEnd, tt.name)
+				}
+			}
+		}
+	}
+}
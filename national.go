@@ -0,0 +1,191 @@
+package gosms
+
+import (
+	"errors"
+	"unicode"
+)
+
+// ErrUnsupportedLanguage indicates that no shift table exists for the given national language
+var ErrUnsupportedLanguage = errors.New("the national language shift table is not supported")
+
+// NationalLanguage identifies a national language shift table from 3GPP TS 23.038
+type NationalLanguage byte
+
+const (
+	// NationalLanguageDefault selects the default GSM alphabet and extension table
+	NationalLanguageDefault NationalLanguage = 0x00
+
+	// NationalLanguageTurkish selects the Turkish shift tables
+	NationalLanguageTurkish NationalLanguage = 0x01
+
+	// NationalLanguageSpanish selects the Spanish single shift table
+	NationalLanguageSpanish NationalLanguage = 0x02
+
+	// NationalLanguagePortuguese selects the Portuguese shift tables
+	NationalLanguagePortuguese NationalLanguage = 0x03
+
+	// NationalLanguageBengali selects the Bengali shift tables
+	NationalLanguageBengali NationalLanguage = 0x04
+
+	// NationalLanguageHindi selects the Hindi shift tables
+	NationalLanguageHindi NationalLanguage = 0x06
+
+	// NationalLanguageTamil selects the Tamil shift tables
+	NationalLanguageTamil NationalLanguage = 0x0B
+)
+
+var nationalLanguageNames = map[NationalLanguage]string{
+	NationalLanguageDefault:    "Default",
+	NationalLanguageTurkish:    "Turkish",
+	NationalLanguageSpanish:    "Spanish",
+	NationalLanguagePortuguese: "Portuguese",
+	NationalLanguageBengali:    "Bengali",
+	NationalLanguageHindi:      "Hindi",
+	NationalLanguageTamil:      "Tamil",
+}
+
+// String returns the name of the national language
+func (l NationalLanguage) String() string {
+	if name, ok := nationalLanguageNames[l]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// gsmLockingShiftTables replace the default GSM alphabet
+var gsmLockingShiftTables = map[NationalLanguage]*[gsmAlphabetSize]rune{
+	NationalLanguageDefault: &gsmDefaultAlphabet,
+	NationalLanguageTurkish: withSeptets(&gsmDefaultAlphabet, map[byte]rune{
+		0x04: '€', 0x07: 'ı', 0x0B: 'Ğ', 0x0C: 'ğ', 0x1C: 'Ş', 0x1D: 'ş',
+		0x40: 'İ', 0x60: 'ç',
+	}),
+	NationalLanguagePortuguese: withSeptets(&gsmDefaultAlphabet, map[byte]rune{
+		0x04: 'ê', 0x06: 'ú', 0x07: 'í', 0x08: 'ó', 0x09: 'ç', 0x0B: 'Ô', 0x0C: 'ô',
+		0x0E: 'Á', 0x0F: 'á', 0x12: 'ª', 0x13: 'Ç', 0x14: 'À', 0x15: '∞', 0x16: '^',
+		0x17: '\\', 0x18: '€', 0x19: 'Ó', 0x1A: '|', 0x1C: 'Â', 0x1D: 'â', 0x1E: 'Ê',
+		0x24: 'º', 0x40: 'Í', 0x5B: 'Ã', 0x5C: 'Õ', 0x5D: 'Ú', 0x60: '~', 0x7B: 'ã',
+		0x7C: 'õ', 0x7D: '`',
+	}),
+	NationalLanguageBengali: indicLockingShift(0x0980, unicode.Bengali, map[byte]rune{
+		0x60: '\u09CE', 0x7B: '\u09D7', 0x7C: '\u09DC', 0x7D: '\u09DD', 0x7E: '\u09F0', 0x7F: '\u09F1',
+	}),
+	NationalLanguageHindi: indicLockingShift(0x0900, unicode.Devanagari, map[byte]rune{
+		0x7B: '\u0972', 0x7C: '\u097B', 0x7D: '\u097C', 0x7E: '\u097E', 0x7F: '\u097F',
+	}),
+	NationalLanguageTamil: indicLockingShift(0x0B80, unicode.Tamil, map[byte]rune{
+		0x7B: '\u0BD7',
+	}),
+}
+
+// gsmSingleShiftTables replace the GSM extension table
+var gsmSingleShiftTables = map[NationalLanguage]*[gsmAlphabetSize]rune{
+	NationalLanguageDefault: &gsmExtensionTable,
+	NationalLanguageTurkish: withSeptets(&gsmExtensionTable, map[byte]rune{
+		0x47: 'Ğ', 0x49: 'İ', 0x53: 'Ş', 0x63: 'ç', 0x67: 'ğ', 0x69: 'ı', 0x73: 'ş',
+	}),
+	NationalLanguageSpanish: withSeptets(&gsmExtensionTable, map[byte]rune{
+		0x09: 'ç', 0x41: 'Á', 0x49: 'Í', 0x4F: 'Ó', 0x55: 'Ú', 0x61: 'á', 0x69: 'í',
+		0x6F: 'ó', 0x75: 'ú',
+	}),
+	NationalLanguagePortuguese: withSeptets(&gsmExtensionTable, map[byte]rune{
+		0x05: 'ê', 0x09: 'ç', 0x0B: 'Ô', 0x0C: 'ô', 0x0E: 'Á', 0x0F: 'á', 0x12: 'Φ',
+		0x13: 'Γ', 0x15: 'Ω', 0x16: 'Π', 0x17: 'Ψ', 0x18: 'Σ', 0x19: 'Θ', 0x1F: 'Ê',
+		0x41: 'À', 0x49: 'Í', 0x4F: 'Ó', 0x55: 'Ú', 0x5B: 'Ã', 0x5C: 'Õ', 0x61: 'Â',
+		0x69: 'í', 0x6F: 'ó', 0x75: 'ú', 0x7B: 'ã', 0x7C: 'õ', 0x7F: 'â',
+	}),
+	NationalLanguageBengali: indicSingleShift(0x09E6),
+	NationalLanguageHindi:   indicSingleShift(0x0966),
+	NationalLanguageTamil:   indicSingleShift(0x0BE6),
+}
+
+var (
+	gsmLockingShiftSeptets = reverseTables(gsmLockingShiftTables)
+	gsmSingleShiftSeptets  = reverseTables(gsmSingleShiftTables)
+)
+
+// indicLetterOffsets are the offsets into a unicode Indic script block of the
+// letters in the Indic locking shift tables, which all share the same layout
+var indicLetterOffsets = map[byte]rune{
+	0x00: 0x01, 0x01: 0x02, 0x02: 0x03, 0x03: 0x05, 0x04: 0x06, 0x05: 0x07, 0x06: 0x08,
+	0x07: 0x09, 0x08: 0x0A, 0x09: 0x0B, 0x0B: 0x0C, 0x0C: 0x0D, 0x0E: 0x0E, 0x0F: 0x0F,
+	0x10: 0x10, 0x11: 0x11, 0x12: 0x12, 0x13: 0x13, 0x14: 0x14, 0x15: 0x15, 0x16: 0x16,
+	0x17: 0x17, 0x18: 0x18, 0x19: 0x19, 0x1A: 0x1A, 0x1C: 0x1B, 0x1D: 0x1C, 0x1E: 0x1D,
+	0x1F: 0x1E, 0x22: 0x1F, 0x23: 0x20, 0x24: 0x21, 0x25: 0x22, 0x26: 0x23, 0x27: 0x24,
+	0x2A: 0x25, 0x2B: 0x26, 0x2D: 0x27, 0x2F: 0x28, 0x3C: 0x29, 0x3D: 0x2A, 0x3E: 0x2B,
+	0x40: 0x2C, 0x41: 0x2D, 0x42: 0x2E, 0x43: 0x2F, 0x44: 0x30, 0x45: 0x31, 0x46: 0x32,
+	0x47: 0x33, 0x48: 0x34, 0x49: 0x35, 0x4A: 0x36, 0x4B: 0x37, 0x4C: 0x38, 0x4D: 0x39,
+	0x4E: 0x3C, 0x4F: 0x3D, 0x50: 0x3E, 0x51: 0x3F, 0x52: 0x40, 0x53: 0x41, 0x54: 0x42,
+	0x55: 0x43, 0x56: 0x44, 0x57: 0x45, 0x58: 0x46, 0x59: 0x47, 0x5A: 0x48, 0x5B: 0x49,
+	0x5C: 0x4A, 0x5D: 0x4B, 0x5E: 0x4C, 0x5F: 0x4D, 0x60: 0x50,
+}
+
+// indicCommonSeptets are the characters shared by all Indic locking shift tables
+var indicCommonSeptets = map[byte]rune{
+	0x0A: '\n', 0x0D: '\r', 0x20: ' ', 0x21: '!', 0x28: ')', 0x29: '(', 0x2C: ',',
+	0x2E: '.', 0x3A: ':', 0x3B: ';', 0x3F: '?',
+}
+
+// indicCommonShiftSeptets are the characters shared by all Indic single shift tables
+var indicCommonShiftSeptets = map[byte]rune{
+	0x00: '@', 0x01: '£', 0x02: '$', 0x03: '¥', 0x04: '¿', 0x05: '"', 0x06: '¤',
+	0x07: '%', 0x08: '&', 0x09: '\'', 0x0A: '\f', 0x0B: '*', 0x0C: '+', 0x0E: '-',
+	0x0F: '/', 0x10: '<', 0x11: '=', 0x12: '>', 0x13: '¡', 0x14: '^', 0x16: '_',
+	0x17: '#', 0x19: '\u0964', 0x1A: '\u0965', 0x28: '{', 0x29: '}', 0x2F: '\\', 0x3C: '[',
+	0x3D: '~', 0x3E: ']', 0x40: '|', 0x65: '€',
+}
+
+// withSeptets returns a copy of table with septets replaced
+func withSeptets(table *[gsmAlphabetSize]rune, septets map[byte]rune) *[gsmAlphabetSize]rune {
+	replaced := *table
+	for septet, char := range septets {
+		replaced[septet] = char
+	}
+	return &replaced
+}
+
+// indicLockingShift builds the locking shift table of an Indic script whose unicode
+// block starts at base. Letters missing from the script are left unassigned.
+func indicLockingShift(base rune, script *unicode.RangeTable, septets map[byte]rune) *[gsmAlphabetSize]rune {
+	var table [gsmAlphabetSize]rune
+
+	for septet, offset := range indicLetterOffsets {
+		if unicode.Is(script, base+offset) {
+			table[septet] = base + offset
+		}
+	}
+	for septet, char := range indicCommonSeptets {
+		table[septet] = char
+	}
+	for idx := 0; idx < 10; idx++ {
+		table[0x30+idx] = '0' + rune(idx)
+	}
+	for idx := 0; idx < 26; idx++ {
+		table[0x61+idx] = 'a' + rune(idx)
+	}
+	return withSeptets(&table, septets)
+}
+
+// indicSingleShift builds the single shift table of an Indic script whose digits start at zero
+func indicSingleShift(zero rune) *[gsmAlphabetSize]rune {
+	var table [gsmAlphabetSize]rune
+
+	for septet, char := range indicCommonShiftSeptets {
+		table[septet] = char
+	}
+	for idx := 0; idx < 10; idx++ {
+		table[0x1C+idx] = zero + rune(idx)
+	}
+	for idx := 0; idx < 26; idx++ {
+		table[0x41+idx] = 'A' + rune(idx)
+	}
+	return &table
+}
+
+// reverseTables maps the characters of each table back to their septets
+func reverseTables(tables map[NationalLanguage]*[gsmAlphabetSize]rune) map[NationalLanguage]map[rune]byte {
+	septets := make(map[NationalLanguage]map[rune]byte, len(tables))
+	for language, table := range tables {
+		septets[language] = reverseAlphabet(table)
+	}
+	return septets
+}
@@ -0,0 +1,339 @@
+package gosms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test ensures that national language encoders are created for supported tables only
+func TestNewNationalGSM(t *testing.T) {
+	var TestNewNationalGSM = []struct {
+		name          string
+		lockingShift  NationalLanguage
+		singleShift   NationalLanguage
+		expectedName  string
+		expectedError error
+	}{
+		{
+			"default tables",
+			NationalLanguageDefault,
+			NationalLanguageDefault,
+			EncoderNameGSM,
+			nil,
+		},
+		{
+			"Turkish tables",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			"GSM (Turkish locking shift, Turkish single shift)",
+			nil,
+		},
+		{
+			"Spanish single shift",
+			NationalLanguageDefault,
+			NationalLanguageSpanish,
+			"GSM (Spanish single shift)",
+			nil,
+		},
+		{
+			"Hindi locking shift",
+			NationalLanguageHindi,
+			NationalLanguageDefault,
+			"GSM (Hindi locking shift)",
+			nil,
+		},
+		{
+			"Spanish has no locking shift table",
+			NationalLanguageSpanish,
+			NationalLanguageDefault,
+			"",
+			ErrUnsupportedLanguage,
+		},
+		{
+			"unknown language",
+			NationalLanguageDefault,
+			NationalLanguage(0x7F),
+			"",
+			ErrUnsupportedLanguage,
+		},
+	}
+
+	for _, tt := range TestNewNationalGSM {
+		encoder, err := NewNationalGSM(tt.lockingShift, tt.singleShift)
+		if tt.expectedError != nil {
+			assert.Nil(t, encoder, tt.name)
+			assert.EqualError(t, err, tt.expectedError.Error(), tt.name)
+			continue
+		}
+
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.expectedName, encoder.GetEncoderName(), tt.name)
+		assert.Equal(t, tt.lockingShift, encoder.(*GSM).GetLockingShift(), tt.name)
+		assert.Equal(t, tt.singleShift, encoder.(*GSM).GetSingleShift(), tt.name)
+	}
+}
+
+// this test ensures that national language tables encode and decode their characters
+func TestNationalGSMEncodeDecode(t *testing.T) {
+	var TestNationalGSMEncodeDecode = []struct {
+		name              string
+		lockingShift      NationalLanguage
+		singleShift       NationalLanguage
+		message           string
+		expectedSeptets   int
+		encodableAsGSM    bool
+		expectedSeptetsAt map[int]byte
+	}{
+		{
+			"Turkish",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			"Işığı söndür, çağır!",
+			20,
+			false,
+			map[int]byte{0: 0x49, 1: 0x1D, 2: 0x07, 3: 0x0C, 4: 0x07},
+		},
+		{
+			"Turkish single shift only",
+			NationalLanguageDefault,
+			NationalLanguageTurkish,
+			"ğ",
+			2,
+			false,
+			map[int]byte{0: 0x1B, 1: 0x67},
+		},
+		{
+			"Spanish",
+			NationalLanguageDefault,
+			NationalLanguageSpanish,
+			"Canción",
+			8,
+			false,
+			map[int]byte{5: 0x1B, 6: 0x6F},
+		},
+		{
+			"Portuguese",
+			NationalLanguagePortuguese,
+			NationalLanguagePortuguese,
+			"Não há ação",
+			11,
+			false,
+			map[int]byte{1: 0x7B, 5: 0x0F, 8: 0x09, 9: 0x7B},
+		},
+		{
+			"Hindi",
+			NationalLanguageHindi,
+			NationalLanguageHindi,
+			"नमस्ते 123।",
+			12,
+			false,
+			map[int]byte{0: 0x2F, 1: 0x42, 10: 0x1B, 11: 0x19},
+		},
+		{
+			"Bengali",
+			NationalLanguageBengali,
+			NationalLanguageBengali,
+			"আমি",
+			3,
+			false,
+			map[int]byte{0: 0x04, 1: 0x42, 2: 0x51},
+		},
+		{
+			"Tamil",
+			NationalLanguageTamil,
+			NationalLanguageTamil,
+			"வணக்கம் ௧",
+			10,
+			false,
+			map[int]byte{0: 0x49, 1: 0x26, 8: 0x1B, 9: 0x1D},
+		},
+		{
+			"default characters remain available",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			"Hello [world]",
+			15,
+			true,
+			map[int]byte{0: 0x48, 6: 0x1B, 7: 0x3C},
+		},
+	}
+
+	for _, tt := range TestNationalGSMEncodeDecode {
+		encoder, err := NewNationalGSM(tt.lockingShift, tt.singleShift)
+		assert.Nil(t, err, tt.name)
+		assert.True(t, encoder.CheckEncodability(tt.message), tt.name)
+		assert.Equal(t, tt.encodableAsGSM, NewGSM().CheckEncodability(tt.message), tt.name)
+
+		// count code points the same way the septets are encoded
+		var codePoints int
+		for _, char := range tt.message {
+			charPoints, err := encoder.GetCodePoints(char)
+			assert.Nil(t, err, tt.name)
+			codePoints += charPoints
+		}
+
+		septets, err := encoder.(*GSM).EncodeSeptets(tt.message)
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.expectedSeptets, len(septets), tt.name)
+		assert.Equal(t, codePoints, len(septets), tt.name)
+		for idx, septet := range tt.expectedSeptetsAt {
+			assert.Equal(t, septet, septets[idx], tt.name)
+		}
+
+		encoded, err := encoder.Encode(tt.message)
+		assert.Nil(t, err, tt.name)
+		decoded, err := encoder.Decode(encoded)
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.message, decoded, tt.name)
+	}
+}
+
+// this test ensures that every character in every table survives a round trip
+func TestNationalGSMTablesRoundTrip(t *testing.T) {
+	for language, table := range gsmLockingShiftTables {
+		encoder, err := NewNationalGSM(language, NationalLanguageDefault)
+		assert.Nil(t, err)
+
+		for septet, char := range table {
+			if char == 0 {
+				continue
+			}
+			decoded, err := encoder.(*GSM).DecodeSeptets([]byte{byte(septet)})
+			assert.Nil(t, err)
+			assert.Equal(t, string(char), decoded, language.String())
+
+			codePoints, err := encoder.GetCodePoints(char)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, codePoints, language.String())
+		}
+	}
+
+	for language, table := range gsmSingleShiftTables {
+		encoder, err := NewNationalGSM(NationalLanguageDefault, language)
+		assert.Nil(t, err)
+
+		for _, char := range table {
+			if char == 0 {
+				continue
+			}
+			septets, err := encoder.(*GSM).EncodeSeptets(string(char))
+			assert.Nil(t, err)
+
+			decoded, err := encoder.(*GSM).DecodeSeptets(septets)
+			assert.Nil(t, err)
+			assert.Equal(t, string(char), decoded, language.String())
+		}
+	}
+
+	// check that reserved septets cannot be decoded
+	encoder, _ := NewNationalGSM(NationalLanguageTamil, NationalLanguageTamil)
+	decoded, err := encoder.(*GSM).DecodeSeptets([]byte{0x00})
+	assert.Equal(t, "", decoded)
+	assert.EqualError(t, err, ErrNotDecodable.Error())
+}
+
+// this test ensures that Split identifies national language tables in the UDH
+// and counts the information elements against the capacity of each part
+func TestSplitWithNationalLanguage(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	var TestSplitWithNationalLanguage = []struct {
+		name           string
+		lockingShift   NationalLanguage
+		singleShift    NationalLanguage
+		shortReference bool
+		message        string
+		expectedParts  int
+		expectedUDH    []byte
+	}{
+		{
+			"single part with both tables",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			true,
+			strings.Repeat("ş", 152),
+			1,
+			[]byte{0x06, 0x24, 0x01, 0x01, 0x25, 0x01, 0x01},
+		},
+		{
+			"single part with single shift",
+			NationalLanguageDefault,
+			NationalLanguageSpanish,
+			true,
+			strings.Repeat("x", 155),
+			1,
+			[]byte{0x03, 0x24, 0x01, 0x02},
+		},
+		{
+			"both tables push a message into two parts",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			true,
+			strings.Repeat("ş", 153),
+			2,
+			nil,
+		},
+		{
+			"concatenated parts with both tables",
+			NationalLanguageTurkish,
+			NationalLanguageTurkish,
+			true,
+			strings.Repeat("ş", 146*2),
+			2,
+			nil,
+		},
+		{
+			"concatenated parts with both tables and long reference",
+			NationalLanguagePortuguese,
+			NationalLanguagePortuguese,
+			false,
+			strings.Repeat("ã", 145*2),
+			2,
+			nil,
+		},
+	}
+
+	for _, tt := range TestSplitWithNationalLanguage {
+		encoder, err := NewNationalGSM(tt.lockingShift, tt.singleShift)
+		assert.Nil(t, err)
+
+		splitter := NewSplitter()
+		splitter.SetEncoder(encoder)
+		splitter.SetShortReference(tt.shortReference)
+
+		SMSs, err := splitter.Split(from, []string{to}, tt.message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when splitting the message for test '%s'", err, tt.name)
+		}
+		assert.Equal(t, tt.expectedParts, len(SMSs), tt.name)
+
+		for idx, sms := range SMSs {
+			udh := []byte(sms.GetUDH())
+			assert.True(t, len(sms.GetUserData()) <= DefaultSMSBytes, tt.name)
+			if tt.expectedUDH != nil {
+				assert.Equal(t, tt.expectedUDH, udh, tt.name)
+				continue
+			}
+
+			// concatenation information element followed by the language information elements
+			concatLength := udhByteLengthShort
+			if !tt.shortReference {
+				concatLength = udhByteLengthLong
+			}
+			assert.Equal(t, byte(len(udh)-1), udh[0], tt.name)
+			assert.Equal(t, byte(len(SMSs)), udh[concatLength-2], tt.name)
+			assert.Equal(t, byte(idx+1), udh[concatLength-1], tt.name)
+			assert.Equal(t, []byte{0x24, 0x01, byte(tt.singleShift), 0x25, 0x01, byte(tt.lockingShift)}, udh[concatLength:], tt.name)
+
+			// the content decodes from the user data after the fill bits
+			udhBits := len(udh)*byteLength + FillBits(len(udh))
+			septets := UnpackSeptets(sms.GetUserData(), udhBits, len([]rune(sms.GetContent())))
+			decoded, err := encoder.(*GSM).DecodeSeptets(septets)
+			assert.Nil(t, err)
+			assert.Equal(t, sms.GetContent(), decoded, tt.name)
+		}
+	}
+}
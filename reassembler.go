@@ -0,0 +1,237 @@
+package gosms
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReassemblyTimeout is how long parts of an incomplete message are kept
+const DefaultReassemblyTimeout = 5 * time.Minute
+
+// ErrInvalidConcatenation indicates that the concatenation information element of a part is inconsistent
+var ErrInvalidConcatenation = errors.New("the concatenation information element is invalid")
+
+// reassemblyKey identifies the parts of a concatenated message
+type reassemblyKey struct {
+	from      string
+	to        string
+	reference uint16
+	total     byte
+}
+
+// reassemblyGroup holds the parts of a concatenated message received so far
+type reassemblyGroup struct {
+	parts   map[byte]string
+	expires time.Time
+}
+
+// PartialMessage describes a concatenated message which expired before all of its parts arrived
+type PartialMessage struct {
+	from      string
+	to        string
+	reference uint16
+	total     int
+	parts     map[int]string
+}
+
+// GetFrom returns the originator of the message
+func (p *PartialMessage) GetFrom() string {
+	return p.from
+}
+
+// GetTo returns the recipient of the message
+func (p *PartialMessage) GetTo() string {
+	return p.to
+}
+
+// GetReference returns the concatenation reference number of the message
+func (p *PartialMessage) GetReference() uint16 {
+	return p.reference
+}
+
+// GetTotal returns the number of parts the message was made of
+func (p *PartialMessage) GetTotal() int {
+	return p.total
+}
+
+// GetParts returns the content of the received parts keyed by sequence number, starting at 1
+func (p *PartialMessage) GetParts() map[int]string {
+	return p.parts
+}
+
+// GetContent returns the content of the received parts in order
+func (p *PartialMessage) GetContent() string {
+	var content strings.Builder
+	for sequence := 1; sequence <= p.total; sequence++ {
+		content.WriteString(p.parts[sequence])
+	}
+	return content.String()
+}
+
+// GetMissing returns the sequence numbers of the parts which were not received
+func (p *PartialMessage) GetMissing() []int {
+	var missing []int
+	for sequence := 1; sequence <= p.total; sequence++ {
+		if _, ok := p.parts[sequence]; !ok {
+			missing = append(missing, sequence)
+		}
+	}
+	return missing
+}
+
+// Reassembler puts the parts of inbound concatenated messages back together.
+// Parts are grouped by originator, recipient, reference number and total
+// number of parts, and may arrive in any order.
+type Reassembler struct {
+	mutex         sync.Mutex
+	groups        map[reassemblyKey]*reassemblyGroup
+	timeout       time.Duration
+	expiryHandler func(PartialMessage)
+	now           func() time.Time
+}
+
+// NewReassembler creates a new Reassembler configured with default values
+func NewReassembler() *Reassembler {
+	return &Reassembler{
+		groups:        make(map[reassemblyKey]*reassemblyGroup),
+		timeout:       DefaultReassemblyTimeout,
+		expiryHandler: nil,
+		now:           time.Now,
+	}
+}
+
+// SetTimeout sets how long parts of an incomplete message are kept after its first part arrives
+func (r *Reassembler) SetTimeout(timeout time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.timeout = timeout
+}
+
+// SetExpiryHandler sets a function which is called with the received parts of each expired message
+func (r *Reassembler) SetExpiryHandler(expiryHandler func(PartialMessage)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.expiryHandler = expiryHandler
+}
+
+// Add adds a part to the Reassembler. Once every part of a message has arrived,
+// the full message content is returned along with true. Messages which are not
+// concatenated are returned immediately, and duplicate parts are ignored.
+func (r *Reassembler) Add(sms SMS) (string, bool, error) {
+	r.Expire()
+
+	reference, total, sequence, concatenated, err := getConcatenation(sms)
+	if err != nil {
+		return "", false, err
+	}
+	if !concatenated || total == 1 {
+		return sms.content, true, nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := reassemblyKey{from: sms.from, to: sms.to, reference: reference, total: total}
+	group, ok := r.groups[key]
+	if !ok {
+		group = &reassemblyGroup{parts: make(map[byte]string, total), expires: r.now().Add(r.timeout)}
+		r.groups[key] = group
+	}
+	if _, duplicate := group.parts[sequence]; !duplicate {
+		group.parts[sequence] = sms.content
+	}
+	if len(group.parts) < int(total) {
+		return "", false, nil
+	}
+
+	var message strings.Builder
+	for idx := byte(1); idx <= total; idx++ {
+		message.WriteString(group.parts[idx])
+	}
+	delete(r.groups, key)
+	return message.String(), true, nil
+}
+
+// Expire removes incomplete messages whose timeout has passed, passing each to
+// the expiry handler. It is called by Add, and can be called periodically to
+// expire messages when no parts are arriving.
+func (r *Reassembler) Expire() {
+	var expired []PartialMessage
+
+	r.mutex.Lock()
+	now := r.now()
+	for key, group := range r.groups {
+		if now.Before(group.expires) {
+			continue
+		}
+		delete(r.groups, key)
+
+		parts := make(map[int]string, len(group.parts))
+		for sequence, content := range group.parts {
+			parts[int(sequence)] = content
+		}
+		expired = append(expired, PartialMessage{
+			from:      key.from,
+			to:        key.to,
+			reference: key.reference,
+			total:     int(key.total),
+			parts:     parts,
+		})
+	}
+	expiryHandler := r.expiryHandler
+	r.mutex.Unlock()
+
+	// the handler is called without holding the lock so that it may use the Reassembler
+	if expiryHandler == nil {
+		return
+	}
+	for _, partial := range expired {
+		expiryHandler(partial)
+	}
+}
+
+// GetPending returns the number of incomplete messages being held
+func (r *Reassembler) GetPending() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.groups)
+}
+
+// getConcatenation returns the numbering of an SMS part, from its segment or the
+// concatenation information element of its UDH, if it has one
+func getConcatenation(sms SMS) (reference uint16, total byte, sequence byte, concatenated bool, err error) {
+	var udh UDH
+
+	// parts numbered alongside their user data have no concatenation element
+	if segment, ok := sms.GetSegment(); ok {
+		if segment.Sequence == 0 || segment.Sequence > segment.Total {
+			return 0, 0, 0, false, ErrInvalidConcatenation
+		}
+		return segment.Reference, segment.Total, segment.Sequence, true, nil
+	}
+
+	if len(sms.udh) == 0 {
+		return 0, 0, 0, false, nil
+	}
+	if err := udh.Unmarshal([]byte(sms.udh)); err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	for _, infoElement := range udh.InformationElements {
+		switch concatenation := infoElement.(type) {
+		case Concatenated8Bit:
+			reference, total, sequence = uint16(concatenation.Reference), concatenation.Total, concatenation.Sequence
+		case Concatenated16Bit:
+			reference, total, sequence = concatenation.Reference, concatenation.Total, concatenation.Sequence
+		default:
+			continue
+		}
+		if total == 0 || sequence == 0 || sequence > total {
+			return 0, 0, 0, false, ErrInvalidConcatenation
+		}
+		return reference, total, sequence, true, nil
+	}
+	return 0, 0, 0, false, nil
+}
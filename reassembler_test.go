@@ -0,0 +1,243 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const reassemblyMessage = "This message is long enough that it has to be split into several parts, " +
+	"which may arrive out of order and have to be put back together."
+
+// splitForReassembly splits reassemblyMessage into parts of at most 40 bytes
+func splitForReassembly(t *testing.T, shortReference bool) []SMS {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetShortReference(shortReference)
+
+	smsParts, err := splitter.Split("from", []string{"to"}, reassemblyMessage)
+	assert.NoError(t, err)
+	assert.True(t, len(smsParts) > 2)
+	return smsParts
+}
+
+func TestReassemblerReassemblesOutOfOrderParts(t *testing.T) {
+	var TestReassemblerReassemblesOutOfOrderParts = []struct {
+		name           string
+		shortReference bool
+	}{
+		{"8-bit reference", true},
+		{"16-bit reference", false},
+	}
+
+	for _, tt := range TestReassemblerReassemblesOutOfOrderParts {
+		t.Run(tt.name, func(t *testing.T) {
+			smsParts := splitForReassembly(t, tt.shortReference)
+			reassembler := NewReassembler()
+
+			// deliver the parts in reverse order
+			for idx := len(smsParts) - 1; idx > 0; idx-- {
+				message, complete, err := reassembler.Add(smsParts[idx])
+				assert.NoError(t, err)
+				assert.False(t, complete)
+				assert.Equal(t, "", message)
+			}
+			assert.Equal(t, 1, reassembler.GetPending())
+
+			message, complete, err := reassembler.Add(smsParts[0])
+			assert.NoError(t, err)
+			assert.True(t, complete)
+			assert.Equal(t, reassemblyMessage, message)
+			assert.Equal(t, 0, reassembler.GetPending())
+		})
+	}
+}
+
+func TestReassemblerReassemblesParsedParts(t *testing.T) {
+	smsParts := splitForReassembly(t, true)
+	reassembler := NewReassembler()
+
+	var message string
+	var complete bool
+	for _, sms := range smsParts {
+		parsed, err := ParseSMS("from", "to", sms.GetUserData(), true, sms.GetUserDataLength(), NewGSM())
+		assert.NoError(t, err)
+
+		message, complete, err = reassembler.Add(parsed)
+		assert.NoError(t, err)
+	}
+
+	assert.True(t, complete)
+	assert.Equal(t, reassemblyMessage, message)
+}
+
+func TestReassemblerIgnoresDuplicateParts(t *testing.T) {
+	smsParts := splitForReassembly(t, true)
+	reassembler := NewReassembler()
+
+	for idx := 0; idx < len(smsParts)-1; idx++ {
+		_, complete, err := reassembler.Add(smsParts[idx])
+		assert.NoError(t, err)
+		assert.False(t, complete)
+
+		_, complete, err = reassembler.Add(smsParts[idx])
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+
+	message, complete, err := reassembler.Add(smsParts[len(smsParts)-1])
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, reassemblyMessage, message)
+}
+
+func TestReassemblerKeepsMessagesSeparate(t *testing.T) {
+	smsParts := splitForReassembly(t, true)
+	reassembler := NewReassembler()
+
+	// the same parts from another originator belong to a different message
+	for _, sms := range smsParts[:len(smsParts)-1] {
+		other := sms
+		other.from = "other"
+		_, complete, err := reassembler.Add(other)
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	for _, sms := range smsParts[:len(smsParts)-1] {
+		_, complete, err := reassembler.Add(sms)
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	assert.Equal(t, 2, reassembler.GetPending())
+
+	message, complete, err := reassembler.Add(smsParts[len(smsParts)-1])
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, reassemblyMessage, message)
+	assert.Equal(t, 1, reassembler.GetPending())
+}
+
+func TestReassemblerReturnsUnconcatenatedMessages(t *testing.T) {
+	splitter := NewSplitter()
+	smsParts, err := splitter.Split("from", []string{"to"}, "short message")
+	assert.NoError(t, err)
+
+	reassembler := NewReassembler()
+	message, complete, err := reassembler.Add(smsParts[0])
+
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, "short message", message)
+	assert.Equal(t, 0, reassembler.GetPending())
+}
+
+func TestReassemblerReturnsErrorForInvalidConcatenation(t *testing.T) {
+	var TestReassemblerReturnsErrorForInvalidConcatenation = []struct {
+		name        string
+		udh         []byte
+		expectedErr error
+	}{
+		{"zero total", []byte{0x05, 0x00, 0x03, 0x01, 0x00, 0x00}, ErrInvalidConcatenation},
+		{"zero sequence", []byte{0x05, 0x00, 0x03, 0x01, 0x02, 0x00}, ErrInvalidConcatenation},
+		{"sequence beyond total", []byte{0x05, 0x00, 0x03, 0x01, 0x02, 0x03}, ErrInvalidConcatenation},
+		{"malformed UDH", []byte{0x05, 0x00, 0x04, 0x01, 0x02, 0x03}, ErrInvalidUDH},
+	}
+
+	for _, tt := range TestReassemblerReturnsErrorForInvalidConcatenation {
+		t.Run(tt.name, func(t *testing.T) {
+			reassembler := NewReassembler()
+			_, complete, err := reassembler.Add(newSMS("from", "to", "content", string(tt.udh)))
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.False(t, complete)
+		})
+	}
+}
+
+func TestReassemblerExpiresIncompleteMessages(t *testing.T) {
+	smsParts := splitForReassembly(t, true)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var expired []PartialMessage
+	reassembler := NewReassembler()
+	reassembler.now = func() time.Time { return now }
+	reassembler.SetTimeout(time.Minute)
+	reassembler.SetExpiryHandler(func(partial PartialMessage) {
+		expired = append(expired, partial)
+	})
+
+	// the second part never arrives
+	for idx, sms := range smsParts {
+		if idx != 1 {
+			reassembler.Add(sms)
+		}
+	}
+
+	now = now.Add(time.Minute - time.Second)
+	reassembler.Expire()
+	assert.Equal(t, 0, len(expired))
+	assert.Equal(t, 1, reassembler.GetPending())
+
+	now = now.Add(time.Second)
+	reassembler.Expire()
+	assert.Equal(t, 0, reassembler.GetPending())
+	assert.Equal(t, 1, len(expired))
+
+	partial := expired[0]
+	assert.Equal(t, "from", partial.GetFrom())
+	assert.Equal(t, "to", partial.GetTo())
+	assert.Equal(t, uint16(smsParts[0].udh[3]), partial.GetReference())
+	assert.Equal(t, len(smsParts), partial.GetTotal())
+	assert.Equal(t, []int{2}, partial.GetMissing())
+	assert.Equal(t, len(smsParts)-1, len(partial.GetParts()))
+	assert.Equal(t, smsParts[0].GetContent(), partial.GetParts()[1])
+
+	var expectedContent string
+	for idx, sms := range smsParts {
+		if idx != 1 {
+			expectedContent += sms.GetContent()
+		}
+	}
+	assert.Equal(t, expectedContent, partial.GetContent())
+
+	// a late part starts a new message instead of completing the expired one
+	_, complete, err := reassembler.Add(smsParts[1])
+	assert.NoError(t, err)
+	assert.False(t, complete)
+	assert.Equal(t, 1, reassembler.GetPending())
+}
+
+func TestReassemblerReassemblesSegments(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetConcatenation(ConcatenationSAR)
+	smsParts, err := splitter.Split("from", []string{"to"}, reassemblyMessage)
+	assert.NoError(t, err)
+	assert.True(t, len(smsParts) > 2)
+
+	reassembler := NewReassembler()
+	var message string
+	var complete bool
+	for idx := len(smsParts) - 1; idx >= 0; idx-- {
+		// parts parsed from a PDU carry their numbering outside the user data
+		parsed, err := ParseSMS("from", "to", smsParts[idx].GetUserData(), false, smsParts[idx].GetUserDataLength(), NewGSM())
+		assert.NoError(t, err)
+		segment, _ := smsParts[idx].GetSegment()
+		parsed.SetSegment(segment)
+
+		message, complete, err = reassembler.Add(parsed)
+		assert.NoError(t, err)
+	}
+	assert.True(t, complete)
+	assert.Equal(t, reassemblyMessage, message)
+}
+
+func TestReassemblerReturnsErrorForInvalidSegment(t *testing.T) {
+	sms := newSMS("from", "to", "content", "")
+	sms.SetSegment(Concatenated16Bit{Reference: 1, Total: 2, Sequence: 3})
+
+	_, complete, err := NewReassembler().Add(sms)
+	assert.Equal(t, ErrInvalidConcatenation, err)
+	assert.False(t, complete)
+}
@@ -0,0 +1,204 @@
+package gosms
+
+import (
+	"container/list"
+	"crypto/md5"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReferenceWindow is how long LRUReferenceAllocator keeps a reference
+	// number from being reused, which is as long as a Reassembler waits for parts
+	DefaultReferenceWindow = DefaultReassemblyTimeout
+
+	// DefaultReferenceDestinations is how many destinations a CounterReferenceAllocator
+	// or LRUReferenceAllocator tracks
+	DefaultReferenceDestinations = 100000
+
+	shortReferenceCount int = 1 << 8
+	longReferenceCount  int = 1 << 16
+)
+
+// ErrReferencesExhausted indicates that every reference number for a destination is in use
+var ErrReferencesExhausted = errors.New("all reference numbers for the destination are in use")
+
+// ReferenceAllocator allocates the reference numbers which identify the parts of a concatenated message
+type ReferenceAllocator interface {
+	// Allocate returns a reference number for a message from from to to, whose
+	// first part is content. Short references must be less than 256.
+	Allocate(from string, to string, content string, shortReference bool) (uint16, error)
+}
+
+// referenceCount returns the number of reference numbers available
+func referenceCount(shortReference bool) int {
+	if shortReference {
+		return shortReferenceCount
+	}
+	return longReferenceCount
+}
+
+// HashReferenceAllocator derives reference numbers from an MD5 hash of the
+// message and the current time. It needs no state, but is likely to reuse short
+// references when several messages are sent to a destination in quick succession.
+type HashReferenceAllocator struct{}
+
+// NewHashReferenceAllocator returns a new HashReferenceAllocator
+func NewHashReferenceAllocator() ReferenceAllocator {
+	return &HashReferenceAllocator{}
+}
+
+// Allocate returns the first one or two bytes of the message hash
+func (a *HashReferenceAllocator) Allocate(from string, to string, content string, shortReference bool) (uint16, error) {
+	const timeBase = 10
+
+	// messageHash is not intended to be cryptographically strong,
+	// but merely unique enough to identify a message.
+	md5Hash := md5.New()
+	microtime := time.Now().UnixNano() / int64(time.Millisecond)
+	io.WriteString(md5Hash, // create an MD5 hash of
+		from+ // the sender
+			to+ // the receivers
+			content+ // the first message part
+			strconv.FormatInt(microtime, timeBase), // the time
+	) // to ensure uniqueness
+	messageHash := md5Hash.Sum(nil)
+
+	if shortReference {
+		return uint16(messageHash[0]), nil
+	}
+	return uint16(messageHash[0])<<byteLength | uint16(messageHash[1]), nil
+}
+
+// CounterReferenceAllocator allocates consecutive reference numbers to each
+// recipient, so a reference is only reused once the counter wraps around.
+// Recipients are tracked in a least recently used cache, so a recipient which
+// is evicted starts counting from zero again.
+type CounterReferenceAllocator struct {
+	mutex sync.Mutex
+	destinationCache
+}
+
+// NewCounterReferenceAllocator returns a new CounterReferenceAllocator which
+// tracks up to capacity recipients
+func NewCounterReferenceAllocator(capacity int) ReferenceAllocator {
+	return &CounterReferenceAllocator{destinationCache: newDestinationCache(capacity)}
+}
+
+// Allocate returns the next reference number for to
+func (a *CounterReferenceAllocator) Allocate(from string, to string, content string, shortReference bool) (uint16, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	references := a.getDestination(to)
+	reference := references.counter % referenceCount(shortReference)
+	references.counter = (reference + 1) % longReferenceCount
+	return uint16(reference), nil
+}
+
+// referencePool is the reference numbers of one size recently allocated to a
+// destination. References are allocated in order, so those in use are the ones
+// before next, and they expire in the order they were allocated.
+type referencePool struct {
+	next      int
+	allocated []time.Time
+}
+
+// allocate returns the next reference number, or ErrReferencesExhausted if
+// every reference was allocated within the window
+func (p *referencePool) allocate(now time.Time, window time.Duration, count int) (uint16, error) {
+	expired := 0
+	for expired < len(p.allocated) && now.Sub(p.allocated[expired]) >= window {
+		expired++
+	}
+	p.allocated = p.allocated[expired:]
+
+	if len(p.allocated) >= count {
+		return 0, ErrReferencesExhausted
+	}
+	reference := uint16(p.next)
+	p.next = (p.next + 1) % count
+	p.allocated = append(p.allocated, now)
+	return reference, nil
+}
+
+// destinationReferences are the reference numbers recently allocated to a destination
+type destinationReferences struct {
+	to      string
+	counter int
+	short   referencePool
+	long    referencePool
+}
+
+// destinationCache holds the references of the most recently used destinations
+type destinationCache struct {
+	capacity     int
+	destinations map[string]*list.Element
+	recency      *list.List
+}
+
+// newDestinationCache returns a destinationCache which holds up to capacity destinations
+func newDestinationCache(capacity int) destinationCache {
+	return destinationCache{
+		capacity:     capacity,
+		destinations: make(map[string]*list.Element),
+		recency:      list.New(),
+	}
+}
+
+// LRUReferenceAllocator guarantees that a reference number is not reused for a
+// destination within a time window. Short and long references are allocated
+// separately. Destinations are tracked in a least recently used cache, so the
+// guarantee only holds while fewer destinations than its capacity are messaged
+// within the window.
+type LRUReferenceAllocator struct {
+	mutex  sync.Mutex
+	window time.Duration
+	now    func() time.Time
+	destinationCache
+}
+
+// NewLRUReferenceAllocator returns a new LRUReferenceAllocator which tracks up
+// to capacity destinations and does not reuse references within window
+func NewLRUReferenceAllocator(window time.Duration, capacity int) ReferenceAllocator {
+	return &LRUReferenceAllocator{
+		window:           window,
+		now:              time.Now,
+		destinationCache: newDestinationCache(capacity),
+	}
+}
+
+// Allocate returns the next reference number which has not been allocated to
+// to within the window, or ErrReferencesExhausted if there is none
+func (a *LRUReferenceAllocator) Allocate(from string, to string, content string, shortReference bool) (uint16, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	references := a.getDestination(to)
+	if shortReference {
+		return references.short.allocate(a.now(), a.window, shortReferenceCount)
+	}
+	return references.long.allocate(a.now(), a.window, longReferenceCount)
+}
+
+// getDestination returns the references of to, marking it as most recently used
+// and evicting the least recently used destination if the capacity is exceeded
+func (c *destinationCache) getDestination(to string) *destinationReferences {
+	if element, ok := c.destinations[to]; ok {
+		c.recency.MoveToFront(element)
+		return element.Value.(*destinationReferences)
+	}
+
+	references := &destinationReferences{to: to}
+	c.destinations[to] = c.recency.PushFront(references)
+
+	for c.recency.Len() > c.capacity && c.recency.Len() > 1 {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.destinations, oldest.Value.(*destinationReferences).to)
+	}
+	return references
+}
@@ -0,0 +1,181 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashReferenceAllocator(t *testing.T) {
+	allocator := NewHashReferenceAllocator()
+
+	shortReference, err := allocator.Allocate("from", "to", "content", true)
+	assert.NoError(t, err)
+	assert.True(t, shortReference < uint16(shortReferenceCount))
+
+	_, err = allocator.Allocate("from", "to", "content", false)
+	assert.NoError(t, err)
+}
+
+func TestCounterReferenceAllocator(t *testing.T) {
+	allocator := NewCounterReferenceAllocator(DefaultReferenceDestinations)
+
+	var TestCounterReferenceAllocator = []struct {
+		name           string
+		to             string
+		shortReference bool
+		expected       uint16
+	}{
+		{"first reference", "alice", true, 0},
+		{"second reference", "alice", true, 1},
+		{"another recipient", "bob", true, 0},
+		{"long reference", "alice", false, 2},
+		{"first recipient again", "alice", true, 3},
+	}
+
+	for _, tt := range TestCounterReferenceAllocator {
+		t.Run(tt.name, func(t *testing.T) {
+			reference, err := allocator.Allocate("from", tt.to, "content", tt.shortReference)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, reference)
+		})
+	}
+}
+
+func TestCounterReferenceAllocatorWrapsAround(t *testing.T) {
+	allocator := NewCounterReferenceAllocator(DefaultReferenceDestinations)
+
+	for idx := 0; idx < shortReferenceCount; idx++ {
+		reference, _ := allocator.Allocate("from", "to", "content", true)
+		assert.Equal(t, uint16(idx), reference)
+	}
+
+	reference, err := allocator.Allocate("from", "to", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+}
+
+func TestLRUReferenceAllocatorDoesNotReuseReferencesWithinWindow(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	allocator := NewLRUReferenceAllocator(time.Hour, 10).(*LRUReferenceAllocator)
+	allocator.now = func() time.Time { return now }
+
+	seen := make(map[uint16]bool)
+	for idx := 0; idx < shortReferenceCount; idx++ {
+		reference, err := allocator.Allocate("from", "to", "content", true)
+		assert.NoError(t, err)
+		assert.False(t, seen[reference])
+		seen[reference] = true
+	}
+
+	// every short reference is in use
+	_, err := allocator.Allocate("from", "to", "content", true)
+	assert.Equal(t, ErrReferencesExhausted, err)
+
+	// other destinations are unaffected
+	reference, err := allocator.Allocate("from", "other", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+
+	// references become available once the window has passed
+	now = now.Add(time.Hour)
+	reference, err = allocator.Allocate("from", "to", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+}
+
+func TestLRUReferenceAllocatorSkipsReferencesInUse(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	allocator := NewLRUReferenceAllocator(time.Hour, 10).(*LRUReferenceAllocator)
+	allocator.now = func() time.Time { return now }
+
+	for idx := 0; idx < 3; idx++ {
+		allocator.Allocate("from", "to", "content", true)
+	}
+
+	// the first reference expires, but allocation continues after the last reference
+	now = now.Add(time.Hour)
+	allocator.Allocate("from", "to", "content", true)
+	reference, err := allocator.Allocate("from", "to", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(4), reference)
+}
+
+func TestLRUReferenceAllocatorExpiresReferencesInAllocationOrder(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	allocator := NewLRUReferenceAllocator(time.Hour, 10).(*LRUReferenceAllocator)
+	allocator.now = func() time.Time { return now }
+
+	allocator.Allocate("from", "to", "content", true)
+	now = now.Add(30 * time.Minute)
+	for idx := 1; idx < shortReferenceCount; idx++ {
+		allocator.Allocate("from", "to", "content", true)
+	}
+
+	// only the first reference has expired
+	now = now.Add(30 * time.Minute)
+	reference, err := allocator.Allocate("from", "to", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+	_, err = allocator.Allocate("from", "to", "content", true)
+	assert.Equal(t, ErrReferencesExhausted, err)
+}
+
+func TestLRUReferenceAllocatorKeepsShortAndLongReferencesApart(t *testing.T) {
+	allocator := NewLRUReferenceAllocator(time.Hour, 10)
+
+	for idx := 0; idx < shortReferenceCount; idx++ {
+		allocator.Allocate("from", "to", "content", true)
+	}
+	_, err := allocator.Allocate("from", "to", "content", true)
+	assert.Equal(t, ErrReferencesExhausted, err)
+
+	// long references do not use up short ones, nor short references long ones
+	for idx := 0; idx < shortReferenceCount+1; idx++ {
+		reference, err := allocator.Allocate("from", "to", "content", false)
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(idx), reference)
+	}
+}
+
+func TestLRUReferenceAllocatorEvictsLeastRecentlyUsedDestination(t *testing.T) {
+	allocator := NewLRUReferenceAllocator(time.Hour, 2).(*LRUReferenceAllocator)
+
+	allocator.Allocate("from", "alice", "content", true)
+	allocator.Allocate("from", "bob", "content", true)
+	allocator.Allocate("from", "alice", "content", true)
+	allocator.Allocate("from", "carol", "content", true)
+
+	assert.Equal(t, 2, allocator.recency.Len())
+	_, tracked := allocator.destinations["bob"]
+	assert.False(t, tracked)
+	_, tracked = allocator.destinations["alice"]
+	assert.True(t, tracked)
+
+	// bob starts over once evicted
+	reference, err := allocator.Allocate("from", "bob", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+}
+
+func TestCounterReferenceAllocatorEvictsLeastRecentlyUsedRecipient(t *testing.T) {
+	allocator := NewCounterReferenceAllocator(2).(*CounterReferenceAllocator)
+
+	allocator.Allocate("from", "alice", "content", true)
+	allocator.Allocate("from", "bob", "content", true)
+	allocator.Allocate("from", "alice", "content", true)
+	allocator.Allocate("from", "carol", "content", true)
+
+	assert.Equal(t, 2, allocator.recency.Len())
+	_, tracked := allocator.destinations["bob"]
+	assert.False(t, tracked)
+
+	// alice keeps counting, bob starts over once evicted
+	reference, err := allocator.Allocate("from", "alice", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), reference)
+	reference, err = allocator.Allocate("from", "bob", "content", true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), reference)
+}
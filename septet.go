@@ -0,0 +1,77 @@
+package gosms
+
+const septetBits int = 7
+
+// FillBits returns the number of bits needed after a UDH of udhLength octets
+// for GSM septets to start on a septet boundary
+func FillBits(udhLength int) int {
+	return (septetBits - (udhLength*byteLength)%septetBits) % septetBits
+}
+
+// UDHSeptets returns the number of septets taken up by a UDH of udhLength
+// octets and the fill bits which follow it, as counted by TP-UDL
+func UDHSeptets(udhLength int) int {
+	return (udhLength*byteLength + FillBits(udhLength)) / septetBits
+}
+
+// PackSeptets packs 7-bit septets into octets as described in GSM 03.38.
+// fillBits zero bits are inserted before the first septet so that the
+// septets can start on a septet boundary after a user data header. Spare bits
+// in the last octet are zero, so the septet count must be sent alongside.
+func PackSeptets(septets []byte, fillBits int) []byte {
+	totalBits := fillBits + len(septets)*septetBits
+	packed := make([]byte, (totalBits+byteLength-1)/byteLength)
+
+	for idx, septet := range septets {
+		bit := fillBits + idx*septetBits
+		octet := bit / byteLength
+		shift := uint(bit % byteLength)
+
+		packed[octet] |= (septet & gsmSeptetMask) << shift
+		// the septet overflows into the next octet
+		if shift > 1 {
+			packed[octet+1] |= (septet & gsmSeptetMask) >> (uint(byteLength) - shift)
+		}
+	}
+	return packed
+}
+
+// UnpackSeptets unpacks count septets from packed octets, skipping the first
+// fillBits bits. It is the inverse of PackSeptets.
+func UnpackSeptets(packed []byte, fillBits int, count int) []byte {
+	var septets []byte
+
+	for idx := 0; idx < count; idx++ {
+		bit := fillBits + idx*septetBits
+		octet := bit / byteLength
+		shift := uint(bit % byteLength)
+
+		// stop if the packed data is too short
+		if octet >= len(packed) {
+			break
+		}
+
+		septet := packed[octet] >> shift
+		if shift > 1 {
+			if octet+1 >= len(packed) {
+				break
+			}
+			septet |= packed[octet+1] << (uint(byteLength) - shift)
+		}
+		septets = append(septets, septet&gsmSeptetMask)
+	}
+	return septets
+}
+
+// reverseAlphabet maps the characters of an alphabet back to their septets
+func reverseAlphabet(alphabet *[gsmAlphabetSize]rune) map[rune]byte {
+	septets := make(map[rune]byte, gsmAlphabetSize)
+
+	// iterate backwards so that the lowest septet wins for duplicate characters
+	for idx := gsmAlphabetSize - 1; idx >= 0; idx-- {
+		if alphabet[idx] != 0 {
+			septets[alphabet[idx]] = byte(idx)
+		}
+	}
+	return septets
+}
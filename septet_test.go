@@ -0,0 +1,91 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test ensures that septets are packed and unpacked as described in GSM 03.38
+func TestPackSeptets(t *testing.T) {
+	var TestPackSeptets = []struct {
+		name     string
+		septets  []byte
+		fillBits int
+		expected []byte
+	}{
+		{
+			"no septets",
+			nil,
+			0,
+			[]byte{},
+		},
+		{
+			"single septet",
+			[]byte{0x41},
+			0,
+			[]byte{0x41},
+		},
+		{
+			"hellohello",
+			[]byte("hellohello"),
+			0,
+			[]byte{0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37},
+		},
+		{
+			"eight septets fill seven octets",
+			[]byte("abcdefgh"),
+			0,
+			[]byte{0x61, 0xF1, 0x98, 0x5C, 0x36, 0x9F, 0xD1},
+		},
+		{
+			"one fill bit",
+			[]byte{0x41},
+			1,
+			[]byte{0x82},
+		},
+		{
+			"fill bits overflow",
+			[]byte{0x7F, 0x7F},
+			6,
+			[]byte{0xC0, 0xFF, 0x0F},
+		},
+	}
+
+	for _, tt := range TestPackSeptets {
+		packed := PackSeptets(tt.septets, tt.fillBits)
+		assert.Equal(t, tt.expected, packed, tt.name)
+
+		unpacked := UnpackSeptets(packed, tt.fillBits, len(tt.septets))
+		assert.Equal(t, len(tt.septets), len(unpacked), tt.name)
+		for idx := range unpacked {
+			assert.Equal(t, tt.septets[idx], unpacked[idx], tt.name)
+		}
+	}
+}
+
+// this test ensures that UnpackSeptets stops at the end of the packed data
+func TestUnpackSeptetsStopsAtEndOfData(t *testing.T) {
+	unpacked := UnpackSeptets([]byte{0xE8, 0x32}, 0, 10)
+
+	assert.Equal(t, []byte{0x68, 0x65}, unpacked)
+}
+
+// this test ensures that a UDH is followed by enough fill bits to reach a septet boundary
+func TestFillBits(t *testing.T) {
+	var TestFillBits = []struct {
+		udhLength          int
+		expectedFillBits   int
+		expectedUDHSeptets int
+	}{
+		{0, 0, 0},
+		{6, 1, 7},
+		{7, 0, 8},
+		{12, 2, 14},
+	}
+
+	for _, tt := range TestFillBits {
+		assert.Equal(t, tt.expectedFillBits, FillBits(tt.udhLength), tt.udhLength)
+		assert.Equal(t, tt.expectedUDHSeptets, UDHSeptets(tt.udhLength), tt.udhLength)
+	}
+}
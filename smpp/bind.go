@@ -0,0 +1,99 @@
+package smpp
+
+import "github.com/textnow/gosms"
+
+const (
+	// InterfaceVersion is the SMPP version implemented by this package
+	InterfaceVersion byte = 0x34
+
+	maxSystemIDLength     int = 16
+	maxPasswordLength     int = 9
+	maxSystemTypeLength   int = 13
+	maxAddressRangeLength int = 41
+)
+
+// BindType is the kind of session a bind requests
+type BindType int
+
+const (
+	// BindTransmitter binds a session for submitting messages
+	BindTransmitter BindType = iota
+
+	// BindReceiver binds a session for receiving messages
+	BindReceiver
+
+	// BindTransceiver binds a session for both submitting and receiving messages
+	BindTransceiver
+)
+
+// bindCommandIDs are the request and response command IDs of each bind type
+var bindCommandIDs = map[BindType][2]CommandID{
+	BindTransmitter: {CommandBindTransmitter, CommandBindTransmitterResp},
+	BindReceiver:    {CommandBindReceiver, CommandBindReceiverResp},
+	BindTransceiver: {CommandBindTransceiver, CommandBindTransceiverResp},
+}
+
+// Bind is a bind_transmitter, bind_receiver or bind_transceiver
+type Bind struct {
+	Header
+	BindType         BindType
+	SystemID         string
+	Password         string
+	SystemType       string
+	InterfaceVersion byte
+	AddrTON          gosms.TypeOfNumber
+	AddrNPI          gosms.NumberingPlan
+	AddressRange     string
+}
+
+// GetCommandID returns the command ID of the bind type
+func (p *Bind) GetCommandID() CommandID {
+	return bindCommandIDs[p.BindType][0]
+}
+
+// marshalBody encodes the body of the Bind
+func (p *Bind) marshalBody(w *pduWriter) {
+	w.writeCString(p.SystemID, maxSystemIDLength)
+	w.writeCString(p.Password, maxPasswordLength)
+	w.writeCString(p.SystemType, maxSystemTypeLength)
+	w.writeByte(p.InterfaceVersion)
+	w.writeByte(byte(p.AddrTON))
+	w.writeByte(byte(p.AddrNPI))
+	w.writeCString(p.AddressRange, maxAddressRangeLength)
+}
+
+// unmarshalBody decodes the body of the Bind
+func (p *Bind) unmarshalBody(r *pduReader) {
+	p.SystemID = r.readCString(maxSystemIDLength)
+	p.Password = r.readCString(maxPasswordLength)
+	p.SystemType = r.readCString(maxSystemTypeLength)
+	p.InterfaceVersion = r.readByte()
+	p.AddrTON = gosms.TypeOfNumber(r.readByte())
+	p.AddrNPI = gosms.NumberingPlan(r.readByte())
+	p.AddressRange = r.readCString(maxAddressRangeLength)
+}
+
+// BindResp is the response to a Bind
+type BindResp struct {
+	Header
+	BindType BindType
+	SystemID string
+	TLVs     TLVs
+}
+
+// GetCommandID returns the response command ID of the bind type
+func (p *BindResp) GetCommandID() CommandID {
+	return bindCommandIDs[p.BindType][1]
+}
+
+// marshalBody encodes the body of the BindResp
+func (p *BindResp) marshalBody(w *pduWriter) {
+	w.writeCString(p.SystemID, maxSystemIDLength)
+	w.writeTLVs(p.TLVs)
+}
+
+// unmarshalBody decodes the body of the BindResp
+func (p *BindResp) unmarshalBody(r *pduReader) {
+	p.SystemID = r.readCString(maxSystemIDLength)
+	p.TLVs = r.readTLVs()
+}
@@ -0,0 +1,94 @@
+package smpp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+)
+
+func TestBindMarshal(t *testing.T) {
+	bind := &Bind{
+		Header:           Header{SequenceNumber: 1},
+		BindType:         BindTransmitter,
+		SystemID:         "test",
+		Password:         "pw",
+		InterfaceVersion: InterfaceVersion,
+		AddrTON:          gosms.TypeOfNumberInternational,
+		AddrNPI:          gosms.NumberingPlanISDN,
+	}
+
+	data, err := Marshal(bind)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x00, 0x00, 0x00, 0x1D, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		't', 'e', 's', 't', 0x00, 'p', 'w', 0x00, 0x00, 0x34, 0x01, 0x01, 0x00,
+	}, data)
+}
+
+func TestBindRoundTrip(t *testing.T) {
+	var TestBindRoundTrip = []struct {
+		name               string
+		bindType           BindType
+		expectedCommandID  CommandID
+		expectedResponseID CommandID
+	}{
+		{"transmitter", BindTransmitter, CommandBindTransmitter, CommandBindTransmitterResp},
+		{"receiver", BindReceiver, CommandBindReceiver, CommandBindReceiverResp},
+		{"transceiver", BindTransceiver, CommandBindTransceiver, CommandBindTransceiverResp},
+	}
+
+	for _, tt := range TestBindRoundTrip {
+		t.Run(tt.name, func(t *testing.T) {
+			bind := &Bind{
+				Header:           Header{SequenceNumber: 5},
+				BindType:         tt.bindType,
+				SystemID:         "system",
+				Password:         "password",
+				SystemType:       "type",
+				InterfaceVersion: InterfaceVersion,
+				AddressRange:     "^1415",
+			}
+			assert.Equal(t, tt.expectedCommandID, bind.GetCommandID())
+			data, err := Marshal(bind)
+			assert.NoError(t, err)
+			pdu, err := Unmarshal(data)
+			assert.NoError(t, err)
+			assert.Equal(t, bind, pdu)
+
+			bindResp := &BindResp{
+				Header:   Header{SequenceNumber: 5},
+				BindType: tt.bindType,
+				SystemID: "smsc",
+				TLVs:     TLVs{NewUint8TLV(TagSCInterfaceVersion, InterfaceVersion)},
+			}
+			assert.Equal(t, tt.expectedResponseID, bindResp.GetCommandID())
+			data, err = Marshal(bindResp)
+			assert.NoError(t, err)
+			pdu, err = Unmarshal(data)
+			assert.NoError(t, err)
+			assert.Equal(t, bindResp, pdu)
+		})
+	}
+}
+
+func TestBindMarshalReturnsErrorForLongFields(t *testing.T) {
+	var TestBindMarshalReturnsErrorForLongFields = []struct {
+		name string
+		bind *Bind
+	}{
+		{"system_id", &Bind{SystemID: strings.Repeat("s", maxSystemIDLength)}},
+		{"password", &Bind{Password: strings.Repeat("p", maxPasswordLength)}},
+		{"system_type", &Bind{SystemType: strings.Repeat("t", maxSystemTypeLength)}},
+		{"address_range", &Bind{AddressRange: strings.Repeat("1", maxAddressRangeLength)}},
+	}
+
+	for _, tt := range TestBindMarshalReturnsErrorForLongFields {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(tt.bind)
+			assert.Equal(t, ErrFieldTooLong, err)
+		})
+	}
+}
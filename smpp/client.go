@@ -0,0 +1,775 @@
+package smpp
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/textnow/gosms"
+)
+
+const (
+	// DefaultWindow is the default number of submit_sm a Client leaves outstanding
+	DefaultWindow int = 10
+
+	// DefaultEnquireLinkInterval is the default time a session may be idle before a Client sends an enquire_link
+	DefaultEnquireLinkInterval = 30 * time.Second
+
+	// DefaultResponseTimeout is the default time a Client waits for a response
+	DefaultResponseTimeout = 10 * time.Second
+
+	// DefaultReconnectDelay is the default delay before a Client first tries to reconnect
+	DefaultReconnectDelay = time.Second
+
+	// DefaultMaxReconnectDelay is the default limit of the exponential reconnect backoff
+	DefaultMaxReconnectDelay = time.Minute
+
+	// DefaultUnbindTimeout is the default time a closing Client waits for the SMSC to respond to its unbind
+	DefaultUnbindTimeout = time.Second
+
+	// DefaultThrottleDelay is the default time a Client pauses submitting after ESME_RTHROTTLED
+	DefaultThrottleDelay = time.Second
+
+	// DefaultMaxThrottleRetries is the default number of times a Client resends a submit_sm after ESME_RTHROTTLED
+	DefaultMaxThrottleRetries int = 5
+
+	// DefaultDeliveryBuffer is the default capacity of the channel returned by Client.Deliveries
+	DefaultDeliveryBuffer int = 100
+
+	maxSequenceNumber uint32 = 0x7FFFFFFF
+)
+
+var (
+	// ErrClientClosed indicates that the Client has been closed
+	ErrClientClosed = errors.New("the client is closed")
+
+	// ErrConnectionLost indicates that the connection to the SMSC was lost before a response arrived
+	ErrConnectionLost = errors.New("the connection to the SMSC was lost")
+
+	// ErrResponseTimeout indicates that the SMSC did not respond in time
+	ErrResponseTimeout = errors.New("the SMSC did not respond in time")
+
+	// ErrNotBound indicates that the Client could not bind to the SMSC in time
+	ErrNotBound = errors.New("the client is not bound to the SMSC")
+
+	// ErrUnexpectedResponse indicates that the SMSC responded with the wrong PDU
+	ErrUnexpectedResponse = errors.New("the SMSC responded with an unexpected PDU")
+
+	// ErrAlreadyConnected indicates that the Client has already been connected
+	ErrAlreadyConnected = errors.New("the client is already connected")
+)
+
+// Client is an ESME which binds to an SMSC, submits SMS parts generated by a
+// gosms Splitter, and receives deliver_sm. A Client keeps its session alive with
+// enquire_link and rebinds with an exponential backoff when it is dropped.
+type Client struct {
+	address             string
+	bindType            BindType
+	systemID            string
+	password            string
+	systemType          string
+	enquireLinkInterval time.Duration
+	responseTimeout     time.Duration
+	unbindTimeout       time.Duration
+	reconnectDelay      time.Duration
+	maxReconnectDelay   time.Duration
+	throttleDelay       time.Duration
+	maxThrottleRetries  int
+	registeredDelivery  byte
+	defaultRegion       string
+
+	mutex          sync.Mutex
+	connected      bool
+	session        *session
+	bound          chan struct{}
+	sequenceNumber uint32
+	throttledUntil time.Time
+	slots          chan struct{}
+	deliveries     chan *DeliverSM
+	rejected       int
+	closed         chan struct{}
+	closeOnce      sync.Once
+	waitGroup      sync.WaitGroup
+}
+
+// NewClient creates a new Client for the SMSC at address configured with default values
+func NewClient(address string, systemID string, password string) *Client {
+	return &Client{
+		address:             address,
+		bindType:            BindTransceiver,
+		systemID:            systemID,
+		password:            password,
+		systemType:          "",
+		enquireLinkInterval: DefaultEnquireLinkInterval,
+		responseTimeout:     DefaultResponseTimeout,
+		unbindTimeout:       DefaultUnbindTimeout,
+		reconnectDelay:      DefaultReconnectDelay,
+		maxReconnectDelay:   DefaultMaxReconnectDelay,
+		throttleDelay:       DefaultThrottleDelay,
+		maxThrottleRetries:  DefaultMaxThrottleRetries,
+		registeredDelivery:  0,
+		defaultRegion:       "",
+		bound:               make(chan struct{}),
+		slots:               make(chan struct{}, DefaultWindow),
+		deliveries:          make(chan *DeliverSM, DefaultDeliveryBuffer),
+		closed:              make(chan struct{}),
+	}
+}
+
+// SetBindType sets how the Client binds, which is BindTransceiver by default
+func (c *Client) SetBindType(bindType BindType) {
+	c.bindType = bindType
+}
+
+// SetSystemType sets the system_type the Client binds with
+func (c *Client) SetSystemType(systemType string) {
+	c.systemType = systemType
+}
+
+// SetWindow sets how many submit_sm may be waiting for a response at once. The
+// window cannot be changed once the Client is connected.
+func (c *Client) SetWindow(window int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.connected {
+		return ErrAlreadyConnected
+	}
+	if window < 1 {
+		window = 1
+	}
+	c.slots = make(chan struct{}, window)
+	return nil
+}
+
+// SetEnquireLinkInterval sets how long a session may be idle before the Client sends an enquire_link, and zero disables enquire_link
+func (c *Client) SetEnquireLinkInterval(enquireLinkInterval time.Duration) {
+	c.enquireLinkInterval = enquireLinkInterval
+}
+
+// SetResponseTimeout sets how long the Client waits for a response, or to be bound before submitting
+func (c *Client) SetResponseTimeout(responseTimeout time.Duration) {
+	c.responseTimeout = responseTimeout
+}
+
+// SetUnbindTimeout sets how long Close waits for the SMSC to respond to the unbind
+func (c *Client) SetUnbindTimeout(unbindTimeout time.Duration) {
+	c.unbindTimeout = unbindTimeout
+}
+
+// SetReconnectDelay sets the first and the longest delay between attempts to reconnect
+func (c *Client) SetReconnectDelay(reconnectDelay time.Duration, maxReconnectDelay time.Duration) {
+	c.reconnectDelay = reconnectDelay
+	c.maxReconnectDelay = maxReconnectDelay
+}
+
+// SetThrottleDelay sets how long the Client pauses submitting after the SMSC responds with ESME_RTHROTTLED
+func (c *Client) SetThrottleDelay(throttleDelay time.Duration) {
+	c.throttleDelay = throttleDelay
+}
+
+// SetMaxThrottleRetries sets how many times the Client resends a submit_sm after
+// ESME_RTHROTTLED before returning the error
+func (c *Client) SetMaxThrottleRetries(maxThrottleRetries int) {
+	c.maxThrottleRetries = maxThrottleRetries
+}
+
+// SetRegisteredDelivery sets the registered_delivery of submitted messages, such as RegisteredDeliveryReceipt
+func (c *Client) SetRegisteredDelivery(registeredDelivery byte) {
+	c.registeredDelivery = registeredDelivery
+}
+
+// SetDefaultRegion sets the ISO 3166-1 alpha-2 region used to interpret national numbers in submitted SMS
+func (c *Client) SetDefaultRegion(defaultRegion string) {
+	c.defaultRegion = defaultRegion
+}
+
+// Connect binds to the SMSC, and keeps the Client bound until it is closed.
+// The Client must be configured before it is connected.
+func (c *Client) Connect() error {
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+		c.mutex.Unlock()
+		return ErrClientClosed
+	default:
+	}
+	if c.connected {
+		c.mutex.Unlock()
+		return ErrAlreadyConnected
+	}
+	c.connected = true
+	// Close waits for the Client to finish connecting
+	c.waitGroup.Add(1)
+	defer c.waitGroup.Done()
+	c.mutex.Unlock()
+
+	s, err := c.bind()
+	if err != nil {
+		c.mutex.Lock()
+		c.connected = false
+		c.mutex.Unlock()
+		return err
+	}
+	c.setSession(s)
+
+	c.waitGroup.Add(1)
+	go c.maintain(s)
+	return nil
+}
+
+// Deliveries returns the deliver_sm received from the SMSC, including delivery
+// receipts. The channel is closed once the Client is closed. While the channel
+// is full, deliver_sm are rejected with ESME_RMSGQFUL so that the SMSC
+// delivers them again later.
+func (c *Client) Deliveries() <-chan *DeliverSM {
+	return c.deliveries
+}
+
+// GetRejectedDeliveries returns how many deliver_sm were rejected because Deliveries was full
+func (c *Client) GetRejectedDeliveries() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.rejected
+}
+
+// Submit submits an SMS and returns the message ID given by the SMSC. Submit
+// waits for a place in the window, and retries after the throttle delay, up to
+// the maximum number of throttle retries, when the SMSC responds with
+// ESME_RTHROTTLED.
+func (c *Client) Submit(sms gosms.SMS) (string, error) {
+	submit, err := NewSubmitSM(sms, c.defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	submit.RegisteredDelivery = c.registeredDelivery
+	return c.SubmitSM(submit)
+}
+
+// SubmitAll submits the SMS parts of a message and returns their message IDs.
+// Parts are first sent in order, without waiting for the responses to earlier
+// parts but keeping within the window. A part which is throttled is sent again
+// after the throttle delay, so it may reach the SMSC after later parts, which
+// the UDH or sar_* numbering lets the recipient reassemble. No more parts are
+// sent once one fails, and the first error is returned once the parts already
+// sent have been answered.
+func (c *Client) SubmitAll(smsParts []gosms.SMS) ([]string, error) {
+	submits := make([]*SubmitSM, len(smsParts))
+	for idx, sms := range smsParts {
+		submit, err := NewSubmitSM(sms, c.defaultRegion)
+		if err != nil {
+			return nil, err
+		}
+		submit.RegisteredDelivery = c.registeredDelivery
+		submits[idx] = submit
+	}
+
+	var mutex sync.Mutex
+	var firstErr error
+	var waitGroup sync.WaitGroup
+	messageIDs := make([]string, len(submits))
+	fail := func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return firstErr != nil
+	}
+
+	for idx, submit := range submits {
+		if err := c.acquireSlot(); err != nil {
+			fail(err)
+			break
+		}
+		if failed() {
+			c.releaseSlot()
+			break
+		}
+
+		// parts are sent one after another, and only the responses are awaited concurrently
+		s, responses, err := c.sendSubmit(submit)
+		if err != nil {
+			c.releaseSlot()
+			fail(err)
+			break
+		}
+		waitGroup.Add(1)
+		go func(idx int, submit *SubmitSM) {
+			defer waitGroup.Done()
+			defer c.releaseSlot()
+
+			messageID, err := c.awaitSubmit(s, submit, responses)
+			if err != nil {
+				fail(err)
+				return
+			}
+			messageIDs[idx] = messageID
+		}(idx, submit)
+	}
+	waitGroup.Wait()
+	return messageIDs, firstErr
+}
+
+// SubmitSM submits a SubmitSM and returns the message ID given by the SMSC
+func (c *Client) SubmitSM(submit *SubmitSM) (string, error) {
+	if err := c.acquireSlot(); err != nil {
+		return "", err
+	}
+	defer c.releaseSlot()
+
+	s, responses, err := c.sendSubmit(submit)
+	if err != nil {
+		return "", err
+	}
+	return c.awaitSubmit(s, submit, responses)
+}
+
+// acquireSlot waits for a place in the window
+func (c *Client) acquireSlot() error {
+	select {
+	case <-c.closed:
+		return ErrClientClosed
+	default:
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-c.closed:
+		return ErrClientClosed
+	}
+}
+
+// releaseSlot gives up a place in the window
+func (c *Client) releaseSlot() {
+	<-c.slots
+}
+
+// sendSubmit sends a SubmitSM once submitting is not paused and the Client is bound
+func (c *Client) sendSubmit(submit *SubmitSM) (*session, chan PDU, error) {
+	if err := c.waitForThrottle(); err != nil {
+		return nil, nil, err
+	}
+	s, err := c.getSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	responses, err := c.send(s, submit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, responses, nil
+}
+
+// awaitSubmit waits for the response to a SubmitSM, sending it again after the
+// throttle delay when the SMSC responds with ESME_RTHROTTLED, up to the maximum
+// number of throttle retries
+func (c *Client) awaitSubmit(s *session, submit *SubmitSM, responses chan PDU) (string, error) {
+	for retries := 0; ; retries++ {
+		response, err := c.await(s, submit, responses, c.responseTimeout)
+		if err != nil {
+			return "", err
+		}
+
+		switch status := response.GetHeader().CommandStatus; {
+		case status == StatusThrottled && retries < c.maxThrottleRetries:
+			c.throttle()
+			if s, responses, err = c.sendSubmit(submit); err != nil {
+				return "", err
+			}
+			continue
+		case status != StatusOK:
+			return "", &CommandStatusError{commandStatus: status}
+		}
+		submitResp, ok := response.(*SubmitSMResp)
+		if !ok {
+			return "", ErrUnexpectedResponse
+		}
+		return submitResp.MessageID, nil
+	}
+}
+
+// Close unbinds from the SMSC and stops the Client. Close waits at most the
+// unbind timeout for the SMSC to respond to the unbind.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.mutex.Lock()
+		s := c.session
+		c.mutex.Unlock()
+		if s != nil {
+			unbind := &Unbind{}
+			if responses, err := c.send(s, unbind); err == nil {
+				c.await(s, unbind, responses, c.unbindTimeout)
+			}
+		}
+
+		// Connect checks closed while holding the mutex
+		c.mutex.Lock()
+		close(c.closed)
+		c.mutex.Unlock()
+		if s != nil {
+			s.close()
+		}
+		c.waitGroup.Wait()
+		close(c.deliveries)
+	})
+	return nil
+}
+
+// bind connects to the SMSC and binds a new session
+func (c *Client) bind() (*session, error) {
+	conn, err := net.DialTimeout("tcp", c.address, c.responseTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s := newSession(conn, c.responseTimeout)
+	c.waitGroup.Add(1)
+	go c.read(s)
+
+	response, err := c.request(s, &Bind{
+		BindType:         c.bindType,
+		SystemID:         c.systemID,
+		Password:         c.password,
+		SystemType:       c.systemType,
+		InterfaceVersion: InterfaceVersion,
+	})
+	if err == nil && response.GetHeader().CommandStatus != StatusOK {
+		err = &CommandStatusError{commandStatus: response.GetHeader().CommandStatus}
+	}
+	if _, isBindResp := response.(*BindResp); err == nil && !isBindResp {
+		err = ErrUnexpectedResponse
+	}
+	if err != nil {
+		s.close()
+		return nil, err
+	}
+
+	c.waitGroup.Add(1)
+	go c.keepAlive(s)
+	return s, nil
+}
+
+// maintain rebinds with an exponential backoff whenever the session is lost
+func (c *Client) maintain(s *session) {
+	defer c.waitGroup.Done()
+
+	for {
+		select {
+		case <-s.closed:
+		case <-c.closed:
+			s.close()
+			return
+		}
+		c.mutex.Lock()
+		c.session = nil
+		c.bound = make(chan struct{})
+		c.mutex.Unlock()
+
+		delay := c.reconnectDelay
+		for {
+			select {
+			case <-time.After(delay):
+			case <-c.closed:
+				return
+			}
+
+			var err error
+			if s, err = c.bind(); err == nil {
+				break
+			}
+			if delay *= 2; delay > c.maxReconnectDelay {
+				delay = c.maxReconnectDelay
+			}
+		}
+		c.setSession(s)
+	}
+}
+
+// setSession makes s the current session, releasing anything waiting to be bound
+func (c *Client) setSession(s *session) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.session = s
+	close(c.bound)
+}
+
+// getSession returns the current session, waiting up to the response timeout for the Client to rebind
+func (c *Client) getSession() (*session, error) {
+	c.mutex.Lock()
+	s, bound := c.session, c.bound
+	c.mutex.Unlock()
+	if s != nil {
+		return s, nil
+	}
+
+	timer := time.NewTimer(c.responseTimeout)
+	defer timer.Stop()
+	select {
+	case <-bound:
+		return c.getSession()
+	case <-c.closed:
+		return nil, ErrClientClosed
+	case <-timer.C:
+		return nil, ErrNotBound
+	}
+}
+
+// throttle pauses submitting for the throttle delay
+func (c *Client) throttle() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.throttledUntil = time.Now().Add(c.throttleDelay)
+}
+
+// waitForThrottle waits until submitting is no longer paused
+func (c *Client) waitForThrottle() error {
+	c.mutex.Lock()
+	delay := time.Until(c.throttledUntil)
+	c.mutex.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-c.closed:
+		return ErrClientClosed
+	}
+}
+
+// nextSequenceNumber returns the sequence number of the next request
+func (c *Client) nextSequenceNumber() uint32 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sequenceNumber = c.sequenceNumber%maxSequenceNumber + 1
+	return c.sequenceNumber
+}
+
+// request sends pdu over s and waits for the response with its sequence number
+func (c *Client) request(s *session, pdu PDU) (PDU, error) {
+	responses, err := c.send(s, pdu)
+	if err != nil {
+		return nil, err
+	}
+	return c.await(s, pdu, responses, c.responseTimeout)
+}
+
+// send numbers pdu and sends it over s, returning the channel its response arrives on
+func (c *Client) send(s *session, pdu PDU) (chan PDU, error) {
+	pdu.GetHeader().SequenceNumber = c.nextSequenceNumber()
+	data, err := Marshal(pdu)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.register(pdu.GetHeader().SequenceNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.write(data); err != nil {
+		s.unregister(pdu.GetHeader().SequenceNumber)
+		return nil, ErrConnectionLost
+	}
+	return responses, nil
+}
+
+// await waits up to timeout for the response to pdu
+func (c *Client) await(s *session, pdu PDU, responses chan PDU, timeout time.Duration) (PDU, error) {
+	defer s.unregister(pdu.GetHeader().SequenceNumber)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case response, ok := <-responses:
+		if !ok {
+			return nil, ErrConnectionLost
+		}
+		return response, nil
+	case <-timer.C:
+		return nil, ErrResponseTimeout
+	}
+}
+
+// respond sends a response to a request from the SMSC
+func (c *Client) respond(s *session, request *Header, response PDU) {
+	response.GetHeader().SequenceNumber = request.SequenceNumber
+	if data, err := Marshal(response); err == nil {
+		s.write(data)
+	}
+}
+
+// read handles the PDUs received over s until it is closed
+func (c *Client) read(s *session) {
+	defer c.waitGroup.Done()
+	defer s.close()
+
+	for {
+		pdu, err := ReadPDU(s.conn)
+		if unknownCommandError, ok := err.(*UnknownCommandError); ok {
+			c.respond(s, &Header{SequenceNumber: unknownCommandError.GetSequenceNumber()}, &GenericNack{Header: Header{CommandStatus: StatusInvalidCommandID}})
+			continue
+		}
+		if err != nil {
+			return
+		}
+		s.touch()
+
+		if pdu.GetCommandID().IsResponse() {
+			s.resolve(pdu)
+			continue
+		}
+		switch request := pdu.(type) {
+		case *EnquireLink:
+			c.respond(s, &request.Header, &EnquireLinkResp{})
+		case *Unbind:
+			c.respond(s, &request.Header, &UnbindResp{})
+			return
+		case *DeliverSM:
+			// never wait for the caller, which would hold up responses
+			select {
+			case c.deliveries <- request:
+				c.respond(s, &request.Header, &DeliverSMResp{})
+			default:
+				c.mutex.Lock()
+				c.rejected++
+				c.mutex.Unlock()
+				c.respond(s, &request.Header, &DeliverSMResp{Header: Header{CommandStatus: StatusMessageQueueFull}})
+			}
+		default:
+			c.respond(s, pdu.GetHeader(), &GenericNack{Header: Header{CommandStatus: StatusInvalidCommandID}})
+		}
+	}
+}
+
+// keepAlive sends an enquire_link whenever s has been idle for the enquire
+// link interval, and closes s if the SMSC does not respond
+func (c *Client) keepAlive(s *session) {
+	defer c.waitGroup.Done()
+
+	if c.enquireLinkInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.enquireLinkInterval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.closed:
+			return
+		}
+		if s.idle() < c.enquireLinkInterval {
+			continue
+		}
+		if _, err := c.request(s, &EnquireLink{}); err != nil {
+			s.close()
+			return
+		}
+	}
+}
+
+// session is a bound connection to an SMSC
+type session struct {
+	conn         net.Conn
+	writeTimeout time.Duration
+	writeMutex   sync.Mutex
+	mutex        sync.Mutex
+	pending      map[uint32]chan PDU
+	lastActivity time.Time
+	isClosed     bool
+	closed       chan struct{}
+}
+
+// newSession creates a new session over conn
+func newSession(conn net.Conn, writeTimeout time.Duration) *session {
+	return &session{
+		conn:         conn,
+		writeTimeout: writeTimeout,
+		pending:      make(map[uint32]chan PDU),
+		lastActivity: time.Now(),
+		closed:       make(chan struct{}),
+	}
+}
+
+// write sends an encoded PDU
+func (s *session) write(data []byte) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	_, err := s.conn.Write(data)
+	return err
+}
+
+// register returns the channel the response with sequenceNumber is sent on
+func (s *session) register(sequenceNumber uint32) (chan PDU, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isClosed {
+		return nil, ErrConnectionLost
+	}
+	responses := make(chan PDU, 1)
+	s.pending[sequenceNumber] = responses
+	return responses, nil
+}
+
+// unregister stops waiting for the response with sequenceNumber
+func (s *session) unregister(sequenceNumber uint32) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.pending, sequenceNumber)
+}
+
+// resolve passes a response to the request waiting for it, if there is one
+func (s *session) resolve(response PDU) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if responses, ok := s.pending[response.GetHeader().SequenceNumber]; ok {
+		responses <- response
+		delete(s.pending, response.GetHeader().SequenceNumber)
+	}
+}
+
+// touch records activity on the session
+func (s *session) touch() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastActivity = time.Now()
+}
+
+// idle returns how long the session has been idle
+func (s *session) idle() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return time.Since(s.lastActivity)
+}
+
+// close closes the connection and fails the requests waiting for a response
+func (s *session) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isClosed {
+		return
+	}
+	s.isClosed = true
+	close(s.closed)
+	s.conn.Close()
+	for sequenceNumber, responses := range s.pending {
+		close(responses)
+		delete(s.pending, sequenceNumber)
+	}
+}
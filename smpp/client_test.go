@@ -0,0 +1,603 @@
+package smpp
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+)
+
+// testSMSC is a minimal SMSC on loopback, which answers each PDU it receives
+// with the PDU returned by its handler
+type testSMSC struct {
+	listener net.Listener
+	handle   func(s *testSMSC, conn net.Conn, pdu PDU) PDU
+	mutex    sync.Mutex
+	conns    []net.Conn
+	received []PDU
+}
+
+// newTestSMSC starts a testSMSC which answers with defaultResponse unless handle returns a response
+func newTestSMSC(t *testing.T, handle func(s *testSMSC, conn net.Conn, pdu PDU) PDU) *testSMSC {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	if handle == nil {
+		handle = func(s *testSMSC, conn net.Conn, pdu PDU) PDU { return defaultResponse(pdu) }
+	}
+	s := &testSMSC{listener: listener, handle: handle}
+	go s.accept()
+	return s
+}
+
+// defaultResponse returns a successful response to pdu
+func defaultResponse(pdu PDU) PDU {
+	switch request := pdu.(type) {
+	case *Bind:
+		return &BindResp{BindType: request.BindType, SystemID: "smsc"}
+	case *SubmitSM:
+		return &SubmitSMResp{MessageID: fmt.Sprintf("id%d", request.SequenceNumber)}
+	case *EnquireLink:
+		return &EnquireLinkResp{}
+	case *Unbind:
+		return &UnbindResp{}
+	}
+	return nil
+}
+
+func (s *testSMSC) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.conns = append(s.conns, conn)
+		s.mutex.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *testSMSC) serve(conn net.Conn) {
+	for {
+		pdu, err := ReadPDU(conn)
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.received = append(s.received, pdu)
+		s.mutex.Unlock()
+
+		if response := s.handle(s, conn, pdu); response != nil {
+			response.GetHeader().SequenceNumber = pdu.GetHeader().SequenceNumber
+			s.write(conn, response)
+		}
+	}
+}
+
+// write sends pdu over conn
+func (s *testSMSC) write(conn net.Conn, pdu PDU) {
+	data, _ := Marshal(pdu)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	conn.Write(data)
+}
+
+// getConn returns the most recent connection
+func (s *testSMSC) getConn() net.Conn {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.conns[len(s.conns)-1]
+}
+
+// count returns how many PDUs with commandID were received
+func (s *testSMSC) count(commandID CommandID) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, pdu := range s.received {
+		if pdu.GetCommandID() == commandID {
+			count++
+		}
+	}
+	return count
+}
+
+// getReceived returns the PDUs with commandID which were received
+func (s *testSMSC) getReceived(commandID CommandID) []PDU {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var received []PDU
+	for _, pdu := range s.received {
+		if pdu.GetCommandID() == commandID {
+			received = append(received, pdu)
+		}
+	}
+	return received
+}
+
+// drop closes every connection
+func (s *testSMSC) drop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *testSMSC) close() {
+	s.listener.Close()
+	s.drop()
+}
+
+// newTestClient returns a Client for smsc with short timeouts
+func newTestClient(smsc *testSMSC) *Client {
+	client := NewClient(smsc.listener.Addr().String(), "system", "secret")
+	client.SetResponseTimeout(500 * time.Millisecond)
+	client.SetReconnectDelay(10*time.Millisecond, 40*time.Millisecond)
+	client.SetThrottleDelay(50 * time.Millisecond)
+	client.SetEnquireLinkInterval(0)
+	return client
+}
+
+// waitFor waits up to a second for condition to hold
+func waitFor(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClientSubmitsSplitMessage(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.SetBindType(BindTransmitter)
+	client.SetSystemType("gosms")
+	client.SetRegisteredDelivery(RegisteredDeliveryReceipt)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	splitter := gosms.NewSplitter()
+	splitter.SetMessageBytes(40)
+	smsParts, err := splitter.Split("TextNow", []string{"+14155550123"}, "This message is long enough that it has to be split into several parts.")
+	assert.NoError(t, err)
+
+	messageIDs, err := client.SubmitAll(smsParts)
+	assert.NoError(t, err)
+	assert.Equal(t, len(smsParts), len(messageIDs))
+
+	binds := smsc.getReceived(CommandBindTransmitter)
+	assert.Equal(t, 1, len(binds))
+	bind := binds[0].(*Bind)
+	assert.Equal(t, "system", bind.SystemID)
+	assert.Equal(t, "secret", bind.Password)
+	assert.Equal(t, "gosms", bind.SystemType)
+	assert.Equal(t, InterfaceVersion, bind.InterfaceVersion)
+
+	submits := smsc.getReceived(CommandSubmitSM)
+	assert.Equal(t, len(smsParts), len(submits))
+	contents := map[string]string{}
+	for _, pdu := range submits {
+		submit := pdu.(*SubmitSM)
+		assert.Equal(t, ESMClassUDHI, submit.ESMClass)
+		assert.Equal(t, RegisteredDeliveryReceipt, submit.RegisteredDelivery)
+		sms, err := submit.GetSMS()
+		assert.NoError(t, err)
+		contents[fmt.Sprintf("id%d", submit.SequenceNumber)] = sms.GetContent()
+	}
+	// message IDs are returned in the order of the parts
+	for idx, messageID := range messageIDs {
+		assert.Equal(t, smsParts[idx].GetContent(), contents[messageID])
+	}
+}
+
+func TestClientKeepsWithinWindow(t *testing.T) {
+	var mutex sync.Mutex
+	outstanding, maxOutstanding := 0, 0
+
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		if _, ok := pdu.(*SubmitSM); !ok {
+			return defaultResponse(pdu)
+		}
+		mutex.Lock()
+		outstanding++
+		if outstanding > maxOutstanding {
+			maxOutstanding = outstanding
+		}
+		mutex.Unlock()
+
+		// respond later, so that requests pile up
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			mutex.Lock()
+			outstanding--
+			mutex.Unlock()
+			s.write(conn, &SubmitSMResp{Header: Header{SequenceNumber: pdu.GetHeader().SequenceNumber}, MessageID: "id"})
+		}()
+		return nil
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.SetWindow(2))
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	splitter := gosms.NewSplitter()
+	splitter.SetMessageBytes(20)
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "This message is long enough that it has to be split into several parts.")
+	assert.NoError(t, err)
+	assert.True(t, len(smsParts) > 4)
+
+	_, err = client.SubmitAll(smsParts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, maxOutstanding)
+
+	// the parts reach the SMSC in order
+	submits := smsc.getReceived(CommandSubmitSM)
+	assert.Equal(t, len(smsParts), len(submits))
+	for idx, pdu := range submits {
+		assert.Equal(t, string(smsParts[idx].GetUDH()), string(pdu.(*SubmitSM).ShortMessage[:len(smsParts[idx].GetUDH())]))
+	}
+}
+
+func TestClientSubmitAllStopsAtFirstError(t *testing.T) {
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		if submit, ok := pdu.(*SubmitSM); ok && s.count(CommandSubmitSM) == 2 {
+			return &SubmitSMResp{Header: Header{CommandStatus: StatusInvalidDestinationAddress, SequenceNumber: submit.SequenceNumber}}
+		}
+		return defaultResponse(pdu)
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.SetWindow(1))
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	splitter := gosms.NewSplitter()
+	splitter.SetMessageBytes(20)
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "This message is long enough that it has to be split into several parts.")
+	assert.NoError(t, err)
+	assert.True(t, len(smsParts) > 4)
+
+	messageIDs, err := client.SubmitAll(smsParts)
+	assert.Equal(t, &CommandStatusError{commandStatus: StatusInvalidDestinationAddress}, err)
+	assert.NotEqual(t, "", messageIDs[0])
+	assert.Equal(t, "", messageIDs[1])
+	assert.Equal(t, 2, smsc.count(CommandSubmitSM))
+}
+
+func TestClientRejectsWindowOnceConnected(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	assert.Equal(t, ErrAlreadyConnected, client.SetWindow(2))
+	assert.Equal(t, DefaultWindow, cap(client.slots))
+}
+
+func TestClientConnectReturnsBindError(t *testing.T) {
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		return &BindResp{Header: Header{CommandStatus: StatusInvalidPassword}, BindType: BindTransceiver}
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+
+	err := client.Connect()
+
+	commandStatusError, ok := err.(*CommandStatusError)
+	assert.True(t, ok)
+	assert.Equal(t, StatusInvalidPassword, commandStatusError.GetCommandStatus())
+	assert.Equal(t, "the request failed with ESME_RINVPASWD", err.Error())
+	assert.NoError(t, client.Close())
+}
+
+func TestClientSendsEnquireLinkWhenIdle(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.SetEnquireLinkInterval(20 * time.Millisecond)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandEnquireLink) >= 2 }))
+	assert.Equal(t, 1, smsc.count(CommandBindTransceiver))
+}
+
+func TestClientRebindsWhenEnquireLinkFails(t *testing.T) {
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		if _, ok := pdu.(*EnquireLink); ok {
+			return nil
+		}
+		return defaultResponse(pdu)
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.SetEnquireLinkInterval(20 * time.Millisecond)
+	client.SetResponseTimeout(50 * time.Millisecond)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandBindTransceiver) >= 2 }))
+}
+
+func TestClientReconnectsAfterDrop(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	smsc.drop()
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandBindTransceiver) == 2 }))
+
+	splitter := gosms.NewSplitter()
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+	assert.NoError(t, err)
+	messageID, err := client.Submit(smsParts[0])
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", messageID)
+}
+
+func TestClientRetriesWhenThrottled(t *testing.T) {
+	var mutex sync.Mutex
+	throttled := false
+
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if _, ok := pdu.(*SubmitSM); ok && !throttled {
+			throttled = true
+			return &SubmitSMResp{Header: Header{CommandStatus: StatusThrottled}}
+		}
+		return defaultResponse(pdu)
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	splitter := gosms.NewSplitter()
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+	assert.NoError(t, err)
+	start := time.Now()
+	messageID, err := client.Submit(smsParts[0])
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", messageID)
+	assert.Equal(t, 2, smsc.count(CommandSubmitSM))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestClientLimitsThrottleRetries(t *testing.T) {
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		if _, ok := pdu.(*SubmitSM); ok {
+			return &SubmitSMResp{Header: Header{CommandStatus: StatusThrottled}}
+		}
+		return defaultResponse(pdu)
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.SetThrottleDelay(time.Millisecond)
+	client.SetMaxThrottleRetries(2)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	splitter := gosms.NewSplitter()
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+	assert.NoError(t, err)
+	_, err = client.Submit(smsParts[0])
+
+	assert.Equal(t, &CommandStatusError{commandStatus: StatusThrottled}, err)
+	assert.Equal(t, 3, smsc.count(CommandSubmitSM))
+}
+
+func TestClientSubmitReturnsError(t *testing.T) {
+	var TestClientSubmitReturnsError = []struct {
+		name     string
+		response func(s *testSMSC, conn net.Conn) PDU
+		expected error
+	}{
+		{"rejected", func(s *testSMSC, conn net.Conn) PDU {
+			return &SubmitSMResp{Header: Header{CommandStatus: StatusInvalidDestinationAddress}}
+		}, &CommandStatusError{commandStatus: StatusInvalidDestinationAddress}},
+		{"generic_nack", func(s *testSMSC, conn net.Conn) PDU {
+			return &GenericNack{Header: Header{CommandStatus: StatusInvalidCommandLength}}
+		}, &CommandStatusError{commandStatus: StatusInvalidCommandLength}},
+		{"wrong response", func(s *testSMSC, conn net.Conn) PDU {
+			return &EnquireLinkResp{}
+		}, ErrUnexpectedResponse},
+		{"no response", func(s *testSMSC, conn net.Conn) PDU {
+			return nil
+		}, ErrResponseTimeout},
+		{"connection lost", func(s *testSMSC, conn net.Conn) PDU {
+			conn.Close()
+			return nil
+		}, ErrConnectionLost},
+	}
+
+	for _, tt := range TestClientSubmitReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+				if _, ok := pdu.(*SubmitSM); ok {
+					return tt.response(s, conn)
+				}
+				return defaultResponse(pdu)
+			})
+			defer smsc.close()
+			client := newTestClient(smsc)
+			client.SetResponseTimeout(100 * time.Millisecond)
+			assert.NoError(t, client.Connect())
+			defer client.Close()
+
+			splitter := gosms.NewSplitter()
+			smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+			assert.NoError(t, err)
+			_, err = client.Submit(smsParts[0])
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestClientSurfacesDeliveries(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	smsc.write(smsc.getConn(), &DeliverSM{
+		Header: Header{SequenceNumber: 77},
+		Message: Message{
+			ESMClass:     ESMClassDeliveryReceipt,
+			ShortMessage: []byte("id:abc sub:001 dlvrd:001 submit date:2101021504 done date:2101021505 stat:DELIVRD err:000 text:hello"),
+		},
+	})
+
+	select {
+	case deliver := <-client.Deliveries():
+		assert.True(t, deliver.IsReceipt())
+		receipt, err := deliver.GetReceipt()
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", receipt.GetMessageID())
+		assert.Equal(t, gosms.DeliveryStateDelivered, receipt.GetState())
+	case <-time.After(time.Second):
+		assert.Fail(t, "no delivery")
+	}
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandDeliverSMResp) == 1 }))
+	assert.Equal(t, uint32(77), smsc.getReceived(CommandDeliverSMResp)[0].GetHeader().SequenceNumber)
+}
+
+func TestClientRejectsDeliveriesWhenFull(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.deliveries = make(chan *DeliverSM, 1)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	for idx := uint32(1); idx <= 3; idx++ {
+		smsc.write(smsc.getConn(), &DeliverSM{Header: Header{SequenceNumber: idx}, Message: Message{ShortMessage: []byte("hi")}})
+	}
+
+	// responses keep flowing while nobody reads the deliveries
+	splitter := gosms.NewSplitter()
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+	assert.NoError(t, err)
+	_, err = client.Submit(smsParts[0])
+	assert.NoError(t, err)
+
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandDeliverSMResp) == 3 }))
+	var statuses []CommandStatus
+	for _, pdu := range smsc.getReceived(CommandDeliverSMResp) {
+		statuses = append(statuses, pdu.GetHeader().CommandStatus)
+	}
+	assert.Equal(t, []CommandStatus{StatusOK, StatusMessageQueueFull, StatusMessageQueueFull}, statuses)
+	assert.Equal(t, 2, client.GetRejectedDeliveries())
+	assert.Equal(t, uint32(1), (<-client.Deliveries()).SequenceNumber)
+}
+
+func TestClientAnswersSMSCRequests(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	conn := smsc.getConn()
+	smsc.write(conn, &EnquireLink{Header: Header{SequenceNumber: 5}})
+	// a query_sm, which the client does not support
+	smsc.mutex.Lock()
+	conn.Write([]byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06})
+	smsc.mutex.Unlock()
+
+	assert.True(t, waitFor(func() bool { return smsc.count(CommandGenericNack) == 1 }))
+	assert.Equal(t, 1, smsc.count(CommandEnquireLinkResp))
+	nack := smsc.getReceived(CommandGenericNack)[0]
+	assert.Equal(t, StatusInvalidCommandID, nack.GetHeader().CommandStatus)
+	assert.Equal(t, uint32(6), nack.GetHeader().SequenceNumber)
+}
+
+func TestClientClose(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, client.Close())
+
+	assert.Equal(t, 1, smsc.count(CommandUnbind))
+	_, open := <-client.Deliveries()
+	assert.False(t, open)
+
+	splitter := gosms.NewSplitter()
+	smsParts, err := splitter.Split("12345", []string{"+14155550123"}, "hello")
+	assert.NoError(t, err)
+	_, err = client.Submit(smsParts[0])
+	assert.Equal(t, ErrClientClosed, err)
+}
+
+func TestClientCloseLimitsUnbindWait(t *testing.T) {
+	smsc := newTestSMSC(t, func(s *testSMSC, conn net.Conn, pdu PDU) PDU {
+		if _, ok := pdu.(*Unbind); ok {
+			return nil
+		}
+		return defaultResponse(pdu)
+	})
+	defer smsc.close()
+	client := newTestClient(smsc)
+	client.SetUnbindTimeout(20 * time.Millisecond)
+	assert.NoError(t, client.Connect())
+
+	start := time.Now()
+	assert.NoError(t, client.Close())
+	assert.True(t, time.Since(start) < 250*time.Millisecond)
+	assert.Equal(t, 1, smsc.count(CommandUnbind))
+}
+
+func TestClientConnectAfterClose(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Close())
+
+	assert.Equal(t, ErrClientClosed, client.Connect())
+	assert.Equal(t, 0, smsc.count(CommandBindTransceiver))
+}
+
+func TestClientCloseWaitsForConnect(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+
+	connected := make(chan error)
+	go func() { connected <- client.Connect() }()
+	assert.NoError(t, client.Close())
+
+	err := <-connected
+	assert.True(t, err == nil || err == ErrClientClosed)
+	_, open := <-client.Deliveries()
+	assert.False(t, open)
+}
+
+func TestClientRejectsSecondConnect(t *testing.T) {
+	smsc := newTestSMSC(t, nil)
+	defer smsc.close()
+	client := newTestClient(smsc)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	assert.Equal(t, ErrAlreadyConnected, client.Connect())
+	assert.Equal(t, 1, smsc.count(CommandBindTransceiver))
+}
@@ -0,0 +1,397 @@
+package smpp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/textnow/gosms"
+)
+
+// ErrLatin1MessageClass indicates that a Latin-1 SMS has a message class, which SMPP cannot carry
+var ErrLatin1MessageClass = errors.New("Latin-1 messages cannot have a message class")
+
+const (
+	// ESMClassDeliveryReceipt marks a deliver_sm as an SMSC delivery receipt
+	ESMClassDeliveryReceipt byte = 0x04
+
+	// ESMClassUDHI indicates that the short_message starts with a UDH
+	ESMClassUDHI byte = 0x40
+
+	// ESMClassReplyPath requests a reply path
+	ESMClassReplyPath byte = 0x80
+
+	// RegisteredDeliveryReceipt requests a delivery receipt for the final outcome of a message
+	RegisteredDeliveryReceipt byte = 0x01
+
+	// RegisteredDeliveryFailureReceipt requests a delivery receipt only if a message fails
+	RegisteredDeliveryFailureReceipt byte = 0x02
+
+	// DataCodingDefault is the SMSC default alphabet, which is taken to be GSM
+	DataCodingDefault byte = 0x00
+
+	// DataCodingBinary is 8-bit binary data
+	DataCodingBinary byte = 0x04
+
+	// DataCodingLatin1 is ISO-8859-1
+	DataCodingLatin1 byte = 0x03
+
+	// DataCodingUCS2 is UCS-2
+	DataCodingUCS2 byte = 0x08
+
+	dataCodingBinaryAlias     byte = 0x02
+	dataCodingClassStart      byte = 0x10
+	dataCodingSharedEnd       byte = 0x40
+	dataCodingGSMControlStart byte = 0xC0
+
+	maxServiceTypeLength  int = 6
+	maxAddressLength      int = 21
+	maxTimeLength         int = 17
+	maxMessageIDLength    int = 65
+	maxShortMessageLength int = 254
+)
+
+// Message holds the fields shared by submit_sm and deliver_sm
+type Message struct {
+	ServiceType          string
+	SourceAddrTON        gosms.TypeOfNumber
+	SourceAddrNPI        gosms.NumberingPlan
+	SourceAddr           string
+	DestAddrTON          gosms.TypeOfNumber
+	DestAddrNPI          gosms.NumberingPlan
+	DestinationAddr      string
+	ESMClass             byte
+	ProtocolID           byte
+	PriorityFlag         byte
+	ScheduleDeliveryTime string
+	ValidityPeriod       string
+	RegisteredDelivery   byte
+	ReplaceIfPresentFlag byte
+	DataCoding           byte
+	SMDefaultMsgID       byte
+	ShortMessage         []byte
+	TLVs                 TLVs
+}
+
+// newMessage converts an SMS generated by a gosms Splitter into the fields of
+// a submit_sm. National numbers are interpreted using defaultRegion, and the
+// recipient cannot be alphanumeric.
+func newMessage(sms gosms.SMS, defaultRegion string) (Message, error) {
+	source, err := gosms.ParseAddress(sms.GetFrom(), defaultRegion)
+	if err != nil {
+		return Message{}, err
+	}
+	destination, err := gosms.ParseAddress(sms.GetTo(), defaultRegion)
+	if err != nil {
+		return Message{}, err
+	}
+	if destination.GetType() == gosms.AddressTypeAlphanumeric {
+		return Message{}, gosms.ErrAlphanumericRecipient
+	}
+
+	dataCoding, err := getDataCoding(sms)
+	if err != nil {
+		return Message{}, err
+	}
+	content := sms.GetBytes()
+	// GSM is sent as one septet per octet, which the SMSC packs
+	if gsm, isGSM := sms.GetEncoder().(*gosms.GSM); isGSM {
+		if content, err = gsm.EncodeSeptets(sms.GetContent()); err != nil {
+			return Message{}, err
+		}
+	}
+
+	message := Message{
+		SourceAddrTON:   source.GetTypeOfNumber(),
+		SourceAddrNPI:   source.GetNumberingPlan(),
+		SourceAddr:      source.GetDigits(),
+		DestAddrTON:     destination.GetTypeOfNumber(),
+		DestAddrNPI:     destination.GetNumberingPlan(),
+		DestinationAddr: destination.GetDigits(),
+		DataCoding:      dataCoding,
+		ShortMessage:    append([]byte(sms.GetUDH()), content...),
+	}
+	if sms.GetUDH() != "" {
+		message.ESMClass |= ESMClassUDHI
+	}
+	if segment, ok := sms.GetSegment(); ok {
+		message.TLVs = message.TLVs.
+			Set(NewUint16TLV(TagSARMessageReference, segment.Reference)).
+			Set(NewUint8TLV(TagSARTotalSegments, segment.Total)).
+			Set(NewUint8TLV(TagSARSegmentSequence, segment.Sequence))
+	}
+	if sms.GetConcatenation() == gosms.ConcatenationPayload {
+		message.TLVs = message.TLVs.Set(TLV{Tag: TagMessagePayload, Value: message.ShortMessage})
+		message.ShortMessage = nil
+	}
+	return message, nil
+}
+
+// GetSMS decodes the short_message, or the message_payload TLV if there is no
+// short_message, into an SMS which can be passed to a gosms Reassembler. Parts
+// numbered with the sar_* TLVs are given a segment. International numbers are
+// given a plus sign.
+func (m *Message) GetSMS() (gosms.SMS, error) {
+	sms, err := m.parseSMS()
+	if err != nil {
+		return gosms.SMS{}, err
+	}
+
+	if segment, ok := m.getSegment(); ok {
+		sms.SetSegment(segment)
+	}
+	return sms, nil
+}
+
+// parseSMS decodes the content of the Message
+func (m *Message) parseSMS() (gosms.SMS, error) {
+	dcs, err := parseDataCoding(m.DataCoding)
+	if err != nil {
+		return gosms.SMS{}, err
+	}
+	from := formatAddress(m.SourceAddrTON, m.SourceAddr)
+	to := formatAddress(m.DestAddrTON, m.DestinationAddr)
+	hasUDH := m.ESMClass&ESMClassUDHI != 0
+
+	content := m.ShortMessage
+	if tlv, ok := m.TLVs.Get(TagMessagePayload); ok && len(content) == 0 {
+		content = tlv.Value
+	}
+
+	if dcs.GetCharacterSet() != gosms.CharacterSetGSM {
+		return gosms.ParseUserData(from, to, dcs, hasUDH, len(content), content)
+	}
+
+	// pack the septets after the UDH, as they would be in a TPDU
+	var udhLength int
+	if hasUDH {
+		if len(content) == 0 || int(content[0]) >= len(content) {
+			return gosms.SMS{}, gosms.ErrInvalidUDH
+		}
+		udhLength = int(content[0]) + 1
+	}
+	septets := content[udhLength:]
+	userData := append(append([]byte{}, content[:udhLength]...), gosms.PackSeptets(septets, gosms.FillBits(udhLength))...)
+	length := gosms.UDHSeptets(udhLength) + len(septets)
+	return gosms.ParseUserData(from, to, dcs, hasUDH, length, userData)
+}
+
+// getSegment returns the numbering given by the sar_* TLVs, and false if they are missing
+func (m *Message) getSegment() (gosms.Concatenated16Bit, bool) {
+	var segment gosms.Concatenated16Bit
+
+	referenceTLV, hasReference := m.TLVs.Get(TagSARMessageReference)
+	totalTLV, hasTotal := m.TLVs.Get(TagSARTotalSegments)
+	sequenceTLV, hasSequence := m.TLVs.Get(TagSARSegmentSequence)
+	if !hasReference || !hasTotal || !hasSequence {
+		return segment, false
+	}
+
+	reference, referenceOK := referenceTLV.GetUint16()
+	total, totalOK := totalTLV.GetUint8()
+	sequence, sequenceOK := sequenceTLV.GetUint8()
+	if !referenceOK || !totalOK || !sequenceOK {
+		return segment, false
+	}
+	segment = gosms.Concatenated16Bit{Reference: reference, Total: total, Sequence: sequence}
+	return segment, true
+}
+
+// writeMessage appends the fields shared by submit_sm and deliver_sm
+func (w *pduWriter) writeMessage(m *Message) {
+	if len(m.ShortMessage) > maxShortMessageLength {
+		w.err = ErrFieldTooLong
+		return
+	}
+	w.writeCString(m.ServiceType, maxServiceTypeLength)
+	w.writeByte(byte(m.SourceAddrTON))
+	w.writeByte(byte(m.SourceAddrNPI))
+	w.writeCString(m.SourceAddr, maxAddressLength)
+	w.writeByte(byte(m.DestAddrTON))
+	w.writeByte(byte(m.DestAddrNPI))
+	w.writeCString(m.DestinationAddr, maxAddressLength)
+	w.writeByte(m.ESMClass)
+	w.writeByte(m.ProtocolID)
+	w.writeByte(m.PriorityFlag)
+	w.writeCString(m.ScheduleDeliveryTime, maxTimeLength)
+	w.writeCString(m.ValidityPeriod, maxTimeLength)
+	w.writeByte(m.RegisteredDelivery)
+	w.writeByte(m.ReplaceIfPresentFlag)
+	w.writeByte(m.DataCoding)
+	w.writeByte(m.SMDefaultMsgID)
+	w.writeByte(byte(len(m.ShortMessage)))
+	w.writeBytes(m.ShortMessage)
+	w.writeTLVs(m.TLVs)
+}
+
+// readMessage consumes the fields shared by submit_sm and deliver_sm
+func (r *pduReader) readMessage() Message {
+	var m Message
+
+	m.ServiceType = r.readCString(maxServiceTypeLength)
+	m.SourceAddrTON = gosms.TypeOfNumber(r.readByte())
+	m.SourceAddrNPI = gosms.NumberingPlan(r.readByte())
+	m.SourceAddr = r.readCString(maxAddressLength)
+	m.DestAddrTON = gosms.TypeOfNumber(r.readByte())
+	m.DestAddrNPI = gosms.NumberingPlan(r.readByte())
+	m.DestinationAddr = r.readCString(maxAddressLength)
+	m.ESMClass = r.readByte()
+	m.ProtocolID = r.readByte()
+	m.PriorityFlag = r.readByte()
+	m.ScheduleDeliveryTime = r.readCString(maxTimeLength)
+	m.ValidityPeriod = r.readCString(maxTimeLength)
+	m.RegisteredDelivery = r.readByte()
+	m.ReplaceIfPresentFlag = r.readByte()
+	m.DataCoding = r.readByte()
+	m.SMDefaultMsgID = r.readByte()
+	m.ShortMessage = r.readBytes(int(r.readByte()))
+	m.TLVs = r.readTLVs()
+	return m
+}
+
+// getDataCoding returns the data_coding for the encoder and message class of
+// sms. Messages with a class use their TP-DCS, which SMPP shares. Latin-1 has
+// no TP-DCS, so it cannot be given a class.
+func getDataCoding(sms gosms.SMS) (byte, error) {
+	dcs, err := sms.GetDataCoding()
+	if err != nil {
+		return 0, err
+	}
+
+	switch dcs.GetCharacterSet() {
+	case gosms.CharacterSet8Bit:
+		if dcs.GetMessageClass() != gosms.MessageClassNone {
+			return 0, ErrLatin1MessageClass
+		}
+		return DataCodingLatin1, nil
+	case gosms.CharacterSetUCS2:
+		if dcs.GetMessageClass() == gosms.MessageClassNone {
+			return DataCodingUCS2, nil
+		}
+	}
+	return dcs.Marshal()
+}
+
+// parseDataCoding returns the TP-DCS equivalent of a data_coding
+func parseDataCoding(dataCoding byte) (gosms.DCS, error) {
+	switch dataCoding {
+	case DataCodingDefault:
+		return gosms.NewDCS(gosms.CharacterSetGSM, gosms.MessageClassNone), nil
+	case DataCodingLatin1, DataCodingBinary, dataCodingBinaryAlias:
+		return gosms.NewDCS(gosms.CharacterSet8Bit, gosms.MessageClassNone), nil
+	case DataCodingUCS2:
+		return gosms.NewDCS(gosms.CharacterSetUCS2, gosms.MessageClassNone), nil
+	}
+
+	// the general coding groups with a message class, and the GSM message
+	// waiting and message class groups, are shared with TP-DCS
+	var dcs gosms.DCS
+	if dataCoding < dataCodingClassStart || (dataCoding >= dataCodingSharedEnd && dataCoding < dataCodingGSMControlStart) {
+		return dcs, gosms.ErrUnsupportedDataCoding
+	}
+	err := dcs.Unmarshal(dataCoding)
+	return dcs, err
+}
+
+// formatAddress returns an SMPP address in the form used by gosms
+func formatAddress(typeOfNumber gosms.TypeOfNumber, address string) string {
+	if typeOfNumber == gosms.TypeOfNumberInternational && address != "" && !strings.HasPrefix(address, "+") {
+		return "+" + address
+	}
+	return address
+}
+
+// SubmitSM submits a short message to an SMSC
+type SubmitSM struct {
+	Header
+	Message
+}
+
+// NewSubmitSM converts an SMS generated by a gosms Splitter into a SubmitSM,
+// setting the UDHI flag of the esm_class for SMS with a UDH and the data_coding
+// of its encoder. National numbers are interpreted using defaultRegion, an ISO
+// 3166-1 alpha-2 code such as "US", and the recipient cannot be alphanumeric.
+func NewSubmitSM(sms gosms.SMS, defaultRegion string) (*SubmitSM, error) {
+	message, err := newMessage(sms, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+	return &SubmitSM{Message: message}, nil
+}
+
+// GetCommandID returns CommandSubmitSM
+func (p *SubmitSM) GetCommandID() CommandID {
+	return CommandSubmitSM
+}
+
+// marshalBody encodes the body of the SubmitSM
+func (p *SubmitSM) marshalBody(w *pduWriter) {
+	w.writeMessage(&p.Message)
+}
+
+// unmarshalBody decodes the body of the SubmitSM
+func (p *SubmitSM) unmarshalBody(r *pduReader) {
+	p.Message = r.readMessage()
+}
+
+// SubmitSMResp is the response to a SubmitSM
+type SubmitSMResp struct {
+	Header
+	MessageID string
+}
+
+// GetCommandID returns CommandSubmitSMResp
+func (p *SubmitSMResp) GetCommandID() CommandID {
+	return CommandSubmitSMResp
+}
+
+// marshalBody encodes the body of the SubmitSMResp
+func (p *SubmitSMResp) marshalBody(w *pduWriter) {
+	w.writeCString(p.MessageID, maxMessageIDLength)
+}
+
+// unmarshalBody decodes the body of the SubmitSMResp
+func (p *SubmitSMResp) unmarshalBody(r *pduReader) {
+	p.MessageID = r.readCString(maxMessageIDLength)
+}
+
+// DeliverSM delivers a short message or delivery receipt from an SMSC
+type DeliverSM struct {
+	Header
+	Message
+}
+
+// GetCommandID returns CommandDeliverSM
+func (p *DeliverSM) GetCommandID() CommandID {
+	return CommandDeliverSM
+}
+
+// marshalBody encodes the body of the DeliverSM
+func (p *DeliverSM) marshalBody(w *pduWriter) {
+	w.writeMessage(&p.Message)
+}
+
+// unmarshalBody decodes the body of the DeliverSM
+func (p *DeliverSM) unmarshalBody(r *pduReader) {
+	p.Message = r.readMessage()
+}
+
+// DeliverSMResp is the response to a DeliverSM
+type DeliverSMResp struct {
+	Header
+	MessageID string
+}
+
+// GetCommandID returns CommandDeliverSMResp
+func (p *DeliverSMResp) GetCommandID() CommandID {
+	return CommandDeliverSMResp
+}
+
+// marshalBody encodes the body of the DeliverSMResp
+func (p *DeliverSMResp) marshalBody(w *pduWriter) {
+	w.writeCString(p.MessageID, maxMessageIDLength)
+}
+
+// unmarshalBody decodes the body of the DeliverSMResp
+func (p *DeliverSMResp) unmarshalBody(r *pduReader) {
+	p.MessageID = r.readCString(maxMessageIDLength)
+}
@@ -0,0 +1,322 @@
+package smpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+)
+
+const multipartMessage = "This message has [extended] characters and is long enough to be split into parts."
+
+// splitMessage splits message from from to to with encoder
+func splitMessage(t *testing.T, encoder gosms.Encoder, messageClass gosms.MessageClass, from string, to string, message string) []gosms.SMS {
+	splitter := gosms.NewSplitter()
+	splitter.SetEncoder(encoder)
+	splitter.SetMessageBytes(40)
+	splitter.SetMessageClass(messageClass)
+
+	smsParts, err := splitter.Split(from, []string{to}, message)
+	assert.NoError(t, err)
+	return smsParts
+}
+
+func TestNewSubmitSM(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), gosms.MessageClassNone, "TextNow", "+14155550123", multipartMessage)
+	assert.True(t, len(smsParts) > 1)
+
+	submit, err := NewSubmitSM(smsParts[0], "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, gosms.TypeOfNumberAlphanumeric, submit.SourceAddrTON)
+	assert.Equal(t, gosms.NumberingPlanUnknown, submit.SourceAddrNPI)
+	assert.Equal(t, "TextNow", submit.SourceAddr)
+	assert.Equal(t, gosms.TypeOfNumberInternational, submit.DestAddrTON)
+	assert.Equal(t, gosms.NumberingPlanISDN, submit.DestAddrNPI)
+	assert.Equal(t, "14155550123", submit.DestinationAddr)
+	assert.Equal(t, ESMClassUDHI, submit.ESMClass)
+	assert.Equal(t, DataCodingDefault, submit.DataCoding)
+
+	// the UDH is followed by one septet per octet
+	udh := smsParts[0].GetUDH()
+	assert.Equal(t, []byte(udh), submit.ShortMessage[:len(udh)])
+	assert.Equal(t, []byte("This message has "), submit.ShortMessage[len(udh):len(udh)+17])
+	assert.Equal(t, []byte{0x1B, 0x3C}, submit.ShortMessage[len(udh)+17:len(udh)+19])
+}
+
+func TestNewSubmitSMNationalNumbers(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), gosms.MessageClassNone, "4155550100", "(415) 555-0123", "hi")
+
+	submit, err := NewSubmitSM(smsParts[0], "US")
+
+	assert.NoError(t, err)
+	assert.Equal(t, gosms.TypeOfNumberInternational, submit.SourceAddrTON)
+	assert.Equal(t, "14155550100", submit.SourceAddr)
+	assert.Equal(t, gosms.TypeOfNumberInternational, submit.DestAddrTON)
+	assert.Equal(t, "14155550123", submit.DestinationAddr)
+}
+
+func TestNewSubmitSMDataCoding(t *testing.T) {
+	var TestNewSubmitSMDataCoding = []struct {
+		name               string
+		encoder            gosms.Encoder
+		messageClass       gosms.MessageClass
+		message            string
+		expectedDataCoding byte
+		expectedContent    []byte
+	}{
+		{"GSM", gosms.NewGSM(), gosms.MessageClassNone, "hi@", DataCodingDefault, []byte{'h', 'i', 0x00}},
+		{"UTF-16", gosms.NewUTF16(), gosms.MessageClassNone, "hi", DataCodingUCS2, []byte{0x00, 'h', 0x00, 'i'}},
+		{"Latin-1", gosms.NewLatin1(), gosms.MessageClassNone, "hé", DataCodingLatin1, []byte{'h', 0xE9}},
+		{"flash GSM", gosms.NewGSM(), gosms.MessageClass0, "hi", 0x10, []byte{'h', 'i'}},
+		{"SIM specific GSM", gosms.NewGSM(), gosms.MessageClass2, "hi", 0x12, []byte{'h', 'i'}},
+		{"flash UCS-2", gosms.NewUTF16(), gosms.MessageClass0, "hi", 0x18, []byte{0x00, 'h', 0x00, 'i'}},
+	}
+
+	for _, tt := range TestNewSubmitSMDataCoding {
+		t.Run(tt.name, func(t *testing.T) {
+			smsParts := splitMessage(t, tt.encoder, tt.messageClass, "12345", "+14155550123", tt.message)
+			assert.Equal(t, 1, len(smsParts))
+
+			submit, err := NewSubmitSM(smsParts[0], "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDataCoding, submit.DataCoding)
+			assert.Equal(t, byte(0x00), submit.ESMClass)
+			assert.Equal(t, tt.expectedContent, submit.ShortMessage)
+			assert.Equal(t, gosms.TypeOfNumberUnknown, submit.SourceAddrTON)
+			assert.Equal(t, "12345", submit.SourceAddr)
+		})
+	}
+}
+
+func TestNewSubmitSMReturnsError(t *testing.T) {
+	var TestNewSubmitSMReturnsError = []struct {
+		name         string
+		encoder      gosms.Encoder
+		messageClass gosms.MessageClass
+		from         string
+		to           string
+		expected     error
+	}{
+		{"alphanumeric recipient", gosms.NewGSM(), gosms.MessageClassNone, "12345", "TextNow", gosms.ErrAlphanumericRecipient},
+		{"national sender", gosms.NewGSM(), gosms.MessageClassNone, "4155550123", "+14155550123", gosms.ErrUnsupportedRegion},
+		{"SIM specific Latin-1", gosms.NewLatin1(), gosms.MessageClass2, "12345", "+14155550123", ErrLatin1MessageClass},
+	}
+
+	for _, tt := range TestNewSubmitSMReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			smsParts := splitMessage(t, tt.encoder, tt.messageClass, tt.from, tt.to, "hi")
+
+			_, err := NewSubmitSM(smsParts[0], "")
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestSubmitSMRoundTrip(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), gosms.MessageClassNone, "TextNow", "+14155550123", multipartMessage)
+	submit, err := NewSubmitSM(smsParts[1], "")
+	assert.NoError(t, err)
+	submit.SequenceNumber = 3
+	submit.RegisteredDelivery = RegisteredDeliveryReceipt
+	submit.ValidityPeriod = "000001000000000R"
+	submit.TLVs = TLVs{NewUint16TLV(TagUserMessageReference, 7)}
+
+	data, err := Marshal(submit)
+	assert.NoError(t, err)
+	pdu, err := Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, submit, pdu)
+
+	decoded, err := pdu.(*SubmitSM).GetSMS()
+	assert.NoError(t, err)
+	assert.Equal(t, smsParts[1].GetContent(), decoded.GetContent())
+	assert.Equal(t, smsParts[1].GetUDH(), decoded.GetUDH())
+	assert.Equal(t, "TextNow", decoded.GetFrom())
+	assert.Equal(t, "+14155550123", decoded.GetTo())
+}
+
+func TestSubmitSMMarshalReturnsErrorForLongShortMessage(t *testing.T) {
+	submit := &SubmitSM{Message: Message{ShortMessage: make([]byte, maxShortMessageLength+1)}}
+
+	_, err := Marshal(submit)
+
+	assert.Equal(t, ErrFieldTooLong, err)
+}
+
+func TestDeliverSMReassembly(t *testing.T) {
+	turkish, _ := gosms.NewNationalGSM(gosms.NationalLanguageTurkish, gosms.NationalLanguageTurkish)
+
+	var TestDeliverSMReassembly = []struct {
+		name    string
+		encoder gosms.Encoder
+		message string
+	}{
+		{"GSM", gosms.NewGSM(), multipartMessage},
+		{"GSM national language", turkish, "Şişli'de buluşalım mı? Saat üçte geliyorum, gecikirsem ararım."},
+		{"UTF-16", gosms.NewUTF16(), "This message has emoji 😀 and is long enough to be split into parts."},
+		{"Latin-1", gosms.NewLatin1(), "Ce message est assez long pour être découpé en plusieurs parties."},
+	}
+
+	for _, tt := range TestDeliverSMReassembly {
+		t.Run(tt.name, func(t *testing.T) {
+			smsParts := splitMessage(t, tt.encoder, gosms.MessageClassNone, "+14155550123", "12345", tt.message)
+			assert.True(t, len(smsParts) > 1)
+
+			reassembler := gosms.NewReassembler()
+			var message string
+			var complete bool
+			for idx := len(smsParts) - 1; idx >= 0; idx-- {
+				submit, err := NewSubmitSM(smsParts[idx], "")
+				assert.NoError(t, err)
+				data, err := Marshal(&DeliverSM{Header: Header{SequenceNumber: uint32(idx)}, Message: submit.Message})
+				assert.NoError(t, err)
+
+				pdu, err := Unmarshal(data)
+				assert.NoError(t, err)
+				sms, err := pdu.(*DeliverSM).GetSMS()
+				assert.NoError(t, err)
+				assert.Equal(t, "+14155550123", sms.GetFrom())
+				assert.Equal(t, smsParts[idx].GetContent(), sms.GetContent())
+
+				message, complete, err = reassembler.Add(sms)
+				assert.NoError(t, err)
+			}
+			assert.True(t, complete)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestDeliverSMGetSMS(t *testing.T) {
+	var TestDeliverSMGetSMS = []struct {
+		name                 string
+		dataCoding           byte
+		shortMessage         []byte
+		expectedContent      string
+		expectedMessageClass gosms.MessageClass
+	}{
+		{"GSM ending with @", DataCodingDefault, []byte{'h', 'i', 0x00}, "hi@", gosms.MessageClassNone},
+		{"binary", DataCodingBinary, []byte{'h', 'i'}, "hi", gosms.MessageClassNone},
+		{"binary alias", 0x02, []byte{'h', 'i'}, "hi", gosms.MessageClassNone},
+		{"flash", 0xF0, []byte{'h', 'i'}, "hi", gosms.MessageClass0},
+		{"flash general group", 0x10, []byte{'h', 'i'}, "hi", gosms.MessageClass0},
+		{"flash UCS-2", 0x18, []byte{0x00, 'h', 0x00, 'i'}, "hi", gosms.MessageClass0},
+		{"message waiting", 0xC8, []byte{'h', 'i'}, "hi", gosms.MessageClassNone},
+		{"empty", DataCodingDefault, []byte{}, "", gosms.MessageClassNone},
+	}
+
+	for _, tt := range TestDeliverSMGetSMS {
+		t.Run(tt.name, func(t *testing.T) {
+			deliver := &DeliverSM{Message: Message{
+				SourceAddrTON: gosms.TypeOfNumberNational,
+				SourceAddr:    "4155550123",
+				DataCoding:    tt.dataCoding,
+				ShortMessage:  tt.shortMessage,
+			}}
+
+			sms, err := deliver.GetSMS()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, sms.GetContent())
+			assert.Equal(t, tt.expectedMessageClass, sms.GetMessageClass())
+			assert.Equal(t, "4155550123", sms.GetFrom())
+		})
+	}
+}
+
+func TestDeliverSMGetSMSReturnsError(t *testing.T) {
+	var TestDeliverSMGetSMSReturnsError = []struct {
+		name         string
+		dataCoding   byte
+		esmClass     byte
+		shortMessage []byte
+		expected     error
+	}{
+		{"IA5", 0x01, 0x00, []byte{'h', 'i'}, gosms.ErrUnsupportedDataCoding},
+		{"reserved", 0x80, 0x00, []byte{'h', 'i'}, gosms.ErrUnsupportedDataCoding},
+		{"missing UDH", DataCodingDefault, ESMClassUDHI, []byte{}, gosms.ErrInvalidUDH},
+		{"truncated UDH", DataCodingDefault, ESMClassUDHI, []byte{0x05, 0x00, 0x03}, gosms.ErrInvalidUDH},
+	}
+
+	for _, tt := range TestDeliverSMGetSMSReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			deliver := &DeliverSM{Message: Message{DataCoding: tt.dataCoding, ESMClass: tt.esmClass, ShortMessage: tt.shortMessage}}
+
+			_, err := deliver.GetSMS()
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestDeliverSMRespRoundTrip(t *testing.T) {
+	deliverResp := &DeliverSMResp{Header: Header{SequenceNumber: 9}}
+
+	data, err := Marshal(deliverResp)
+	assert.NoError(t, err)
+	assert.Equal(t, headerLength+1, len(data))
+
+	pdu, err := Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, deliverResp, pdu)
+}
+
+func TestNewSubmitSMConcatenation(t *testing.T) {
+	var TestNewSubmitSMConcatenation = []struct {
+		name          string
+		concatenation gosms.Concatenation
+		expectedParts int
+		expectedTags  []Tag
+	}{
+		{"UDH", gosms.ConcatenationUDH, 3, nil},
+		{"SAR", gosms.ConcatenationSAR, 2, []Tag{TagSARMessageReference, TagSARTotalSegments, TagSARSegmentSequence}},
+		{"payload", gosms.ConcatenationPayload, 1, []Tag{TagMessagePayload}},
+	}
+
+	for _, tt := range TestNewSubmitSMConcatenation {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := gosms.NewSplitter()
+			splitter.SetMessageBytes(40)
+			splitter.SetConcatenation(tt.concatenation)
+			smsParts, err := splitter.Split("TextNow", []string{"+14155550123"}, multipartMessage)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedParts, len(smsParts))
+
+			reassembler := gosms.NewReassembler()
+			var message string
+			var complete bool
+			for idx, sms := range smsParts {
+				submit, err := NewSubmitSM(sms, "")
+				assert.NoError(t, err)
+
+				var tags []Tag
+				for _, tlv := range submit.TLVs {
+					tags = append(tags, tlv.Tag)
+				}
+				assert.Equal(t, tt.expectedTags, tags)
+				assert.Equal(t, tt.concatenation == gosms.ConcatenationUDH, submit.ESMClass&ESMClassUDHI != 0)
+				if tt.concatenation == gosms.ConcatenationSAR {
+					total, _ := submit.TLVs[1].GetUint8()
+					sequence, _ := submit.TLVs[2].GetUint8()
+					assert.Equal(t, uint8(len(smsParts)), total)
+					assert.Equal(t, uint8(idx+1), sequence)
+				}
+				if tt.concatenation == gosms.ConcatenationPayload {
+					assert.Equal(t, 0, len(submit.ShortMessage))
+				}
+
+				data, err := Marshal(submit)
+				assert.NoError(t, err)
+				pdu, err := Unmarshal(data)
+				assert.NoError(t, err)
+				decoded, err := pdu.(*SubmitSM).GetSMS()
+				assert.NoError(t, err)
+				assert.Equal(t, sms.GetContent(), decoded.GetContent())
+
+				message, complete, err = reassembler.Add(decoded)
+				assert.NoError(t, err)
+			}
+			assert.True(t, complete)
+			assert.Equal(t, multipartMessage, message)
+		})
+	}
+}
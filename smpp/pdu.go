@@ -0,0 +1,405 @@
+// Package smpp encodes and decodes SMPP 3.4 PDUs for sending SMS parts
+// generated by a gosms Splitter to an SMSC
+package smpp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	headerLength int = 16
+
+	// MaxPDULength is the longest PDU ReadPDU accepts
+	MaxPDULength int = 64 * 1024
+)
+
+var (
+	// ErrInvalidPDU indicates that a PDU is truncated or malformed
+	ErrInvalidPDU = errors.New("the PDU is malformed")
+
+	// ErrFieldTooLong indicates that a field is longer than SMPP allows
+	ErrFieldTooLong = errors.New("the field is too long")
+)
+
+// CommandID identifies the type of a PDU
+type CommandID uint32
+
+const (
+	// CommandGenericNack identifies a generic_nack
+	CommandGenericNack CommandID = 0x80000000
+
+	// CommandBindReceiver identifies a bind_receiver
+	CommandBindReceiver CommandID = 0x00000001
+
+	// CommandBindReceiverResp identifies a bind_receiver_resp
+	CommandBindReceiverResp CommandID = 0x80000001
+
+	// CommandBindTransmitter identifies a bind_transmitter
+	CommandBindTransmitter CommandID = 0x00000002
+
+	// CommandBindTransmitterResp identifies a bind_transmitter_resp
+	CommandBindTransmitterResp CommandID = 0x80000002
+
+	// CommandSubmitSM identifies a submit_sm
+	CommandSubmitSM CommandID = 0x00000004
+
+	// CommandSubmitSMResp identifies a submit_sm_resp
+	CommandSubmitSMResp CommandID = 0x80000004
+
+	// CommandDeliverSM identifies a deliver_sm
+	CommandDeliverSM CommandID = 0x00000005
+
+	// CommandDeliverSMResp identifies a deliver_sm_resp
+	CommandDeliverSMResp CommandID = 0x80000005
+
+	// CommandUnbind identifies an unbind
+	CommandUnbind CommandID = 0x00000006
+
+	// CommandUnbindResp identifies an unbind_resp
+	CommandUnbindResp CommandID = 0x80000006
+
+	// CommandBindTransceiver identifies a bind_transceiver
+	CommandBindTransceiver CommandID = 0x00000009
+
+	// CommandBindTransceiverResp identifies a bind_transceiver_resp
+	CommandBindTransceiverResp CommandID = 0x80000009
+
+	// CommandEnquireLink identifies an enquire_link
+	CommandEnquireLink CommandID = 0x00000015
+
+	// CommandEnquireLinkResp identifies an enquire_link_resp
+	CommandEnquireLinkResp CommandID = 0x80000015
+
+	commandResponseMask CommandID = 0x80000000
+)
+
+// IsResponse returns true if the command is a response
+func (c CommandID) IsResponse() bool {
+	return c&commandResponseMask != 0
+}
+
+// CommandStatus is the command_status of a PDU, which is zero unless a request failed
+type CommandStatus uint32
+
+const (
+	// StatusOK means that the request succeeded
+	StatusOK CommandStatus = 0x00000000
+
+	// StatusInvalidMessageLength means that the short_message length is invalid
+	StatusInvalidMessageLength CommandStatus = 0x00000001
+
+	// StatusInvalidCommandLength means that the command_length is invalid
+	StatusInvalidCommandLength CommandStatus = 0x00000002
+
+	// StatusInvalidCommandID means that the command_id is unknown
+	StatusInvalidCommandID CommandStatus = 0x00000003
+
+	// StatusInvalidBindStatus means that the command is not allowed in the current bind state
+	StatusInvalidBindStatus CommandStatus = 0x00000004
+
+	// StatusAlreadyBound means that the ESME is already bound
+	StatusAlreadyBound CommandStatus = 0x00000005
+
+	// StatusSystemError means that the SMSC failed
+	StatusSystemError CommandStatus = 0x00000008
+
+	// StatusInvalidSourceAddress means that the source_addr is invalid
+	StatusInvalidSourceAddress CommandStatus = 0x0000000A
+
+	// StatusInvalidDestinationAddress means that the destination_addr is invalid
+	StatusInvalidDestinationAddress CommandStatus = 0x0000000B
+
+	// StatusBindFailed means that the bind was rejected
+	StatusBindFailed CommandStatus = 0x0000000D
+
+	// StatusInvalidPassword means that the password is wrong
+	StatusInvalidPassword CommandStatus = 0x0000000E
+
+	// StatusInvalidSystemID means that the system_id is wrong
+	StatusInvalidSystemID CommandStatus = 0x0000000F
+
+	// StatusMessageQueueFull means that the SMSC cannot queue more messages
+	StatusMessageQueueFull CommandStatus = 0x00000014
+
+	// StatusInvalidESMClass means that the esm_class is invalid
+	StatusInvalidESMClass CommandStatus = 0x00000043
+
+	// StatusSubmitFailed means that the submit_sm failed
+	StatusSubmitFailed CommandStatus = 0x00000045
+
+	// StatusThrottled means that the ESME is sending too quickly
+	StatusThrottled CommandStatus = 0x00000058
+
+	// StatusInvalidOptionalParameters means that the TLVs are malformed
+	StatusInvalidOptionalParameters CommandStatus = 0x000000C0
+
+	// StatusInvalidParameterLength means that a TLV has the wrong length
+	StatusInvalidParameterLength CommandStatus = 0x000000C2
+
+	// StatusDeliveryFailure means that a deliver_sm could not be handled
+	StatusDeliveryFailure CommandStatus = 0x000000FE
+
+	// StatusUnknownError means that the request failed for an unknown reason
+	StatusUnknownError CommandStatus = 0x000000FF
+)
+
+var commandStatusNames = map[CommandStatus]string{
+	StatusOK:                        "ESME_ROK",
+	StatusInvalidMessageLength:      "ESME_RINVMSGLEN",
+	StatusInvalidCommandLength:      "ESME_RINVCMDLEN",
+	StatusInvalidCommandID:          "ESME_RINVCMDID",
+	StatusInvalidBindStatus:         "ESME_RINVBNDSTS",
+	StatusAlreadyBound:              "ESME_RALYBND",
+	StatusSystemError:               "ESME_RSYSERR",
+	StatusInvalidSourceAddress:      "ESME_RINVSRCADR",
+	StatusInvalidDestinationAddress: "ESME_RINVDSTADR",
+	StatusBindFailed:                "ESME_RBINDFAIL",
+	StatusInvalidPassword:           "ESME_RINVPASWD",
+	StatusInvalidSystemID:           "ESME_RINVSYSID",
+	StatusMessageQueueFull:          "ESME_RMSGQFUL",
+	StatusInvalidESMClass:           "ESME_RINVESMCLASS",
+	StatusSubmitFailed:              "ESME_RSUBMITFAIL",
+	StatusThrottled:                 "ESME_RTHROTTLED",
+	StatusInvalidOptionalParameters: "ESME_RINVOPTPARSTREAM",
+	StatusInvalidParameterLength:    "ESME_RINVPARLEN",
+	StatusDeliveryFailure:           "ESME_RDELIVERYFAILURE",
+	StatusUnknownError:              "ESME_RUNKNOWNERR",
+}
+
+// String returns the SMPP name of the command status
+func (s CommandStatus) String() string {
+	if name, ok := commandStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%08X", uint32(s))
+}
+
+// Header holds the command_status and sequence_number shared by every PDU
+type Header struct {
+	CommandStatus  CommandStatus
+	SequenceNumber uint32
+}
+
+// GetHeader returns the header of the PDU
+func (h *Header) GetHeader() *Header {
+	return h
+}
+
+// PDU is an SMPP PDU
+type PDU interface {
+	GetCommandID() CommandID
+	GetHeader() *Header
+	marshalBody(w *pduWriter)
+	unmarshalBody(r *pduReader)
+}
+
+// UnknownCommandError indicates that a PDU has a command_id this package does not support
+type UnknownCommandError struct {
+	commandID      CommandID
+	sequenceNumber uint32
+}
+
+// Error returns the error message
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("the command ID 0x%08X is not supported", uint32(e.commandID))
+}
+
+// GetCommandID returns the unsupported command_id
+func (e *UnknownCommandError) GetCommandID() CommandID {
+	return e.commandID
+}
+
+// GetSequenceNumber returns the sequence_number of the PDU, which a generic_nack should echo
+func (e *UnknownCommandError) GetSequenceNumber() uint32 {
+	return e.sequenceNumber
+}
+
+// CommandStatusError indicates that the other end of a session rejected a request
+type CommandStatusError struct {
+	commandStatus CommandStatus
+}
+
+// Error returns the error message
+func (e *CommandStatusError) Error() string {
+	return fmt.Sprintf("the request failed with %s", e.commandStatus)
+}
+
+// GetCommandStatus returns the command_status of the response
+func (e *CommandStatusError) GetCommandStatus() CommandStatus {
+	return e.commandStatus
+}
+
+// newPDU returns an empty PDU for commandID, or nil if it is not supported
+func newPDU(commandID CommandID) PDU {
+	switch commandID {
+	case CommandGenericNack:
+		return &GenericNack{}
+	case CommandBindReceiver:
+		return &Bind{BindType: BindReceiver}
+	case CommandBindTransmitter:
+		return &Bind{BindType: BindTransmitter}
+	case CommandBindTransceiver:
+		return &Bind{BindType: BindTransceiver}
+	case CommandBindReceiverResp:
+		return &BindResp{BindType: BindReceiver}
+	case CommandBindTransmitterResp:
+		return &BindResp{BindType: BindTransmitter}
+	case CommandBindTransceiverResp:
+		return &BindResp{BindType: BindTransceiver}
+	case CommandSubmitSM:
+		return &SubmitSM{}
+	case CommandSubmitSMResp:
+		return &SubmitSMResp{}
+	case CommandDeliverSM:
+		return &DeliverSM{}
+	case CommandDeliverSMResp:
+		return &DeliverSMResp{}
+	case CommandUnbind:
+		return &Unbind{}
+	case CommandUnbindResp:
+		return &UnbindResp{}
+	case CommandEnquireLink:
+		return &EnquireLink{}
+	case CommandEnquireLinkResp:
+		return &EnquireLinkResp{}
+	}
+	return nil
+}
+
+// Marshal encodes pdu, including its header
+func Marshal(pdu PDU) ([]byte, error) {
+	w := &pduWriter{data: make([]byte, headerLength)}
+	pdu.marshalBody(w)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	header := pdu.GetHeader()
+	binary.BigEndian.PutUint32(w.data[0:], uint32(len(w.data)))
+	binary.BigEndian.PutUint32(w.data[4:], uint32(pdu.GetCommandID()))
+	binary.BigEndian.PutUint32(w.data[8:], uint32(header.CommandStatus))
+	binary.BigEndian.PutUint32(w.data[12:], header.SequenceNumber)
+	return w.data, nil
+}
+
+// Unmarshal decodes a single PDU, and returns an UnknownCommandError if its
+// command_id is not supported. Responses with a non-zero command_status may
+// omit their body.
+func Unmarshal(data []byte) (PDU, error) {
+	if len(data) < headerLength || int(binary.BigEndian.Uint32(data)) != len(data) {
+		return nil, ErrInvalidPDU
+	}
+	commandID := CommandID(binary.BigEndian.Uint32(data[4:]))
+	header := Header{
+		CommandStatus:  CommandStatus(binary.BigEndian.Uint32(data[8:])),
+		SequenceNumber: binary.BigEndian.Uint32(data[12:]),
+	}
+
+	pdu := newPDU(commandID)
+	if pdu == nil {
+		return nil, &UnknownCommandError{commandID: commandID, sequenceNumber: header.SequenceNumber}
+	}
+	*pdu.GetHeader() = header
+
+	if len(data) == headerLength && commandID.IsResponse() && header.CommandStatus != StatusOK {
+		return pdu, nil
+	}
+	r := &pduReader{data: data[headerLength:]}
+	pdu.unmarshalBody(r)
+	if r.err == nil && len(r.data) > 0 {
+		r.err = ErrInvalidPDU
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return pdu, nil
+}
+
+// ReadPDU reads and decodes the next PDU from reader
+func ReadPDU(reader io.Reader) (PDU, error) {
+	lengthField := make([]byte, 4)
+	if _, err := io.ReadFull(reader, lengthField); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint32(lengthField))
+	if length < headerLength || length > MaxPDULength {
+		return nil, ErrInvalidPDU
+	}
+
+	data := make([]byte, length)
+	copy(data, lengthField)
+	if _, err := io.ReadFull(reader, data[len(lengthField):]); err != nil {
+		return nil, err
+	}
+	return Unmarshal(data)
+}
+
+// pduWriter appends PDU fields, keeping the first error
+type pduWriter struct {
+	data []byte
+	err  error
+}
+
+// writeByte appends an integer field of one octet
+func (w *pduWriter) writeByte(value byte) {
+	w.data = append(w.data, value)
+}
+
+// writeBytes appends an octet string field
+func (w *pduWriter) writeBytes(value []byte) {
+	w.data = append(w.data, value...)
+}
+
+// writeCString appends a C-octet string field, whose maximum length includes the terminating NULL
+func (w *pduWriter) writeCString(value string, maxLength int) {
+	if len(value) >= maxLength {
+		w.err = ErrFieldTooLong
+		return
+	}
+	w.data = append(append(w.data, value...), 0x00)
+}
+
+// pduReader consumes PDU fields, keeping the first error
+type pduReader struct {
+	data []byte
+	err  error
+}
+
+// readByte consumes an integer field of one octet
+func (r *pduReader) readByte() byte {
+	value := r.readBytes(1)
+	if value == nil {
+		return 0
+	}
+	return value[0]
+}
+
+// readBytes consumes an octet string field of length octets
+func (r *pduReader) readBytes(length int) []byte {
+	if r.err != nil || length > len(r.data) {
+		r.err = ErrInvalidPDU
+		return nil
+	}
+	value := append([]byte{}, r.data[:length]...)
+	r.data = r.data[length:]
+	return value
+}
+
+// readCString consumes a C-octet string field, whose maximum length includes the terminating NULL
+func (r *pduReader) readCString(maxLength int) string {
+	if r.err != nil {
+		return ""
+	}
+	for idx := 0; idx < len(r.data) && idx < maxLength; idx++ {
+		if r.data[idx] == 0x00 {
+			value := string(r.data[:idx])
+			r.data = r.data[idx+1:]
+			return value
+		}
+	}
+	r.err = ErrInvalidPDU
+	return ""
+}
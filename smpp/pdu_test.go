@@ -0,0 +1,138 @@
+package smpp
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	var TestMarshal = []struct {
+		name     string
+		pdu      PDU
+		expected []byte
+	}{
+		{
+			"enquire_link",
+			&EnquireLink{Header: Header{SequenceNumber: 1}},
+			[]byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		},
+		{
+			"generic_nack",
+			&GenericNack{Header: Header{CommandStatus: StatusInvalidCommandID, SequenceNumber: 0x01020304}},
+			[]byte{0x00, 0x00, 0x00, 0x10, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			"submit_sm_resp",
+			&SubmitSMResp{Header: Header{SequenceNumber: 2}, MessageID: "ab"},
+			[]byte{0x00, 0x00, 0x00, 0x13, 0x80, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 'a', 'b', 0x00},
+		},
+	}
+
+	for _, tt := range TestMarshal {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.pdu)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, data)
+
+			pdu, err := Unmarshal(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.pdu, pdu)
+		})
+	}
+}
+
+func TestUnmarshalReturnsErrorForMalformedPDU(t *testing.T) {
+	var TestUnmarshalReturnsErrorForMalformedPDU = []struct {
+		name string
+		data []byte
+	}{
+		{"too short", []byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x15}},
+		{"wrong length", []byte{0x00, 0x00, 0x00, 0x11, 0x00, 0x00, 0x00, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}},
+		{"trailing data", []byte{0x00, 0x00, 0x00, 0x11, 0x00, 0x00, 0x00, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00}},
+		{"unterminated string", []byte{0x00, 0x00, 0x00, 0x12, 0x80, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 'a', 'b'}},
+		{"missing body", []byte{0x00, 0x00, 0x00, 0x10, 0x80, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}},
+	}
+
+	for _, tt := range TestUnmarshalReturnsErrorForMalformedPDU {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal(tt.data)
+			assert.Equal(t, ErrInvalidPDU, err)
+		})
+	}
+}
+
+// this test ensures that failed responses may omit their body
+func TestUnmarshalFailedResponseWithoutBody(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 0x10, 0x80, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x58, 0x00, 0x00, 0x00, 0x07}
+
+	pdu, err := Unmarshal(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &SubmitSMResp{Header: Header{CommandStatus: StatusThrottled, SequenceNumber: 7}}, pdu)
+}
+
+func TestUnmarshalReturnsUnknownCommandError(t *testing.T) {
+	// a query_sm, which is not supported
+	data := []byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09}
+
+	_, err := Unmarshal(data)
+
+	unknownCommandError, ok := err.(*UnknownCommandError)
+	assert.True(t, ok)
+	assert.Equal(t, CommandID(0x00000003), unknownCommandError.GetCommandID())
+	assert.Equal(t, uint32(9), unknownCommandError.GetSequenceNumber())
+	assert.Equal(t, "the command ID 0x00000003 is not supported", err.Error())
+}
+
+func TestReadPDU(t *testing.T) {
+	var stream []byte
+	for idx := uint32(1); idx <= 3; idx++ {
+		data, err := Marshal(&EnquireLinkResp{Header: Header{SequenceNumber: idx}})
+		assert.NoError(t, err)
+		stream = append(stream, data...)
+	}
+	reader := bytes.NewReader(stream)
+
+	for idx := uint32(1); idx <= 3; idx++ {
+		pdu, err := ReadPDU(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, CommandEnquireLinkResp, pdu.GetCommandID())
+		assert.Equal(t, idx, pdu.GetHeader().SequenceNumber)
+	}
+	_, err := ReadPDU(reader)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadPDUReturnsError(t *testing.T) {
+	var TestReadPDUReturnsError = []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"length too short", []byte{0x00, 0x00, 0x00, 0x0F}, ErrInvalidPDU},
+		{"length too long", []byte{0x00, 0x01, 0x00, 0x01}, ErrInvalidPDU},
+		{"truncated", []byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00}, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range TestReadPDUReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadPDU(bytes.NewReader(tt.data))
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestCommandStatusString(t *testing.T) {
+	assert.Equal(t, "ESME_ROK", StatusOK.String())
+	assert.Equal(t, "ESME_RTHROTTLED", StatusThrottled.String())
+	assert.Equal(t, "0x00000400", CommandStatus(0x400).String())
+}
+
+func TestCommandIDIsResponse(t *testing.T) {
+	assert.False(t, CommandSubmitSM.IsResponse())
+	assert.True(t, CommandSubmitSMResp.IsResponse())
+	assert.True(t, CommandGenericNack.IsResponse())
+}
@@ -0,0 +1,188 @@
+package smpp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/textnow/gosms"
+)
+
+// ErrNotReceipt indicates that a deliver_sm is not a delivery receipt
+var ErrNotReceipt = errors.New("the deliver_sm is not a delivery receipt")
+
+const (
+	receiptIDField    string = "id:"
+	receiptStatField  string = "stat:"
+	receiptErrorField string = "err:"
+	receiptTextField  string = "text:"
+)
+
+// receipt stats, as given in the text of a delivery receipt
+const (
+	// StatEnroute means that the message is still being delivered
+	StatEnroute string = "ENROUTE"
+
+	// StatDelivered means that the message was delivered
+	StatDelivered string = "DELIVRD"
+
+	// StatExpired means that the validity period of the message expired
+	StatExpired string = "EXPIRED"
+
+	// StatDeleted means that the message was deleted
+	StatDeleted string = "DELETED"
+
+	// StatUndeliverable means that the message cannot be delivered
+	StatUndeliverable string = "UNDELIV"
+
+	// StatAccepted means that the message was accepted on behalf of the recipient
+	StatAccepted string = "ACCEPTD"
+
+	// StatUnknown means that the state of the message is unknown
+	StatUnknown string = "UNKNOWN"
+
+	// StatRejected means that the message was rejected
+	StatRejected string = "REJECTD"
+)
+
+// messageStates are the stats of each message_state TLV value
+var messageStates = map[uint8]string{
+	1: StatEnroute,
+	2: StatDelivered,
+	3: StatExpired,
+	4: StatDeleted,
+	5: StatUndeliverable,
+	6: StatAccepted,
+	7: StatUnknown,
+	8: StatRejected,
+}
+
+// StatOf returns the receipt stat of a message_state TLV value, and false if the value is not a known state
+func StatOf(messageState uint8) (string, bool) {
+	stat, ok := messageStates[messageState]
+	return stat, ok
+}
+
+// MessageStateOf returns the message_state TLV value of a receipt stat, and false if the stat is not a known state
+func MessageStateOf(stat string) (uint8, bool) {
+	for messageState, messageStat := range messageStates {
+		if messageStat == stat {
+			return messageState, true
+		}
+	}
+	return 0, false
+}
+
+// Receipt is an SMSC delivery receipt for a submitted message
+type Receipt struct {
+	messageID string
+	stat      string
+	errorCode string
+	text      string
+}
+
+// GetMessageID returns the ID the SMSC gave the message in its submit_sm_resp
+func (r *Receipt) GetMessageID() string {
+	return r.messageID
+}
+
+// GetStat returns the final status of the message, such as StatDelivered
+func (r *Receipt) GetStat() string {
+	return r.stat
+}
+
+// GetState returns the delivery state described by the stat of the receipt
+func (r *Receipt) GetState() gosms.DeliveryState {
+	switch r.stat {
+	case StatDelivered:
+		return gosms.DeliveryStateDelivered
+	case StatExpired, StatDeleted, StatUndeliverable, StatRejected:
+		return gosms.DeliveryStateFailed
+	}
+	return gosms.DeliveryStatePending
+}
+
+// GetErrorCode returns the network specific error code of the receipt
+func (r *Receipt) GetErrorCode() string {
+	return r.errorCode
+}
+
+// GetText returns the start of the message text, if the SMSC included it
+func (r *Receipt) GetText() string {
+	return r.text
+}
+
+// IsReceipt returns true if the DeliverSM is a delivery receipt rather than a message
+func (p *DeliverSM) IsReceipt() bool {
+	return p.ESMClass&ESMClassDeliveryReceipt != 0
+}
+
+// GetReceipt decodes the delivery receipt carried by the DeliverSM. The
+// receipted_message_id and message_state TLVs take precedence over the text of
+// the receipt.
+func (p *DeliverSM) GetReceipt() (Receipt, error) {
+	if !p.IsReceipt() {
+		return Receipt{}, ErrNotReceipt
+	}
+
+	text := string(p.ShortMessage)
+	receipt := Receipt{
+		messageID: getReceiptField(text, receiptIDField),
+		stat:      getReceiptField(text, receiptStatField),
+		errorCode: getReceiptField(text, receiptErrorField),
+	}
+	// the text runs to the end of the receipt
+	if idx := indexReceiptField(text, receiptTextField); idx >= 0 {
+		receipt.text = text[idx+len(receiptTextField):]
+	}
+
+	if tlv, ok := p.TLVs.Get(TagReceiptedMessageID); ok {
+		if messageID, ok := tlv.GetCString(); ok {
+			receipt.messageID = messageID
+		}
+	}
+	if tlv, ok := p.TLVs.Get(TagMessageState); ok {
+		if messageState, ok := tlv.GetUint8(); ok {
+			if stat, ok := StatOf(messageState); ok {
+				receipt.stat = stat
+			}
+		}
+	}
+	return receipt, nil
+}
+
+// getReceiptField returns the value of a field in the text of a receipt, which runs to the next space
+func getReceiptField(text string, field string) string {
+	idx := indexReceiptField(text, field)
+	if idx < 0 {
+		return ""
+	}
+	value := text[idx+len(field):]
+	if end := strings.IndexByte(value, ' '); end >= 0 {
+		value = value[:end]
+	}
+	return value
+}
+
+// indexReceiptField returns the index of a field at the start of the text of a
+// receipt or after a space, ignoring the case of ASCII letters
+func indexReceiptField(text string, field string) int {
+	lower := []byte(text)
+	for idx, char := range lower {
+		if char >= 'A' && char <= 'Z' {
+			lower[idx] = char + 'a' - 'A'
+		}
+	}
+	lowerText := string(lower)
+	for offset := 0; offset < len(lowerText); {
+		idx := strings.Index(lowerText[offset:], field)
+		if idx < 0 {
+			return -1
+		}
+		idx += offset
+		if idx == 0 || lowerText[idx-1] == ' ' {
+			return idx
+		}
+		offset = idx + len(field)
+	}
+	return -1
+}
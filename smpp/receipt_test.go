@@ -0,0 +1,109 @@
+package smpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+)
+
+func TestDeliverSMGetReceipt(t *testing.T) {
+	var TestDeliverSMGetReceipt = []struct {
+		name              string
+		shortMessage      string
+		tlvs              TLVs
+		expectedMessageID string
+		expectedStat      string
+		expectedState     gosms.DeliveryState
+		expectedError     string
+		expectedText      string
+	}{
+		{
+			"delivered",
+			"id:1A2B3C sub:001 dlvrd:001 submit date:2101021504 done date:2101021505 stat:DELIVRD err:000 text:Hello world",
+			nil, "1A2B3C", StatDelivered, gosms.DeliveryStateDelivered, "000", "Hello world",
+		},
+		{
+			"undeliverable with upper case fields",
+			"ID:42 SUB:001 DLVRD:000 SUBMIT DATE:2101021504 DONE DATE:2101021505 STAT:UNDELIV ERR:034 TEXT:",
+			nil, "42", StatUndeliverable, gosms.DeliveryStateFailed, "034", "",
+		},
+		{
+			"enroute without text",
+			"id:7 stat:ENROUTE",
+			nil, "7", StatEnroute, gosms.DeliveryStatePending, "", "",
+		},
+		{
+			"TLVs take precedence",
+			"id:wrong stat:ENROUTE",
+			TLVs{NewCStringTLV(TagReceiptedMessageID, "right"), NewUint8TLV(TagMessageState, 8)},
+			"right", StatRejected, gosms.DeliveryStateFailed, "", "",
+		},
+		{
+			"unknown message state is ignored",
+			"id:9 stat:EXPIRED",
+			TLVs{NewUint8TLV(TagMessageState, 0)},
+			"9", StatExpired, gosms.DeliveryStateFailed, "", "",
+		},
+		{
+			"fields are matched at word boundaries",
+			"msgid:1 id:2 stat:ACCEPTD",
+			nil, "2", StatAccepted, gosms.DeliveryStatePending, "", "",
+		},
+	}
+
+	for _, tt := range TestDeliverSMGetReceipt {
+		t.Run(tt.name, func(t *testing.T) {
+			deliver := &DeliverSM{Message: Message{ESMClass: ESMClassDeliveryReceipt, ShortMessage: []byte(tt.shortMessage), TLVs: tt.tlvs}}
+
+			receipt, err := deliver.GetReceipt()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessageID, receipt.GetMessageID())
+			assert.Equal(t, tt.expectedStat, receipt.GetStat())
+			assert.Equal(t, tt.expectedState, receipt.GetState())
+			assert.Equal(t, tt.expectedError, receipt.GetErrorCode())
+			assert.Equal(t, tt.expectedText, receipt.GetText())
+		})
+	}
+}
+
+func TestDeliverSMGetReceiptReturnsErrorForMessages(t *testing.T) {
+	deliver := &DeliverSM{Message: Message{ShortMessage: []byte("id:1 stat:DELIVRD")}}
+
+	assert.False(t, deliver.IsReceipt())
+	_, err := deliver.GetReceipt()
+	assert.Equal(t, ErrNotReceipt, err)
+}
+
+func TestMessageStates(t *testing.T) {
+	var TestMessageStates = []struct {
+		stat         string
+		messageState uint8
+	}{
+		{StatEnroute, 1},
+		{StatDelivered, 2},
+		{StatExpired, 3},
+		{StatDeleted, 4},
+		{StatUndeliverable, 5},
+		{StatAccepted, 6},
+		{StatUnknown, 7},
+		{StatRejected, 8},
+	}
+
+	for _, tt := range TestMessageStates {
+		t.Run(tt.stat, func(t *testing.T) {
+			messageState, ok := MessageStateOf(tt.stat)
+			assert.True(t, ok)
+			assert.Equal(t, tt.messageState, messageState)
+
+			stat, ok := StatOf(tt.messageState)
+			assert.True(t, ok)
+			assert.Equal(t, tt.stat, stat)
+		})
+	}
+
+	_, ok := MessageStateOf("SCHEDLD")
+	assert.False(t, ok)
+	_, ok = StatOf(0)
+	assert.False(t, ok)
+}
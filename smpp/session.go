@@ -0,0 +1,81 @@
+package smpp
+
+// GenericNack rejects a PDU which could not be decoded
+type GenericNack struct {
+	Header
+}
+
+// GetCommandID returns CommandGenericNack
+func (p *GenericNack) GetCommandID() CommandID {
+	return CommandGenericNack
+}
+
+// marshalBody encodes the empty body of the GenericNack
+func (p *GenericNack) marshalBody(w *pduWriter) {}
+
+// unmarshalBody decodes the empty body of the GenericNack
+func (p *GenericNack) unmarshalBody(r *pduReader) {}
+
+// EnquireLink checks that the other end of a session is alive
+type EnquireLink struct {
+	Header
+}
+
+// GetCommandID returns CommandEnquireLink
+func (p *EnquireLink) GetCommandID() CommandID {
+	return CommandEnquireLink
+}
+
+// marshalBody encodes the empty body of the EnquireLink
+func (p *EnquireLink) marshalBody(w *pduWriter) {}
+
+// unmarshalBody decodes the empty body of the EnquireLink
+func (p *EnquireLink) unmarshalBody(r *pduReader) {}
+
+// EnquireLinkResp is the response to an EnquireLink
+type EnquireLinkResp struct {
+	Header
+}
+
+// GetCommandID returns CommandEnquireLinkResp
+func (p *EnquireLinkResp) GetCommandID() CommandID {
+	return CommandEnquireLinkResp
+}
+
+// marshalBody encodes the empty body of the EnquireLinkResp
+func (p *EnquireLinkResp) marshalBody(w *pduWriter) {}
+
+// unmarshalBody decodes the empty body of the EnquireLinkResp
+func (p *EnquireLinkResp) unmarshalBody(r *pduReader) {}
+
+// Unbind closes a session
+type Unbind struct {
+	Header
+}
+
+// GetCommandID returns CommandUnbind
+func (p *Unbind) GetCommandID() CommandID {
+	return CommandUnbind
+}
+
+// marshalBody encodes the empty body of the Unbind
+func (p *Unbind) marshalBody(w *pduWriter) {}
+
+// unmarshalBody decodes the empty body of the Unbind
+func (p *Unbind) unmarshalBody(r *pduReader) {}
+
+// UnbindResp is the response to an Unbind
+type UnbindResp struct {
+	Header
+}
+
+// GetCommandID returns CommandUnbindResp
+func (p *UnbindResp) GetCommandID() CommandID {
+	return CommandUnbindResp
+}
+
+// marshalBody encodes the empty body of the UnbindResp
+func (p *UnbindResp) marshalBody(w *pduWriter) {}
+
+// unmarshalBody decodes the empty body of the UnbindResp
+func (p *UnbindResp) unmarshalBody(r *pduReader) {}
@@ -0,0 +1,36 @@
+package smpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionPDUs(t *testing.T) {
+	var TestSessionPDUs = []struct {
+		name              string
+		pdu               PDU
+		expectedCommandID CommandID
+	}{
+		{"generic_nack", &GenericNack{}, CommandGenericNack},
+		{"enquire_link", &EnquireLink{}, CommandEnquireLink},
+		{"enquire_link_resp", &EnquireLinkResp{}, CommandEnquireLinkResp},
+		{"unbind", &Unbind{}, CommandUnbind},
+		{"unbind_resp", &UnbindResp{}, CommandUnbindResp},
+	}
+
+	for _, tt := range TestSessionPDUs {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pdu.GetHeader().SequenceNumber = 42
+			assert.Equal(t, tt.expectedCommandID, tt.pdu.GetCommandID())
+
+			data, err := Marshal(tt.pdu)
+			assert.NoError(t, err)
+			assert.Equal(t, headerLength, len(data))
+
+			pdu, err := Unmarshal(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.pdu, pdu)
+		})
+	}
+}
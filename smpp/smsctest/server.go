@@ -0,0 +1,523 @@
+// Package smsctest provides an in-process SMSC for testing SMPP clients
+// without a real carrier
+package smsctest
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/textnow/gosms"
+	"github.com/textnow/gosms/smpp"
+)
+
+const (
+	// ServerSystemID is the system_id the Server gives in its bind responses
+	ServerSystemID string = "smsctest"
+
+	receiptDateFormat string = "0601021504"
+	receiptTextLength int    = 20
+)
+
+// Submission is a submit_sm accepted by the Server
+type Submission struct {
+	messageID string
+	systemID  string
+	submit    *smpp.SubmitSM
+}
+
+// GetMessageID returns the message ID the Server gave in its submit_sm_resp
+func (s *Submission) GetMessageID() string {
+	return s.messageID
+}
+
+// GetSystemID returns the system_id of the session which submitted the message
+func (s *Submission) GetSystemID() string {
+	return s.systemID
+}
+
+// GetSubmitSM returns the submit_sm as it was received
+func (s *Submission) GetSubmitSM() *smpp.SubmitSM {
+	return s.submit
+}
+
+// Message is a message reassembled from the parts submitted to the Server
+type Message struct {
+	from    string
+	to      string
+	content string
+}
+
+// GetFrom returns the originator of the message
+func (m *Message) GetFrom() string {
+	return m.from
+}
+
+// GetTo returns the recipient of the message
+func (m *Message) GetTo() string {
+	return m.to
+}
+
+// GetContent returns the content of every part of the message joined together
+func (m *Message) GetContent() string {
+	return m.content
+}
+
+// fault is a scripted failure applied to a submit_sm instead of accepting it
+type fault struct {
+	commandStatus smpp.CommandStatus
+	ignore        bool
+	disconnect    bool
+}
+
+// Server is an SMSC listening on loopback. It accepts binds, records every
+// submit_sm, reassembles concatenated messages and sends delivery receipts,
+// and can be scripted to reject, throttle, ignore or drop submissions.
+type Server struct {
+	listener     net.Listener
+	mutex        sync.Mutex
+	systemID     string
+	password     string
+	receiptDelay time.Duration
+	receiptStat  string
+	faults       []fault
+	sessions     map[*session]bool
+	bindCount    int
+	submissions  []Submission
+	messages     []Message
+	reassembler  *gosms.Reassembler
+	deliveries   []*smpp.DeliverSM
+	messageID    int
+	sequence     uint32
+	closed       chan struct{}
+	waitGroup    sync.WaitGroup
+}
+
+// NewServer starts a Server on a free loopback port which accepts any
+// credentials and reports every message as delivered
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener:    listener,
+		receiptStat: smpp.StatDelivered,
+		sessions:    make(map[*session]bool),
+		reassembler: gosms.NewReassembler(),
+		closed:      make(chan struct{}),
+	}
+	s.waitGroup.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// GetAddress returns the address the Server is listening on, for passing to smpp.NewClient
+func (s *Server) GetAddress() string {
+	return s.listener.Addr().String()
+}
+
+// SetCredentials sets the system_id and password a bind must give. Any
+// credentials are accepted if systemID is empty.
+func (s *Server) SetCredentials(systemID string, password string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.systemID = systemID
+	s.password = password
+}
+
+// SetReceiptDelay sets how long after a submission its delivery receipt is sent
+func (s *Server) SetReceiptDelay(receiptDelay time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.receiptDelay = receiptDelay
+}
+
+// SetReceiptStat sets the stat reported by delivery receipts, such as smpp.StatUndeliverable
+func (s *Server) SetReceiptStat(receiptStat string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.receiptStat = receiptStat
+}
+
+// Reject rejects the next count submissions with commandStatus
+func (s *Server) Reject(commandStatus smpp.CommandStatus, count int) {
+	s.addFaults(fault{commandStatus: commandStatus}, count)
+}
+
+// Throttle rejects the next count submissions with smpp.StatusThrottled
+func (s *Server) Throttle(count int) {
+	s.Reject(smpp.StatusThrottled, count)
+}
+
+// Ignore drops the next count submissions without responding to them
+func (s *Server) Ignore(count int) {
+	s.addFaults(fault{ignore: true}, count)
+}
+
+// DropOnSubmit closes the connection instead of responding to the next count submissions
+func (s *Server) DropOnSubmit(count int) {
+	s.addFaults(fault{disconnect: true}, count)
+}
+
+// addFaults queues count copies of a fault behind any already scripted
+func (s *Server) addFaults(f fault, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for idx := 0; idx < count; idx++ {
+		s.faults = append(s.faults, f)
+	}
+}
+
+// Disconnect closes every open session without unbinding
+func (s *Server) Disconnect() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for sess := range s.sessions {
+		sess.conn.Close()
+	}
+}
+
+// GetBindCount returns how many binds the Server has accepted
+func (s *Server) GetBindCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.bindCount
+}
+
+// GetSubmissions returns every submission the Server has accepted, in the order they arrived
+func (s *Server) GetSubmissions() []Submission {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Submission(nil), s.submissions...)
+}
+
+// GetMessages returns the messages whose parts have all been submitted, in the order they were completed
+func (s *Server) GetMessages() []Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Deliver sends an SMS generated by a gosms Splitter to a bound receiver as a
+// deliver_sm, interpreting national numbers using defaultRegion. It is held
+// until a receiver binds if none is bound.
+func (s *Server) Deliver(sms gosms.SMS, defaultRegion string) error {
+	submit, err := smpp.NewSubmitSM(sms, defaultRegion)
+	if err != nil {
+		return err
+	}
+	s.deliver(&smpp.DeliverSM{Message: submit.Message})
+	return nil
+}
+
+// Close closes every session without unbinding and stops listening
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	select {
+	case <-s.closed:
+		s.mutex.Unlock()
+		return nil
+	default:
+	}
+	close(s.closed)
+	for sess := range s.sessions {
+		sess.conn.Close()
+	}
+	s.mutex.Unlock()
+
+	err := s.listener.Close()
+	s.waitGroup.Wait()
+	return err
+}
+
+// accept starts a session for each connection until the Server is closed
+func (s *Server) accept() {
+	defer s.waitGroup.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		sess := &session{conn: conn}
+		s.mutex.Lock()
+		select {
+		case <-s.closed:
+			s.mutex.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		s.sessions[sess] = true
+		s.waitGroup.Add(1)
+		s.mutex.Unlock()
+		go s.serve(sess)
+	}
+}
+
+// serve handles the PDUs of a session until it is closed
+func (s *Server) serve(sess *session) {
+	defer s.waitGroup.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.sessions, sess)
+		s.mutex.Unlock()
+		sess.conn.Close()
+	}()
+
+	for {
+		pdu, err := smpp.ReadPDU(sess.conn)
+		if unknownCommandError, ok := err.(*smpp.UnknownCommandError); ok {
+			nack := &smpp.GenericNack{}
+			nack.CommandStatus = smpp.StatusInvalidCommandID
+			nack.SequenceNumber = unknownCommandError.GetSequenceNumber()
+			sess.write(nack)
+			continue
+		}
+		if err != nil {
+			return
+		}
+		if !s.handle(sess, pdu) {
+			return
+		}
+	}
+}
+
+// handle responds to a PDU, returning false if the session should be closed
+func (s *Server) handle(sess *session, pdu smpp.PDU) bool {
+	var response smpp.PDU
+	switch request := pdu.(type) {
+	case *smpp.Bind:
+		response = s.bind(sess, request)
+	case *smpp.SubmitSM:
+		var ok bool
+		if response, ok = s.submit(sess, request); !ok {
+			return false
+		}
+	case *smpp.EnquireLink:
+		response = &smpp.EnquireLinkResp{}
+	case *smpp.Unbind:
+		response = &smpp.UnbindResp{}
+		respond(sess, pdu, response)
+		return false
+	case *smpp.DeliverSMResp, *smpp.GenericNack:
+		return true
+	default:
+		if pdu.GetCommandID().IsResponse() {
+			return true
+		}
+		response = &smpp.GenericNack{}
+		response.GetHeader().CommandStatus = smpp.StatusInvalidCommandID
+	}
+	if response != nil {
+		respond(sess, pdu, response)
+	}
+	return true
+}
+
+// bind checks the credentials of a Bind and marks the session as bound
+func (s *Server) bind(sess *session, bind *smpp.Bind) smpp.PDU {
+	response := &smpp.BindResp{BindType: bind.BindType, SystemID: ServerSystemID}
+
+	s.mutex.Lock()
+	switch {
+	case sess.bound:
+		response.CommandStatus = smpp.StatusAlreadyBound
+	case s.systemID != "" && bind.SystemID != s.systemID:
+		response.CommandStatus = smpp.StatusInvalidSystemID
+	case s.systemID != "" && bind.Password != s.password:
+		response.CommandStatus = smpp.StatusInvalidPassword
+	default:
+		sess.bound = true
+		sess.bindType = bind.BindType
+		sess.systemID = bind.SystemID
+		s.bindCount++
+	}
+	s.mutex.Unlock()
+
+	if response.CommandStatus != smpp.StatusOK {
+		response.SystemID = ""
+		return response
+	}
+
+	// the response goes out before any held deliveries
+	respond(sess, bind, response)
+	s.flush()
+	return nil
+}
+
+// submit records a SubmitSM or applies the next scripted fault to it,
+// returning false if the session should be closed
+func (s *Server) submit(sess *session, submit *smpp.SubmitSM) (smpp.PDU, bool) {
+	response := &smpp.SubmitSMResp{}
+
+	s.mutex.Lock()
+	if !sess.bound || sess.bindType == smpp.BindReceiver {
+		s.mutex.Unlock()
+		response.CommandStatus = smpp.StatusInvalidBindStatus
+		return response, true
+	}
+	if len(s.faults) > 0 {
+		f := s.faults[0]
+		s.faults = s.faults[1:]
+		s.mutex.Unlock()
+		if f.ignore {
+			return nil, true
+		}
+		if f.disconnect {
+			return nil, false
+		}
+		response.CommandStatus = f.commandStatus
+		return response, true
+	}
+	s.mutex.Unlock()
+
+	sms, err := submit.GetSMS()
+	if err != nil {
+		response.CommandStatus = smpp.StatusSubmitFailed
+		return response, true
+	}
+	content, complete, err := s.reassembler.Add(sms)
+	if err != nil {
+		response.CommandStatus = smpp.StatusSubmitFailed
+		return response, true
+	}
+
+	s.mutex.Lock()
+	s.messageID++
+	response.MessageID = fmt.Sprintf("%08X", s.messageID)
+	s.submissions = append(s.submissions, Submission{messageID: response.MessageID, systemID: sess.systemID, submit: submit})
+	if complete {
+		s.messages = append(s.messages, Message{from: sms.GetFrom(), to: sms.GetTo(), content: content})
+	}
+	receiptDelay, receiptStat := s.receiptDelay, s.receiptStat
+	s.mutex.Unlock()
+
+	if wantsReceipt(submit.RegisteredDelivery, receiptStat) {
+		receipt := newReceipt(submit, sms, response.MessageID, receiptStat, time.Now(), receiptDelay)
+		time.AfterFunc(receiptDelay, func() { s.deliver(receipt) })
+	}
+	return response, true
+}
+
+// wantsReceipt returns true if registeredDelivery asks for a receipt with stat
+func wantsReceipt(registeredDelivery byte, stat string) bool {
+	if registeredDelivery&smpp.RegisteredDeliveryReceipt != 0 {
+		return true
+	}
+	failed := stat != smpp.StatDelivered && stat != smpp.StatAccepted && stat != smpp.StatEnroute
+	return registeredDelivery&smpp.RegisteredDeliveryFailureReceipt != 0 && failed
+}
+
+// newReceipt creates the delivery receipt for a submission
+func newReceipt(submit *smpp.SubmitSM, sms gosms.SMS, messageID string, stat string, submitted time.Time, delay time.Duration) *smpp.DeliverSM {
+	delivered := "000"
+	if stat == smpp.StatDelivered {
+		delivered = "001"
+	}
+	text := []rune(sms.GetContent())
+	if len(text) > receiptTextLength {
+		text = text[:receiptTextLength]
+	}
+	shortMessage := fmt.Sprintf("id:%s sub:001 dlvrd:%s submit date:%s done date:%s stat:%s err:000 text:%s",
+		messageID, delivered, submitted.Format(receiptDateFormat), submitted.Add(delay).Format(receiptDateFormat), stat, string(text))
+
+	tlvs := smpp.TLVs{smpp.NewCStringTLV(smpp.TagReceiptedMessageID, messageID)}
+	if messageState, ok := smpp.MessageStateOf(stat); ok {
+		tlvs = append(tlvs, smpp.NewUint8TLV(smpp.TagMessageState, messageState))
+	}
+	return &smpp.DeliverSM{Message: smpp.Message{
+		SourceAddrTON:   submit.DestAddrTON,
+		SourceAddrNPI:   submit.DestAddrNPI,
+		SourceAddr:      submit.DestinationAddr,
+		DestAddrTON:     submit.SourceAddrTON,
+		DestAddrNPI:     submit.SourceAddrNPI,
+		DestinationAddr: submit.SourceAddr,
+		ESMClass:        smpp.ESMClassDeliveryReceipt,
+		DataCoding:      smpp.DataCodingDefault,
+		ShortMessage:    []byte(shortMessage),
+		TLVs:            tlvs,
+	}}
+}
+
+// deliver sends a DeliverSM to a bound receiver, or holds it until one binds
+func (s *Server) deliver(deliver *smpp.DeliverSM) {
+	s.mutex.Lock()
+	select {
+	case <-s.closed:
+		s.mutex.Unlock()
+		return
+	default:
+	}
+	s.deliveries = append(s.deliveries, deliver)
+	s.mutex.Unlock()
+	s.flush()
+}
+
+// flush sends held deliveries to a bound receiver, holding any it could not
+// send until the next receiver binds
+func (s *Server) flush() {
+	s.mutex.Lock()
+	var receiver *session
+	for sess := range s.sessions {
+		if sess.bound && sess.bindType != smpp.BindTransmitter {
+			receiver = sess
+			break
+		}
+	}
+	if receiver == nil {
+		s.mutex.Unlock()
+		return
+	}
+	deliveries := s.deliveries
+	s.deliveries = nil
+	for _, deliver := range deliveries {
+		s.sequence++
+		deliver.SequenceNumber = s.sequence
+	}
+	s.mutex.Unlock()
+
+	for idx, deliver := range deliveries {
+		if err := receiver.write(deliver); err != nil {
+			s.mutex.Lock()
+			// the unsent deliveries go ahead of any held since
+			s.deliveries = append(append([]*smpp.DeliverSM(nil), deliveries[idx:]...), s.deliveries...)
+			s.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// respond sends response with the sequence number of request
+func respond(sess *session, request smpp.PDU, response smpp.PDU) {
+	response.GetHeader().SequenceNumber = request.GetHeader().SequenceNumber
+	sess.write(response)
+}
+
+// session is a connection to the Server
+type session struct {
+	conn       net.Conn
+	writeMutex sync.Mutex
+	bound      bool
+	bindType   smpp.BindType
+	systemID   string
+}
+
+// write sends a PDU, closing the connection if it fails
+func (sess *session) write(pdu smpp.PDU) error {
+	data, err := smpp.Marshal(pdu)
+	if err != nil {
+		return err
+	}
+
+	sess.writeMutex.Lock()
+	defer sess.writeMutex.Unlock()
+	if _, err := sess.conn.Write(data); err != nil {
+		sess.conn.Close()
+		return err
+	}
+	return nil
+}
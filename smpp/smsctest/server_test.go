@@ -0,0 +1,264 @@
+package smsctest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+	"github.com/textnow/gosms/smpp"
+)
+
+const longMessage = "This message is long enough that it has to be split into several parts by the splitter."
+
+// newTestClient connects a Client with short timeouts to server
+func newTestClient(t *testing.T, server *Server) *smpp.Client {
+	client := smpp.NewClient(server.GetAddress(), "system", "secret")
+	client.SetResponseTimeout(200 * time.Millisecond)
+	client.SetReconnectDelay(10*time.Millisecond, 40*time.Millisecond)
+	client.SetThrottleDelay(20 * time.Millisecond)
+	client.SetEnquireLinkInterval(0)
+	assert.NoError(t, client.Connect())
+	return client
+}
+
+// split splits message into parts of at most 40 bytes
+func split(t *testing.T, message string) []gosms.SMS {
+	splitter := gosms.NewSplitter()
+	splitter.SetMessageBytes(40)
+	smsParts, err := splitter.Split("+14155550100", []string{"+14155550123"}, message)
+	assert.NoError(t, err)
+	return smsParts
+}
+
+// waitFor waits up to a second for condition to hold
+func waitFor(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestServerRecordsAndReassemblesSubmissions(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	client := newTestClient(t, server)
+	defer client.Close()
+
+	smsParts := split(t, longMessage)
+	messageIDs, err := client.SubmitAll(smsParts)
+	assert.NoError(t, err)
+
+	submissions := server.GetSubmissions()
+	assert.Equal(t, len(smsParts), len(submissions))
+	for _, submission := range submissions {
+		assert.Contains(t, messageIDs, submission.GetMessageID())
+		assert.Equal(t, "system", submission.GetSystemID())
+		assert.Equal(t, "14155550123", submission.GetSubmitSM().DestinationAddr)
+	}
+
+	messages := server.GetMessages()
+	assert.Equal(t, 1, len(messages))
+	assert.Equal(t, "+14155550100", messages[0].GetFrom())
+	assert.Equal(t, "+14155550123", messages[0].GetTo())
+	assert.Equal(t, longMessage, messages[0].GetContent())
+	assert.Equal(t, 1, server.GetBindCount())
+}
+
+func TestServerChecksCredentials(t *testing.T) {
+	var TestServerChecksCredentials = []struct {
+		systemID string
+		password string
+		expected smpp.CommandStatus
+	}{
+		{"other", "secret", smpp.StatusInvalidSystemID},
+		{"system", "wrong", smpp.StatusInvalidPassword},
+	}
+
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	server.SetCredentials("system", "secret")
+
+	for _, tt := range TestServerChecksCredentials {
+		client := smpp.NewClient(server.GetAddress(), tt.systemID, tt.password)
+		err := client.Connect()
+		commandStatusError, ok := err.(*smpp.CommandStatusError)
+		assert.True(t, ok)
+		if ok {
+			assert.Equal(t, tt.expected, commandStatusError.GetCommandStatus())
+		}
+		client.Close()
+	}
+	assert.Equal(t, 0, server.GetBindCount())
+}
+
+func TestServerSendsReceipts(t *testing.T) {
+	var TestServerSendsReceipts = []struct {
+		stat               string
+		registeredDelivery byte
+		expected           bool
+	}{
+		{smpp.StatDelivered, smpp.RegisteredDeliveryReceipt, true},
+		{smpp.StatUndeliverable, smpp.RegisteredDeliveryReceipt, true},
+		{smpp.StatUndeliverable, smpp.RegisteredDeliveryFailureReceipt, true},
+		{smpp.StatDelivered, smpp.RegisteredDeliveryFailureReceipt, false},
+		{smpp.StatDelivered, 0, false},
+	}
+
+	for _, tt := range TestServerSendsReceipts {
+		t.Run(tt.stat, func(t *testing.T) {
+			server, err := NewServer()
+			assert.NoError(t, err)
+			defer server.Close()
+			server.SetReceiptDelay(30 * time.Millisecond)
+			server.SetReceiptStat(tt.stat)
+			client := newTestClient(t, server)
+			client.SetRegisteredDelivery(tt.registeredDelivery)
+			defer client.Close()
+
+			start := time.Now()
+			messageID, err := client.Submit(split(t, "Hello world, this is a message")[0])
+			assert.NoError(t, err)
+
+			select {
+			case deliver := <-client.Deliveries():
+				assert.True(t, tt.expected)
+				assert.True(t, time.Since(start) >= 30*time.Millisecond)
+				assert.Equal(t, "14155550100", deliver.DestinationAddr)
+				receipt, err := deliver.GetReceipt()
+				assert.NoError(t, err)
+				assert.Equal(t, messageID, receipt.GetMessageID())
+				assert.Equal(t, tt.stat, receipt.GetStat())
+				assert.Equal(t, "Hello world, this is", receipt.GetText())
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServerHoldsDeliveriesUntilReceiverBinds(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	smsParts := split(t, longMessage)
+	for _, sms := range smsParts {
+		assert.NoError(t, server.Deliver(sms, ""))
+	}
+
+	client := newTestClient(t, server)
+	defer client.Close()
+	reassembler := gosms.NewReassembler()
+	for range smsParts {
+		select {
+		case deliver := <-client.Deliveries():
+			assert.False(t, deliver.IsReceipt())
+			sms, err := deliver.GetSMS()
+			assert.NoError(t, err)
+			content, complete, err := reassembler.Add(sms)
+			assert.NoError(t, err)
+			if complete {
+				assert.Equal(t, longMessage, content)
+			}
+		case <-time.After(time.Second):
+			assert.Fail(t, "no delivery")
+		}
+	}
+	assert.Equal(t, 0, reassembler.GetPending())
+}
+
+func TestServerHoldsDeliveriesItFailsToSend(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	// a receiver whose connection is gone
+	conn, peer := net.Pipe()
+	peer.Close()
+	receiver := &session{conn: conn, bound: true, bindType: smpp.BindReceiver}
+	server.mutex.Lock()
+	server.sessions[receiver] = true
+	server.mutex.Unlock()
+
+	smsParts := split(t, longMessage)
+	for _, sms := range smsParts {
+		assert.NoError(t, server.Deliver(sms, ""))
+	}
+	server.mutex.Lock()
+	assert.Equal(t, len(smsParts), len(server.deliveries))
+	delete(server.sessions, receiver)
+	server.mutex.Unlock()
+
+	client := newTestClient(t, server)
+	defer client.Close()
+	for range smsParts {
+		select {
+		case deliver := <-client.Deliveries():
+			assert.False(t, deliver.IsReceipt())
+		case <-time.After(time.Second):
+			assert.Fail(t, "no delivery")
+		}
+	}
+}
+
+func TestServerInjectsFaults(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	client := newTestClient(t, server)
+	defer client.Close()
+	sms := split(t, "hello")[0]
+
+	server.Reject(smpp.StatusInvalidDestinationAddress, 1)
+	_, err = client.Submit(sms)
+	assert.Equal(t, "the request failed with ESME_RINVDSTADR", err.Error())
+
+	server.Throttle(2)
+	_, err = client.Submit(sms)
+	assert.NoError(t, err)
+
+	server.Ignore(1)
+	_, err = client.Submit(sms)
+	assert.Equal(t, smpp.ErrResponseTimeout, err)
+
+	server.DropOnSubmit(1)
+	_, err = client.Submit(sms)
+	assert.Equal(t, smpp.ErrConnectionLost, err)
+	assert.True(t, waitFor(func() bool { return server.GetBindCount() == 2 }))
+
+	// only the submission after the throttling was accepted
+	assert.Equal(t, 1, len(server.GetSubmissions()))
+}
+
+func TestServerDisconnect(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	client := newTestClient(t, server)
+	defer client.Close()
+
+	server.Disconnect()
+	assert.True(t, waitFor(func() bool { return server.GetBindCount() == 2 }))
+
+	_, err = client.Submit(split(t, "hello")[0])
+	assert.NoError(t, err)
+}
+
+func TestServerRejectsSubmissionsFromReceivers(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	client := smpp.NewClient(server.GetAddress(), "system", "secret")
+	client.SetBindType(smpp.BindReceiver)
+	assert.NoError(t, client.Connect())
+	defer client.Close()
+
+	_, err = client.Submit(split(t, "hello")[0])
+	assert.Equal(t, "the request failed with ESME_RINVBNDSTS", err.Error())
+}
@@ -0,0 +1,146 @@
+package smpp
+
+import "encoding/binary"
+
+const (
+	tlvHeaderLength int = 4
+	maxTLVLength    int = 0xFFFF
+)
+
+// Tag identifies an optional parameter
+type Tag uint16
+
+const (
+	// TagDestinationPort is the destination_port TLV
+	TagDestinationPort Tag = 0x020B
+
+	// TagSourcePort is the source_port TLV
+	TagSourcePort Tag = 0x020A
+
+	// TagSARMessageReference is the sar_msg_ref_num TLV
+	TagSARMessageReference Tag = 0x020C
+
+	// TagSARTotalSegments is the sar_total_segments TLV
+	TagSARTotalSegments Tag = 0x020E
+
+	// TagSARSegmentSequence is the sar_segment_seqnum TLV
+	TagSARSegmentSequence Tag = 0x020F
+
+	// TagSCInterfaceVersion is the sc_interface_version TLV
+	TagSCInterfaceVersion Tag = 0x0210
+
+	// TagUserMessageReference is the user_message_reference TLV
+	TagUserMessageReference Tag = 0x0204
+
+	// TagReceiptedMessageID is the receipted_message_id TLV
+	TagReceiptedMessageID Tag = 0x001E
+
+	// TagMessagePayload is the message_payload TLV
+	TagMessagePayload Tag = 0x0424
+
+	// TagMessageState is the message_state TLV
+	TagMessageState Tag = 0x0427
+)
+
+// TLV is an optional parameter of a PDU
+type TLV struct {
+	Tag   Tag
+	Value []byte
+}
+
+// NewUint8TLV returns a TLV holding a one octet integer
+func NewUint8TLV(tag Tag, value uint8) TLV {
+	return TLV{Tag: tag, Value: []byte{value}}
+}
+
+// NewUint16TLV returns a TLV holding a two octet integer
+func NewUint16TLV(tag Tag, value uint16) TLV {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, value)
+	return TLV{Tag: tag, Value: data}
+}
+
+// NewCStringTLV returns a TLV holding a C-octet string
+func NewCStringTLV(tag Tag, value string) TLV {
+	return TLV{Tag: tag, Value: append([]byte(value), 0x00)}
+}
+
+// GetUint8 returns the value of the TLV as a one octet integer, and false if it has a different length
+func (t *TLV) GetUint8() (uint8, bool) {
+	if len(t.Value) != 1 {
+		return 0, false
+	}
+	return t.Value[0], true
+}
+
+// GetUint16 returns the value of the TLV as a two octet integer, and false if it has a different length
+func (t *TLV) GetUint16() (uint16, bool) {
+	if len(t.Value) != 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(t.Value), true
+}
+
+// GetCString returns the value of the TLV as a C-octet string, and false if it is not NULL terminated
+func (t *TLV) GetCString() (string, bool) {
+	if len(t.Value) == 0 || t.Value[len(t.Value)-1] != 0x00 {
+		return "", false
+	}
+	return string(t.Value[:len(t.Value)-1]), true
+}
+
+// TLVs are the optional parameters of a PDU, in the order they are encoded
+type TLVs []TLV
+
+// Get returns the first TLV with tag, and false if there is none
+func (t TLVs) Get(tag Tag) (TLV, bool) {
+	for _, tlv := range t {
+		if tlv.Tag == tag {
+			return tlv, true
+		}
+	}
+	return TLV{}, false
+}
+
+// Set replaces the TLVs with the tag of tlv, or appends tlv if there are none
+func (t TLVs) Set(tlv TLV) TLVs {
+	var tlvs TLVs
+	for _, existing := range t {
+		if existing.Tag != tlv.Tag {
+			tlvs = append(tlvs, existing)
+		}
+	}
+	return append(tlvs, tlv)
+}
+
+// writeTLVs appends the optional parameters of a PDU
+func (w *pduWriter) writeTLVs(tlvs TLVs) {
+	for _, tlv := range tlvs {
+		if len(tlv.Value) > maxTLVLength {
+			w.err = ErrFieldTooLong
+			return
+		}
+		header := make([]byte, tlvHeaderLength)
+		binary.BigEndian.PutUint16(header, uint16(tlv.Tag))
+		binary.BigEndian.PutUint16(header[2:], uint16(len(tlv.Value)))
+		w.writeBytes(header)
+		w.writeBytes(tlv.Value)
+	}
+}
+
+// readTLVs consumes the optional parameters at the end of a PDU
+func (r *pduReader) readTLVs() TLVs {
+	var tlvs TLVs
+	for r.err == nil && len(r.data) > 0 {
+		header := r.readBytes(tlvHeaderLength)
+		if header == nil {
+			return nil
+		}
+		value := r.readBytes(int(binary.BigEndian.Uint16(header[2:])))
+		if value == nil {
+			return nil
+		}
+		tlvs = append(tlvs, TLV{Tag: Tag(binary.BigEndian.Uint16(header)), Value: value})
+	}
+	return tlvs
+}
@@ -0,0 +1,80 @@
+package smpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLVValues(t *testing.T) {
+	uint8TLV := NewUint8TLV(TagSARTotalSegments, 3)
+	value8, ok := uint8TLV.GetUint8()
+	assert.True(t, ok)
+	assert.Equal(t, uint8(3), value8)
+	_, ok = uint8TLV.GetUint16()
+	assert.False(t, ok)
+
+	uint16TLV := NewUint16TLV(TagSARMessageReference, 0xABCD)
+	assert.Equal(t, []byte{0xAB, 0xCD}, uint16TLV.Value)
+	value16, ok := uint16TLV.GetUint16()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0xABCD), value16)
+	_, ok = uint16TLV.GetUint8()
+	assert.False(t, ok)
+
+	stringTLV := NewCStringTLV(TagReceiptedMessageID, "abc")
+	assert.Equal(t, []byte{'a', 'b', 'c', 0x00}, stringTLV.Value)
+	value, ok := stringTLV.GetCString()
+	assert.True(t, ok)
+	assert.Equal(t, "abc", value)
+	_, ok = uint16TLV.GetCString()
+	assert.False(t, ok)
+}
+
+func TestTLVsGetAndSet(t *testing.T) {
+	var tlvs TLVs
+	tlvs = tlvs.Set(NewUint8TLV(TagSARTotalSegments, 2))
+	tlvs = tlvs.Set(NewUint8TLV(TagSARSegmentSequence, 1))
+	tlvs = tlvs.Set(NewUint8TLV(TagSARTotalSegments, 3))
+
+	assert.Equal(t, 2, len(tlvs))
+	tlv, ok := tlvs.Get(TagSARTotalSegments)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{3}, tlv.Value)
+	_, ok = tlvs.Get(TagMessagePayload)
+	assert.False(t, ok)
+}
+
+func TestTLVsRoundTrip(t *testing.T) {
+	tlvs := TLVs{
+		NewUint16TLV(TagSARMessageReference, 0x0102),
+		{Tag: TagMessagePayload, Value: []byte{}},
+		NewCStringTLV(TagReceiptedMessageID, "id"),
+	}
+	w := &pduWriter{}
+	w.writeTLVs(tlvs)
+	assert.NoError(t, w.err)
+	assert.Equal(t, []byte{
+		0x02, 0x0C, 0x00, 0x02, 0x01, 0x02,
+		0x04, 0x24, 0x00, 0x00,
+		0x00, 0x1E, 0x00, 0x03, 'i', 'd', 0x00,
+	}, w.data)
+
+	r := &pduReader{data: w.data}
+	assert.Equal(t, tlvs, r.readTLVs())
+	assert.NoError(t, r.err)
+}
+
+func TestReadTLVsReturnsErrorForTruncatedTLV(t *testing.T) {
+	for _, data := range [][]byte{{0x02, 0x0C, 0x00}, {0x02, 0x0C, 0x00, 0x02, 0x01}} {
+		r := &pduReader{data: data}
+		r.readTLVs()
+		assert.Equal(t, ErrInvalidPDU, r.err)
+	}
+}
+
+func TestWriteTLVsReturnsErrorForLongValue(t *testing.T) {
+	w := &pduWriter{}
+	w.writeTLVs(TLVs{{Tag: TagMessagePayload, Value: make([]byte, maxTLVLength+1)}})
+	assert.Equal(t, ErrFieldTooLong, w.err)
+}
@@ -2,10 +2,15 @@ package gosms
 
 // SMS structure with correctly sized message and appropriate UDH
 type SMS struct {
-	from    string
-	to      string
-	content string
-	udh     string
+	from          string
+	to            string
+	content       string
+	udh           string
+	data          []byte
+	encoder       Encoder
+	messageClass  MessageClass
+	concatenation Concatenation
+	segment       Concatenated16Bit
 }
 
 // newSMS initializes a new SMS
@@ -37,3 +42,99 @@ func (s *SMS) GetContent() string {
 func (s *SMS) GetUDH() string {
 	return s.udh
 }
+
+// GetBytes returns the SMS's content encoded with the encoder used to split it.
+// GSM content is preceded by the fill bits needed to follow the UDH.
+func (s *SMS) GetBytes() []byte {
+	return s.data
+}
+
+// GetUserData returns the SMS's UDH followed by its encoded content
+func (s *SMS) GetUserData() []byte {
+	return append([]byte(s.udh), s.data...)
+}
+
+// GetUserDataLength returns the length of the SMS's user data as given by
+// TP-UDL, in septets including those taken up by the UDH for GSM, and in
+// octets otherwise
+func (s *SMS) GetUserDataLength() int {
+	gsm, isGSM := s.encoder.(*GSM)
+	if !isGSM {
+		return len(s.udh) + len(s.data)
+	}
+	septets, _ := gsm.EncodeSeptets(s.content)
+	return UDHSeptets(len(s.udh)) + len(septets)
+}
+
+// GetEncoder returns the encoder used to split and encode the SMS
+func (s *SMS) GetEncoder() Encoder {
+	return s.encoder
+}
+
+// GetMessageClass returns the SMS's message class
+func (s *SMS) GetMessageClass() MessageClass {
+	return s.messageClass
+}
+
+// GetConcatenation returns how the SMS's message was numbered when it was split
+func (s *SMS) GetConcatenation() Concatenation {
+	return s.concatenation
+}
+
+// GetSegment returns the reference number, total and sequence of an SMS part
+// numbered alongside its user data, and false if it was not numbered that way
+func (s *SMS) GetSegment() (Concatenated16Bit, bool) {
+	return s.segment, s.segment.Total > 0
+}
+
+// SetSegment numbers a parsed SMS part whose PDU identifies it alongside its
+// user data, such as with the SMPP sar_* TLVs, so that it can be reassembled
+func (s *SMS) SetSegment(segment Concatenated16Bit) {
+	s.segment = segment
+}
+
+// GetDataCoding returns the DCS describing the SMS's encoder and message class
+func (s *SMS) GetDataCoding() (DCS, error) {
+	characterSet, err := getCharacterSet(s.encoder)
+	if err != nil {
+		return DCS{}, err
+	}
+	return NewDCS(characterSet, s.messageClass), nil
+}
+
+// ParseSMS parses an SMS from received user data. hasUDH indicates that the
+// user data starts with a UDH, as signalled by the TP-UDHI bit. length is the
+// user data length given by TP-UDL or the SMPP sm_length, in septets including
+// those taken up by the UDH for GSM, and in octets otherwise.
+func ParseSMS(from string, to string, userData []byte, hasUDH bool, length int, encoder Encoder) (SMS, error) {
+	gsm, isGSM := encoder.(*GSM)
+	if !isGSM {
+		if length > len(userData) {
+			return SMS{}, ErrInvalidTPDU
+		}
+		userData = userData[:length]
+	}
+	if hasUDH {
+		if len(userData) == 0 || int(userData[0]) >= len(userData) {
+			return SMS{}, ErrInvalidUDH
+		}
+	}
+	if isGSM {
+		return unmarshalGSMUserData(from, to, hasUDH, length, userData, gsm)
+	}
+
+	var udh []byte
+	if hasUDH {
+		udh = userData[:int(userData[0])+1]
+	}
+	data := userData[len(udh):]
+	content, err := encoder.Decode(data)
+	if err != nil {
+		return SMS{}, err
+	}
+
+	sms := newSMS(from, to, content, string(udh))
+	sms.data = append([]byte(nil), data...)
+	sms.encoder = encoder
+	return sms, nil
+}
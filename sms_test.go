@@ -1,6 +1,7 @@
 package gosms
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,3 +21,89 @@ func TestSMS(t *testing.T) {
 	assert.Equal(t, content, sms.GetContent())
 	assert.Equal(t, udh, sms.GetUDH())
 }
+
+// this test ensures that SMS parts produced by Split are parsed back from their user data
+func TestParseSMSRoundTrip(t *testing.T) {
+	turkish, _ := NewNationalGSM(NationalLanguageTurkish, NationalLanguageTurkish)
+
+	var TestParseSMSRoundTrip = []struct {
+		name    string
+		encoder Encoder
+		message string
+	}{
+		{"GSM", NewGSM(), "This message is long enough to be split into several parts, with [extended] characters too."},
+		{"GSM national language", turkish, "Şişli'de buluşalım mı? Saat üçte geliyorum, gecikirsem ararım."},
+		{"UTF-16", NewUTF16(), "This message has emoji 😀 and is long enough to be split into parts."},
+		{"Latin-1", NewLatin1(), "Ce message est assez long pour être découpé en plusieurs parties."},
+	}
+
+	for _, tt := range TestParseSMSRoundTrip {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			splitter.SetMessageBytes(40)
+
+			smsParts, err := splitter.Split("from", []string{"to"}, tt.message)
+			assert.NoError(t, err)
+			assert.True(t, len(smsParts) > 1)
+
+			for _, sms := range smsParts {
+				parsed, err := ParseSMS("from", "to", sms.GetUserData(), true, sms.GetUserDataLength(), tt.encoder)
+				assert.NoError(t, err)
+				assert.Equal(t, sms.GetContent(), parsed.GetContent())
+				assert.Equal(t, sms.GetUDH(), parsed.GetUDH())
+				assert.Equal(t, sms.GetBytes(), parsed.GetBytes())
+			}
+		})
+	}
+}
+
+// this test ensures that user data without a UDH is decoded in full
+func TestParseSMSWithoutUDH(t *testing.T) {
+	encoder := NewGSM()
+	data, _ := encoder.Encode("hello")
+
+	sms, err := ParseSMS("from", "to", data, false, 5, encoder)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", sms.GetContent())
+	assert.Equal(t, "", sms.GetUDH())
+	assert.Equal(t, encoder, sms.GetEncoder())
+}
+
+// this test ensures that a full GSM part ending in '@' keeps it, since the
+// septet count tells it apart from padding
+func TestParseSMSKeepsTrailingAt(t *testing.T) {
+	splitter := NewSplitter()
+	smsParts, err := splitter.Split("from", []string{"to"}, strings.Repeat("a", 152)+"@"+strings.Repeat("b", 10))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(smsParts))
+	assert.Equal(t, 160, smsParts[0].GetUserDataLength())
+
+	parsed, err := ParseSMS("from", "to", smsParts[0].GetUserData(), true, smsParts[0].GetUserDataLength(), NewGSM())
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 152)+"@", parsed.GetContent())
+}
+
+// this test ensures that user data shorter than its length is rejected
+func TestParseSMSReturnsErrorForShortUserData(t *testing.T) {
+	_, err := ParseSMS("from", "to", []byte{0x00, 0x41}, false, 3, NewUTF16())
+	assert.Equal(t, ErrInvalidTPDU, err)
+
+	_, err = ParseSMS("from", "to", []byte{0xE8, 0x32}, false, 5, NewGSM())
+	assert.Equal(t, ErrInvalidTPDU, err)
+}
+
+// this test ensures that a UDH longer than the user data is rejected
+func TestParseSMSReturnsErrorForTruncatedUDH(t *testing.T) {
+	var TestParseSMSReturnsErrorForTruncatedUDH = [][]byte{
+		{},
+		{0x05, 0x00, 0x03, 0x01},
+	}
+
+	for _, userData := range TestParseSMSReturnsErrorForTruncatedUDH {
+		_, err := ParseSMS("from", "to", userData, true, len(userData), NewGSM())
+		assert.Equal(t, ErrInvalidUDH, err)
+	}
+}
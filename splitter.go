@@ -1,39 +1,92 @@
 package gosms
 
-import (
-	"bytes"
-	"crypto/md5"
-	"encoding/binary"
-	"io"
-	"strconv"
-	"strings"
-	"time"
-)
+import "strings"
 
 const (
 	// DefaultSMSBytes is the default SMS size in bytes
 	DefaultSMSBytes             int = 140
-	shortReferenceInfoElementID int = 0x00
-	longReferenceInfoElementID  int = 0x08
+	shortReferenceInfoElementID int = int(IEIConcatenated8Bit)
+	longReferenceInfoElementID  int = int(IEIConcatenated16Bit)
 	udhByteLengthShort          int = 6
 	udhByteLengthLong           int = 7
 	byteLength                  int = 8
 )
 
+// Concatenation is how the parts of a split message are numbered
+type Concatenation int
+
+const (
+	// ConcatenationUDH numbers each part with a concatenation element in its UDH
+	ConcatenationUDH Concatenation = iota
+
+	// ConcatenationSAR numbers each part alongside its user data, as the SMPP
+	// sar_* TLVs do, so the whole user data is available for content
+	ConcatenationSAR
+
+	// ConcatenationPayload sends the message whole, as in the SMPP
+	// message_payload TLV, leaving the SMSC to split it
+	ConcatenationPayload
+)
+
 // Splitter splits messages into SMS structs
 type Splitter struct {
-	encoder        Encoder
-	messageBytes   int
-	shortReference bool
+	encoder            Encoder
+	encoders           []Encoder
+	autoSelect         bool
+	transliterator     *Transliterator
+	messageBytes       int
+	shortReference     bool
+	referenceAllocator ReferenceAllocator
+	maxSegments        int
+	truncate           bool
+	truncationSuffix   string
+	perRecipient       bool
+	validateAddresses  bool
+	defaultRegion      string
+	messageClass       MessageClass
+	concatenation      Concatenation
 }
 
 // NewSplitter creates a new Splitter configured with default values
 func NewSplitter() *Splitter {
 	return &Splitter{
-		encoder: nil,
-		messageBytes: DefaultSMSBytes,
-		shortReference: true,
+		encoder:            nil,
+		encoders:           DefaultEncoders(),
+		autoSelect:         false,
+		transliterator:     nil,
+		messageBytes:       DefaultSMSBytes,
+		shortReference:     true,
+		referenceAllocator: NewHashReferenceAllocator(),
+		maxSegments:        DefaultMaxSegments,
+		truncate:           false,
+		truncationSuffix:   DefaultTruncationSuffix,
+		perRecipient:       false,
+		validateAddresses:  false,
+		defaultRegion:      "",
+		messageClass:       MessageClassNone,
+		concatenation:      ConcatenationUDH,
+	}
+}
+
+// DefaultEncoders returns the encoders considered by auto-selection, in order
+// of preference: GSM, GSM with each national language table, UTF-16 and Latin-1.
+// Latin-1 comes last so that it is only chosen when it needs fewer parts.
+func DefaultEncoders() []Encoder {
+	encoders := []Encoder{NewGSM()}
+	for _, tables := range [][2]NationalLanguage{
+		{NationalLanguageDefault, NationalLanguageSpanish},
+		{NationalLanguageDefault, NationalLanguagePortuguese},
+		{NationalLanguageDefault, NationalLanguageTurkish},
+		{NationalLanguagePortuguese, NationalLanguagePortuguese},
+		{NationalLanguageTurkish, NationalLanguageTurkish},
+		{NationalLanguageBengali, NationalLanguageBengali},
+		{NationalLanguageHindi, NationalLanguageHindi},
+		{NationalLanguageTamil, NationalLanguageTamil},
+	} {
+		encoder, _ := NewNationalGSM(tables[0], tables[1])
+		encoders = append(encoders, encoder)
 	}
+	return append(encoders, NewUTF16(), NewLatin1())
 }
 
 // SetEncoder sets the encoder of the Splitter
@@ -41,6 +94,30 @@ func (s *Splitter) SetEncoder(encoder Encoder) {
 	s.encoder = encoder
 }
 
+// SetEncoders sets the encoders considered by auto-selection, in order of preference
+func (s *Splitter) SetEncoders(encoders ...Encoder) {
+	s.encoders = encoders
+}
+
+// RegisterEncoder adds an encoder to those considered by auto-selection, with the lowest preference
+func (s *Splitter) RegisterEncoder(encoder Encoder) {
+	s.encoders = append(s.encoders, encoder)
+}
+
+// SetAutoSelect sets the autoSelect of the Splitter. When no encoder is set and
+// autoSelect is true, the registered encoder producing the fewest SMS parts is
+// used, with ties going to the encoder registered first.
+func (s *Splitter) SetAutoSelect(autoSelect bool) {
+	s.autoSelect = autoSelect
+}
+
+// SetTransliterator sets the transliterator of the Splitter. When set, messages
+// which cannot be encoded with GSM are transliterated if that makes them
+// encodable with GSM. A nil transliterator disables transliteration.
+func (s *Splitter) SetTransliterator(transliterator *Transliterator) {
+	s.transliterator = transliterator
+}
+
 // SetMessageBytes sets the messageBytes of the Splitter
 func (s *Splitter) SetMessageBytes(messageBytes int) {
 	s.messageBytes = messageBytes
@@ -51,127 +128,486 @@ func (s *Splitter) SetShortReference(shortReference bool) {
 	s.shortReference = shortReference
 }
 
+// SetReferenceAllocator sets the ReferenceAllocator used to identify the parts of concatenated messages
+func (s *Splitter) SetReferenceAllocator(referenceAllocator ReferenceAllocator) {
+	s.referenceAllocator = referenceAllocator
+}
+
+// SetMaxSegments sets the maximum number of segments a message may be split into, up to 255
+func (s *Splitter) SetMaxSegments(maxSegments int) {
+	s.maxSegments = maxSegments
+}
+
+// SetTruncate sets whether messages needing too many segments are truncated instead of rejected
+func (s *Splitter) SetTruncate(truncate bool) {
+	s.truncate = truncate
+}
+
+// SetTruncationSuffix sets the suffix appended to truncated messages
+func (s *Splitter) SetTruncationSuffix(truncationSuffix string) {
+	s.truncationSuffix = truncationSuffix
+}
+
+// SetPerRecipient sets whether Split generates a separate set of SMSs for each recipient
+func (s *Splitter) SetPerRecipient(perRecipient bool) {
+	s.perRecipient = perRecipient
+}
+
+// SetValidateAddresses sets whether Split validates the sender and recipients and normalises them to E.164
+func (s *Splitter) SetValidateAddresses(validateAddresses bool) {
+	s.validateAddresses = validateAddresses
+}
+
+// SetDefaultRegion sets the ISO 3166-1 alpha-2 region used to interpret national numbers when validating addresses
+func (s *Splitter) SetDefaultRegion(defaultRegion string) {
+	s.defaultRegion = defaultRegion
+}
+
+// SetMessageClass sets the message class of the generated SMSs, such as MessageClass0 for flash SMS
+func (s *Splitter) SetMessageClass(messageClass MessageClass) {
+	s.messageClass = messageClass
+}
+
+// SetConcatenation sets how the parts of split messages are numbered, such as
+// ConcatenationSAR for an SMSC expecting the SMPP sar_* TLVs
+func (s *Splitter) SetConcatenation(concatenation Concatenation) {
+	s.concatenation = concatenation
+}
+
 // CheckEncodability returns true if the message is encodable with the splitter's encoder and false otherwise
 func (s *Splitter) CheckEncodability(message string) bool {
 	return s.encoder.CheckEncodability(message)
 }
 
-// Split generates SMSs with sizable message parts and appropriate UDHs
+// Split generates SMSs with sizable message parts and appropriate UDHs.
+// Messages needing more than the maximum number of segments are rejected with
+// a TooManySegmentsError, or truncated if truncation is enabled.
+// In per-recipient mode the parts for each recipient follow one another.
 func (s *Splitter) Split(from string, to []string, message string) ([]SMS, error) {
+	var err error
+
+	if s.perRecipient {
+		batch, err := s.SplitPerRecipient(from, to, message)
+		if err != nil {
+			return nil, err
+		}
+		return batch.GetSMSs(), nil
+	}
+
+	if s.validateAddresses {
+		if from, to, err = s.normaliseAddresses(from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	prepared, err := s.prepareMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	// append receivers
+	receivers := strings.Join(to, " ")
+
+	return s.createSMSs(from, receivers, prepared.parts, prepared.encoder)
+}
+
+// SplitPerRecipient generates a separate set of SMSs for each recipient, each
+// with its own reference number. Duplicate recipients are only sent to once.
+func (s *Splitter) SplitPerRecipient(from string, to []string, message string) (Batch, error) {
+	var err error
+	batch := Batch{parts: make(map[string][]SMS, len(to))}
+
+	if s.validateAddresses {
+		if from, to, err = s.normaliseAddresses(from, to); err != nil {
+			return Batch{}, err
+		}
+	}
+
+	prepared, err := s.prepareMessage(message)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	for _, recipient := range to {
+		if _, ok := batch.parts[recipient]; ok {
+			continue
+		}
+		smsParts, err := s.createSMSs(from, recipient, prepared.parts, prepared.encoder)
+		if err != nil {
+			return Batch{}, err
+		}
+		batch.recipients = append(batch.recipients, recipient)
+		batch.parts[recipient] = smsParts
+	}
+	return batch, nil
+}
+
+// preparedMessage is a message split into parts, ready to be encoded
+type preparedMessage struct {
+	message       string
+	parts         []string
+	partLength    int
+	encoder       Encoder
+	substitutions []Substitution
+}
+
+// prepareMessage transliterates message, selects its encoder and splits it into
+// parts, rejecting or truncating messages needing too many segments
+func (s *Splitter) prepareMessage(message string) (preparedMessage, error) {
+	// keep the message in the GSM alphabet where possible
+	message, substitutions := s.transliterate(message)
+
+	// use the specified encoder, or select or auto-detect one
+	encoder, err := s.selectEncoder(message)
+	if err != nil {
+		return preparedMessage{}, err
+	}
+
+	// split message
+	messageParts, partLength, err := s.splitMessage(message, encoder)
+	if err != nil {
+		return preparedMessage{}, err
+	}
+
+	// reject or truncate messages needing too many segments
+	if len(messageParts) > s.getMaxSegments() {
+		if !s.truncate {
+			return preparedMessage{}, &TooManySegmentsError{segments: len(messageParts), maxSegments: s.getMaxSegments()}
+		}
+		if message, err = s.truncateMessage(message); err != nil {
+			return preparedMessage{}, err
+		}
+		if encoder, err = s.selectEncoder(message); err != nil {
+			return preparedMessage{}, err
+		}
+		if messageParts, partLength, err = s.splitMessage(message, encoder); err != nil {
+			return preparedMessage{}, err
+		}
+	}
+	return preparedMessage{
+		message:       message,
+		parts:         messageParts,
+		partLength:    partLength,
+		encoder:       encoder,
+		substitutions: substitutions,
+	}, nil
+}
+
+// createSMSs creates encoded SMS parts with UDHs for the message parts sent to to
+func (s *Splitter) createSMSs(from string, to string, messageParts []string, encoder Encoder) ([]SMS, error) {
 	var smsParts []SMS
-	var messageLength int
-	var messageParts []string
-	var receivers string
-	var udhByteLength int
-	var encoder Encoder
 
-	// use the specified encoder or auto-detect
-	if s.encoder != nil {
-		encoder = s.encoder
+	// the SMSC splits a message sent whole
+	if s.concatenation == ConcatenationPayload {
+		messageParts = []string{strings.Join(messageParts, "")}
+	}
+
+	// create SMS parts and number them
+	for _, messagePart := range messageParts {
+		sms := newSMS(from, to, messagePart, "")
+		sms.messageClass = s.messageClass
+		sms.concatenation = s.concatenation
+		smsParts = append(smsParts, sms)
+	}
+	reference, err := s.allocateReference(smsParts)
+	if err != nil {
+		return nil, err
+	}
+	if s.concatenation == ConcatenationSAR {
+		smsParts = appendSegments(smsParts, reference)
 	} else {
-		encoder = autoDetectEncoder(message)
+		smsParts = appendUDHs(smsParts, reference, s.shortReference)
+	}
+	smsParts = appendInfoElements(smsParts, nationalLanguageIEs(encoder))
+	return encodeSMSs(smsParts, encoder)
+}
+
+// allocateReference returns the reference number for the parts of a concatenated
+// message, falling back to a hash of the message if no allocator is set
+func (s *Splitter) allocateReference(smsParts []SMS) (uint16, error) {
+	if len(smsParts) <= 1 {
+		return 0, nil
 	}
 
+	referenceAllocator := s.referenceAllocator
+	if referenceAllocator == nil {
+		referenceAllocator = NewHashReferenceAllocator()
+	}
+	return referenceAllocator.Allocate(smsParts[0].from, smsParts[0].to, smsParts[0].content, s.shortReference)
+}
+
+// transliterate replaces the characters of message which keep it from being
+// encoded with GSM, if the transliterated message can be encoded with GSM
+func (s *Splitter) transliterate(message string) (string, []Substitution) {
+	if s.transliterator == nil {
+		return message, nil
+	}
+
+	gsmEncoders := s.getGSMEncoders()
+	for _, encoder := range gsmEncoders {
+		if encoder.CheckEncodability(message) {
+			return message, nil
+		}
+	}
+
+	// only replace the characters each GSM encoder cannot encode, and keep the
+	// transliteration which replaces the fewest characters
+	var selectedSubstitutions []Substitution
+	var selected = message
+	var selectedCount = -1
+	for _, encoder := range gsmEncoders {
+		transliterated, substitutions := s.transliterator.transliterate(message, encoder)
+		if !encoder.CheckEncodability(transliterated) {
+			continue
+		}
+
+		count := 0
+		for _, substitution := range substitutions {
+			count += substitution.count
+		}
+		if selectedCount == -1 || count < selectedCount {
+			selected = transliterated
+			selectedSubstitutions = substitutions
+			selectedCount = count
+		}
+	}
+	return selected, selectedSubstitutions
+}
+
+// getGSMEncoders returns the GSM encoders the splitter may use, in order of preference
+func (s *Splitter) getGSMEncoders() []Encoder {
+	var gsmEncoders []Encoder
+
+	if s.encoder != nil {
+		if _, isGSM := s.encoder.(*GSM); isGSM {
+			gsmEncoders = append(gsmEncoders, s.encoder)
+		}
+		return gsmEncoders
+	}
+	if !s.autoSelect {
+		return []Encoder{NewGSM()}
+	}
+
+	// auto-selection considers every registered GSM encoder
+	for _, encoder := range s.encoders {
+		if _, isGSM := encoder.(*GSM); isGSM {
+			gsmEncoders = append(gsmEncoders, encoder)
+		}
+	}
+	return gsmEncoders
+}
+
+// selectEncoder returns the encoder used to split message
+func (s *Splitter) selectEncoder(message string) (Encoder, error) {
+	var selected Encoder
+	var selectedParts int
+
+	if s.encoder != nil {
+		return s.encoder, nil
+	}
+	if !s.autoSelect {
+		return autoDetectEncoder(message), nil
+	}
+
+	// choose the encoder with the fewest parts, preferring earlier encoders
+	for _, encoder := range s.encoders {
+		if !encoder.CheckEncodability(message) {
+			continue
+		}
+		messageParts, _, err := s.splitMessage(message, encoder)
+		if err != nil {
+			continue
+		}
+		if selected == nil || len(messageParts) < selectedParts {
+			selected = encoder
+			selectedParts = len(messageParts)
+		}
+	}
+
+	if selected == nil {
+		return nil, ErrNotEncodable
+	}
+	return selected, nil
+}
+
+// splitMessage splits message into parts which fit in an SMS alongside their UDH,
+// and returns the length of each part in code points
+func (s *Splitter) splitMessage(message string, encoder Encoder) ([]string, int, error) {
+	var udhByteLength int
+	var messageLength int
+
 	// set of symbols which compose the message
 	runeSet := []rune(message)
 
-	// append receivers
-	receivers = strings.Join(to, " ")
+	// national language tables are identified in the UDH of every part
+	languageIEsByteLength := infoElementsByteLength(nationalLanguageIEs(encoder))
+	if languageIEsByteLength > 0 {
+		udhByteLength = languageIEsByteLength + 1
+	}
 
 	// short circuit for messages that don't need to be split
-	singleSMS, err := willMessageFit(runeSet, encoder, (s.messageBytes*byteLength)/encoder.GetCodePointBits())
+	messageLength = messageCapacity(encoder, s.messageBytes, udhByteLength)
+	singleSMS, err := willMessageFit(runeSet, encoder, messageLength)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if singleSMS {
-		return []SMS{newSMS(from, receivers, message, "")}, nil
+		return []string{message}, messageLength, nil
 	}
 
-	// determine the UDH length
-	udhByteLength = udhByteLengthLong
-	if s.shortReference {
-		udhByteLength = udhByteLengthShort
-	}
-
-	// adjust message length for UDH
-	messageLength = ((s.messageBytes - udhByteLength) * byteLength) / encoder.GetCodePointBits()
+	// parts numbered alongside their user data fit as much as a single SMS
+	if s.concatenation != ConcatenationSAR {
+		// determine the UDH length
+		udhByteLength = udhByteLengthLong + languageIEsByteLength
+		if s.shortReference {
+			udhByteLength = udhByteLengthShort + languageIEsByteLength
+		}
 
-	// split message
-	messageParts, err = SplitMessage(runeSet, encoder, messageLength)
+		// adjust message length for UDH
+		messageLength = messageCapacity(encoder, s.messageBytes, udhByteLength)
+	}
+	messageParts, err := SplitMessage(runeSet, encoder, messageLength)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	return messageParts, messageLength, nil
+}
 
-	// create SMS parts and append UDHs
-	for _, messagePart := range messageParts {
-		smsParts = append(smsParts, newSMS(from, receivers, messagePart, ""))
+// messageCapacity returns the number of code points that fit in messageBytes
+// after a UDH of udhByteLength bytes. GSM septets must start on a septet
+// boundary, so the fill bits which follow the UDH are also reserved.
+func messageCapacity(encoder Encoder, messageBytes int, udhByteLength int) int {
+	if _, isGSM := encoder.(*GSM); isGSM {
+		return (messageBytes*byteLength - udhByteLength*byteLength - FillBits(udhByteLength)) / septetBits
+	}
+	return ((messageBytes - udhByteLength) * byteLength) / encoder.GetCodePointBits()
+}
+
+// encodeSMSs encodes the content of SMS parts with encoder. GSM content is
+// padded with fill bits so that it can directly follow the UDH.
+func encodeSMSs(smsParts []SMS, encoder Encoder) ([]SMS, error) {
+	for idx := range smsParts {
+		var data []byte
+		var err error
+
+		if gsm, isGSM := encoder.(*GSM); isGSM {
+			var septets []byte
+			septets, err = gsm.EncodeSeptets(smsParts[idx].content)
+			data = PackSeptets(septets, FillBits(len(smsParts[idx].udh)))
+		} else {
+			data, err = encoder.Encode(smsParts[idx].content)
+		}
+		if err != nil {
+			return nil, err
+		}
+		smsParts[idx].data = data
+		smsParts[idx].encoder = encoder
 	}
-	return appendUDHs(smsParts, s.shortReference), nil
+	return smsParts, nil
 }
 
-// appendUDHs generates UDHs for SMS parts
+// appendUDHs generates UDHs for SMS parts identified by reference
 // if messages cannot be uniquely identified, try increasing the
 // size of the reference number by setting shortReference to false
-func appendUDHs(smsParts []SMS, shortReference bool) []SMS {
-	const timeBase = 10
-	var infoElementID int
-	var headerLength int
-	var infoElementDataLength int
-	var udhByteLength int
-
+func appendUDHs(smsParts []SMS, reference uint16, shortReference bool) []SMS {
 	// short circuit for too few SMS parts
 	if len(smsParts) <= 1 {
 		return smsParts
 	}
 
-	// account for the length of the UDH reference number
-	if shortReference {
-		infoElementID = shortReferenceInfoElementID
-		udhByteLength = udhByteLengthShort
-	} else {
-		infoElementID = longReferenceInfoElementID
-		udhByteLength = udhByteLengthLong
-	}
-
-	// messageHash is not intended to be cryptographically strong,
-	// but merely unique enough to identify a message.
-	md5Hash := md5.New()
-	microtime := time.Now().UnixNano() / int64(time.Millisecond)
-	io.WriteString(md5Hash, // create an MD5 hash of
-		smsParts[0].from+ // the sender
-			smsParts[0].to+ // the receivers
-			smsParts[0].content+ // the first message part
-			strconv.FormatInt(microtime, timeBase), // the time
-	) // to ensure uniqueness
-	messageHash := md5Hash.Sum(nil)
-
-	// set header and element length for UDH
-	headerLength = udhByteLength - 1          // the header takes the remaining bytes of the UDH
-	infoElementDataLength = udhByteLength - 3 // the header data takes the remaining bytes of the UDH
-
 	// append UDH to messages, create SMS parts
 	for idx := range smsParts {
-		udh := new(bytes.Buffer)
-		byteOrder := binary.LittleEndian
+		var concatenation InformationElement
 
-		// construct UDH
-		binary.Write(udh, byteOrder, int8(headerLength))
-		binary.Write(udh, byteOrder, int8(infoElementID))
-		binary.Write(udh, byteOrder, int8(infoElementDataLength))
-		binary.Write(udh, byteOrder, int8(messageHash[0]))
-		if !shortReference {
-			binary.Write(udh, byteOrder, int8(messageHash[1]))
+		// account for the length of the UDH reference number
+		if shortReference {
+			concatenation = Concatenated8Bit{
+				Reference: byte(reference),
+				Total:     byte(len(smsParts)),
+				Sequence:  byte(idx + 1),
+			}
+		} else {
+			concatenation = Concatenated16Bit{
+				Reference: reference,
+				Total:     byte(len(smsParts)),
+				Sequence:  byte(idx + 1),
+			}
 		}
-		binary.Write(udh, byteOrder, int8(len(smsParts)))
-		binary.Write(udh, byteOrder, int8(idx+1))
 
-		smsParts[idx].udh = udh.String()
+		// a single concatenation element always fits in a UDH
+		udh := UDH{InformationElements: []InformationElement{concatenation}}
+		data, _ := udh.Marshal()
+		smsParts[idx].udh = string(data)
 	}
 
 	return smsParts
 }
 
+// appendSegments numbers SMS parts identified by reference without adding a UDH
+func appendSegments(smsParts []SMS, reference uint16) []SMS {
+	// short circuit for too few SMS parts
+	if len(smsParts) <= 1 {
+		return smsParts
+	}
+
+	for idx := range smsParts {
+		smsParts[idx].segment = Concatenated16Bit{
+			Reference: reference,
+			Total:     byte(len(smsParts)),
+			Sequence:  byte(idx + 1),
+		}
+	}
+	return smsParts
+}
+
+// nationalLanguageIEs returns the UDH information elements which identify the
+// national language shift tables used by encoder
+func nationalLanguageIEs(encoder Encoder) []InformationElement {
+	var infoElements []InformationElement
+
+	gsm, isGSM := encoder.(*GSM)
+	if !isGSM {
+		return nil
+	}
+	if gsm.singleShift != NationalLanguageDefault {
+		infoElements = append(infoElements, NationalLanguageSingleShift{Language: gsm.singleShift})
+	}
+	if gsm.lockingShift != NationalLanguageDefault {
+		infoElements = append(infoElements, NationalLanguageLockingShift{Language: gsm.lockingShift})
+	}
+	return infoElements
+}
+
+// infoElementsByteLength returns the number of bytes information elements take in a UDH
+func infoElementsByteLength(infoElements []InformationElement) int {
+	var length int
+	for _, infoElement := range infoElements {
+		length += 2 + len(infoElement.GetData())
+	}
+	return length
+}
+
+// appendInfoElements appends information elements to the UDH of each SMS part,
+// creating the UDH if the part does not have one
+func appendInfoElements(smsParts []SMS, infoElements []InformationElement) []SMS {
+	if len(infoElements) == 0 {
+		return smsParts
+	}
+
+	for idx := range smsParts {
+		var udh UDH
+		if len(smsParts[idx].udh) > 0 {
+			udh.Unmarshal([]byte(smsParts[idx].udh))
+		}
+		udh.InformationElements = append(udh.InformationElements, infoElements...)
+
+		data, _ := udh.Marshal()
+		smsParts[idx].udh = string(data)
+	}
+	return smsParts
+}
+
 func autoDetectEncoder(message string) Encoder {
 	runeSet := []rune(message)
 	for _, char := range runeSet {
@@ -182,4 +618,3 @@ func autoDetectEncoder(message string) Encoder {
 	}
 	return NewGSM()
 }
-
@@ -1,7 +1,9 @@
 package gosms
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -399,7 +401,7 @@ func TestAppendUDHsMakesNoChanges(t *testing.T) {
 	}
 
 	for _, tt := range TestAppendUDHsMakesNoChanges {
-		SMSs := appendUDHs(tt.SMSs, true)
+		SMSs := appendUDHs(tt.SMSs, 0xAB, true)
 
 		// check SMS number
 		assert.Equal(t, len(tt.SMSs), len(SMSs))
@@ -447,7 +449,7 @@ func TestAppendUDHsAddsUDHWithShortReferenceNumber(t *testing.T) {
 	}
 
 	for _, tt := range TestAppendUDHsAddsUDHWithShortReferenceNumber {
-		SMSs := appendUDHs(tt.SMSs, true)
+		SMSs := appendUDHs(tt.SMSs, 0xAB, true)
 
 		// check SMS number
 		assert.Equal(t, len(tt.SMSs), len(SMSs))
@@ -466,7 +468,7 @@ func TestAppendUDHsAddsUDHWithShortReferenceNumber(t *testing.T) {
 			assert.Equal(t, int8(udhByteLengthShort-1), int8(sms.udh[0]))
 			assert.Equal(t, int8(shortReferenceInfoElementID), int8(sms.udh[1]))
 			assert.Equal(t, int8(udhByteLengthShort-3), int8(sms.udh[2]))
-			assert.Equal(t, int8(SMSs[0].udh[3]), int8(sms.udh[3])) // all SMSs should have the same reference number
+			assert.Equal(t, byte(0xAB), sms.udh[3]) // all SMSs should have the same reference number
 			assert.Equal(t, int8(len(SMSs)), int8(sms.udh[4]))
 			assert.Equal(t, int8(idx+1), int8(sms.udh[5]))
 		}
@@ -506,7 +508,7 @@ func TestAppendUDHsAddsUDHWithLongReferenceNumber(t *testing.T) {
 	}
 
 	for _, tt := range TestAppendUDHsAddsUDHWithLongReferenceNumber {
-		SMSs := appendUDHs(tt.SMSs, false)
+		SMSs := appendUDHs(tt.SMSs, 0xABCD, false)
 
 		// check SMS number
 		assert.Equal(t, len(tt.SMSs), len(SMSs))
@@ -525,10 +527,408 @@ func TestAppendUDHsAddsUDHWithLongReferenceNumber(t *testing.T) {
 			assert.Equal(t, int8(udhByteLengthLong-1), int8(sms.udh[0]))
 			assert.Equal(t, int8(longReferenceInfoElementID), int8(sms.udh[1]))
 			assert.Equal(t, int8(udhByteLengthLong-3), int8(sms.udh[2]))
-			assert.Equal(t, int8(SMSs[0].udh[3]), int8(sms.udh[3])) // all SMSs should have the same reference number
-			assert.Equal(t, int8(SMSs[0].udh[4]), int8(sms.udh[4])) // all SMSs should have the same reference number
+			assert.Equal(t, byte(0xAB), sms.udh[3]) // all SMSs should have the same reference number
+			assert.Equal(t, byte(0xCD), sms.udh[4]) // all SMSs should have the same reference number
 			assert.Equal(t, int8(len(SMSs)), int8(sms.udh[5]))
 			assert.Equal(t, int8(idx+1), int8(sms.udh[6]))
 		}
 	}
 }
+
+// this test ensures that the content of every SMS is encoded with the encoder
+// used to split it, with GSM septets following the fill bits after the UDH
+func TestSplitEncodesContent(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	var TestSplitEncodesContent = []struct {
+		name          string
+		message       string
+		encoder       Encoder
+		expectedBytes [][]byte
+	}{
+		{
+			"GSM with extended characters, no splitting",
+			"Hello [world] €",
+			NewGSM(),
+			[][]byte{
+				{0xC8, 0x32, 0x9B, 0xFD, 0x06, 0x6D, 0x78, 0xF7, 0xB7, 0x9C, 0x4D, 0xDE, 0xF8, 0x40, 0x9B, 0x32},
+			},
+		},
+		{
+			"GSM, splitting",
+			"Hello world, how are you?",
+			NewGSM(),
+			[][]byte{
+				// one fill bit follows the 6 byte UDH
+				{0x90, 0x65, 0x36, 0xFB, 0x0D, 0xBA, 0xBF, 0xE5, 0x6C, 0x32, 0x0B, 0x04},
+				{0xD0, 0xEF, 0x3B, 0x28, 0x2C, 0x2F, 0x83, 0xF2, 0xEF, 0xFA, 0x0F},
+			},
+		},
+		{
+			"UTF16, splitting",
+			"Hi 🙃, how are you?",
+			NewUTF16(),
+			[][]byte{
+				{0x00, 0x48, 0x00, 0x69, 0x00, 0x20, 0xD8, 0x3D, 0xDE, 0x43, 0x00, 0x2C, 0x00, 0x20},
+				{0x00, 0x68, 0x00, 0x6F, 0x00, 0x77, 0x00, 0x20, 0x00, 0x61, 0x00, 0x72, 0x00, 0x65},
+				{0x00, 0x20, 0x00, 0x79, 0x00, 0x6F, 0x00, 0x75, 0x00, 0x3F},
+			},
+		},
+	}
+
+	for _, tt := range TestSplitEncodesContent {
+		splitter := NewSplitter()
+		splitter.SetEncoder(tt.encoder)
+		splitter.SetMessageBytes(20)
+
+		SMSs, err := splitter.Split(from, []string{to}, tt.message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when splitting the message for test '%s'", err, tt.name)
+		}
+
+		assert.Equal(t, len(tt.expectedBytes), len(SMSs), tt.name)
+		for idx, sms := range SMSs {
+			assert.Equal(t, tt.expectedBytes[idx], sms.GetBytes(), tt.name)
+			assert.Equal(t, sms.GetUDH()+string(sms.GetBytes()), string(sms.GetUserData()), tt.name)
+		}
+	}
+}
+
+// this test ensures that concatenated GSM parts are padded so that their septets
+// start on a septet boundary after the UDH, and that capacity accounts for the padding
+func TestSplitPadsGSMUserData(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	var TestSplitPadsGSMUserData = []struct {
+		name           string
+		shortReference bool
+		messageBytes   int
+		expectedLength int
+		expectedFill   int
+	}{
+		{
+			"short reference, 153 septets",
+			true,
+			DefaultSMSBytes,
+			153,
+			1,
+		},
+		{
+			"long reference, 152 septets",
+			false,
+			DefaultSMSBytes,
+			152,
+			0,
+		},
+		{
+			"short reference, uneven message bytes",
+			true,
+			55,
+			55,
+			1,
+		},
+	}
+
+	for _, tt := range TestSplitPadsGSMUserData {
+		message := strings.Repeat("x", tt.expectedLength*2+1)
+
+		splitter := NewSplitter()
+		splitter.SetShortReference(tt.shortReference)
+		splitter.SetMessageBytes(tt.messageBytes)
+
+		SMSs, err := splitter.Split(from, []string{to}, message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when splitting the message for test '%s'", err, tt.name)
+		}
+
+		assert.Equal(t, 3, len(SMSs), tt.name)
+		assert.Equal(t, tt.expectedLength, len(SMSs[0].GetContent()), tt.name)
+		assert.Equal(t, tt.expectedFill, FillBits(len(SMSs[0].GetUDH())), tt.name)
+
+		for _, sms := range SMSs {
+			userData := sms.GetUserData()
+			assert.True(t, len(userData) <= tt.messageBytes, tt.name)
+
+			// the septets following the UDH and fill bits decode to the content
+			udhBits := len(sms.GetUDH())*byteLength + tt.expectedFill
+			septets := UnpackSeptets(userData, udhBits, len(sms.GetContent()))
+			decoded, err := NewGSM().(*GSM).DecodeSeptets(septets)
+			assert.Nil(t, err)
+			assert.Equal(t, sms.GetContent(), decoded, tt.name)
+		}
+	}
+}
+
+// this test ensures that a UCS-2 encoder counts replaced characters as single code points when splitting
+func TestSplitWithUCS2(t *testing.T) {
+	const from = "from"
+	const to = "to"
+	const message = "This message has 66 normal characters and 4 special characters... 🙃🙃🙃🙃"
+
+	// UTF-16 needs two parts for 74 code points
+	splitter := NewSplitter()
+	splitter.SetEncoder(NewUTF16())
+	SMSs, err := splitter.Split(from, []string{to}, message)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(SMSs))
+
+	// UCS-2 with replacement fits 70 code points into a single part
+	splitter.SetEncoder(NewUCS2WithReplacement('?'))
+	SMSs, err = splitter.Split(from, []string{to}, message)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(SMSs))
+	assert.Equal(t, 140, len(SMSs[0].GetBytes()))
+
+	// strict UCS-2 cannot encode the message
+	splitter.SetEncoder(NewUCS2())
+	SMSs, err = splitter.Split(from, []string{to}, message)
+	assert.Nil(t, SMSs)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+}
+
+// this test ensures that auto-selection chooses the encoder producing the fewest
+// parts, breaks ties by preference, and reports the encoder on every SMS
+func TestSplitAutoSelectsEncoder(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	var TestSplitAutoSelectsEncoder = []struct {
+		name          string
+		message       string
+		encoders      []Encoder
+		expectedName  string
+		expectedParts int
+	}{
+		{
+			"GSM is preferred",
+			"Hello",
+			nil,
+			EncoderNameGSM,
+			1,
+		},
+		{
+			"national single shift ties with locking shift",
+			"Işık",
+			nil,
+			"GSM (Turkish single shift)",
+			1,
+		},
+		{
+			"national locking shift needs fewer parts",
+			strings.Repeat("ş", 100),
+			nil,
+			"GSM (Turkish locking shift, Turkish single shift)",
+			1,
+		},
+		{
+			"UTF-16 for characters outside of every GSM table",
+			"你好朋友",
+			nil,
+			EncoderNameUTF16,
+			1,
+		},
+		{
+			"Latin-1 needs fewer parts than UTF-16",
+			strings.Repeat("þ", 100),
+			nil,
+			EncoderNameLatin1,
+			1,
+		},
+		{
+			"ties are broken by preference order",
+			"Hello",
+			[]Encoder{NewUTF16(), NewGSM()},
+			EncoderNameUTF16,
+			1,
+		},
+		{
+			"fewer parts win regardless of preference order",
+			strings.Repeat("x", 100),
+			[]Encoder{NewUTF16(), NewGSM()},
+			EncoderNameGSM,
+			1,
+		},
+	}
+
+	for _, tt := range TestSplitAutoSelectsEncoder {
+		splitter := NewSplitter()
+		splitter.SetAutoSelect(true)
+		if tt.encoders != nil {
+			splitter.SetEncoders(tt.encoders...)
+		}
+
+		SMSs, err := splitter.Split(from, []string{to}, tt.message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when splitting the message for test '%s'", err, tt.name)
+		}
+
+		assert.Equal(t, tt.expectedParts, len(SMSs), tt.name)
+		for _, sms := range SMSs {
+			assert.Equal(t, tt.expectedName, sms.GetEncoder().GetEncoderName(), tt.name)
+		}
+	}
+
+	// check that registered encoders are considered
+	splitter := NewSplitter()
+	splitter.SetAutoSelect(true)
+	splitter.SetEncoders(NewGSM(), NewUTF16())
+	splitter.RegisterEncoder(NewLatin1())
+	SMSs, err := splitter.Split(from, []string{to}, strings.Repeat("þ", 100))
+	assert.Nil(t, err)
+	assert.Equal(t, EncoderNameLatin1, SMSs[0].GetEncoder().GetEncoderName())
+
+	// check that a specified encoder takes precedence over auto-selection
+	splitter.SetEncoder(NewUTF16())
+	SMSs, err = splitter.Split(from, []string{to}, "Hello")
+	assert.Nil(t, err)
+	assert.Equal(t, EncoderNameUTF16, SMSs[0].GetEncoder().GetEncoderName())
+
+	// check that auto-selection fails when no encoder can encode the message
+	splitter = NewSplitter()
+	splitter.SetAutoSelect(true)
+	splitter.SetEncoders(NewGSM())
+	SMSs, err = splitter.Split(from, []string{to}, "你好朋友")
+	assert.Nil(t, SMSs)
+	assert.EqualError(t, err, ErrNotEncodable.Error())
+}
+
+// this test ensures that the reference allocator of the splitter identifies concatenated messages
+func TestSplitUsesReferenceAllocator(t *testing.T) {
+	const message = "This message is long enough that it has to be split into several parts."
+
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetReferenceAllocator(NewCounterReferenceAllocator(DefaultReferenceDestinations))
+
+	for idx := 0; idx < 3; idx++ {
+		smsParts, err := splitter.Split("from", []string{"to"}, message)
+		assert.NoError(t, err)
+		assert.True(t, len(smsParts) > 1)
+
+		for _, sms := range smsParts {
+			assert.Equal(t, byte(idx), sms.udh[3])
+		}
+	}
+
+	// single part messages do not use up a reference
+	_, err := splitter.Split("from", []string{"to"}, "short")
+	assert.NoError(t, err)
+	smsParts, err := splitter.Split("from", []string{"to"}, message)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(3), smsParts[0].udh[3])
+}
+
+// this test ensures that an allocation error is returned by Split
+func TestSplitReturnsReferenceAllocationError(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetReferenceAllocator(NewLRUReferenceAllocator(time.Hour, 1))
+
+	for idx := 0; idx < shortReferenceCount; idx++ {
+		_, err := splitter.Split("from", []string{"to"}, "This message is long enough that it has to be split into several parts.")
+		assert.NoError(t, err)
+	}
+
+	_, err := splitter.Split("from", []string{"to"}, "This message is long enough that it has to be split into several parts.")
+	assert.Equal(t, ErrReferencesExhausted, err)
+}
+
+func TestSplitSetsMessageClass(t *testing.T) {
+	var TestSplitSetsMessageClass = []struct {
+		name               string
+		encoder            Encoder
+		messageClass       MessageClass
+		expectedDataCoding byte
+	}{
+		{"no class", NewGSM(), MessageClassNone, 0x00},
+		{"flash GSM", NewGSM(), MessageClass0, 0x10},
+		{"flash UTF-16", NewUTF16(), MessageClass0, 0x18},
+		{"SIM specific", NewGSM(), MessageClass2, 0x12},
+	}
+
+	for _, tt := range TestSplitSetsMessageClass {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			splitter.SetMessageBytes(40)
+			splitter.SetMessageClass(tt.messageClass)
+
+			smsParts, err := splitter.Split("from", []string{"+14155550123"}, "This message is long enough that it has to be split into several parts.")
+			assert.NoError(t, err)
+			assert.True(t, len(smsParts) > 1)
+
+			for _, sms := range smsParts {
+				assert.Equal(t, tt.messageClass, sms.GetMessageClass())
+
+				submit, err := NewSMSSubmit(sms, "")
+				assert.NoError(t, err)
+				tpdu, err := submit.Marshal()
+				assert.NoError(t, err)
+				// first octet, TP-MR, 8 byte TP-DA and TP-PID precede the TP-DCS
+				assert.Equal(t, tt.expectedDataCoding, tpdu[11])
+
+				var decoded SMSSubmit
+				assert.NoError(t, decoded.Unmarshal(tpdu))
+				decodedSMS := decoded.GetSMS()
+				assert.Equal(t, tt.messageClass, decodedSMS.GetMessageClass())
+			}
+		})
+	}
+}
+
+func TestSplitConcatenation(t *testing.T) {
+	message := strings.Repeat("a", 200)
+
+	var TestSplitConcatenation = []struct {
+		name            string
+		concatenation   Concatenation
+		expectedLengths []int
+		expectedUDH     bool
+		expectedSegment bool
+	}{
+		{"UDH", ConcatenationUDH, []int{153, 47}, true, false},
+		{"SAR", ConcatenationSAR, []int{160, 40}, false, true},
+		{"payload", ConcatenationPayload, []int{200}, false, false},
+	}
+
+	for _, tt := range TestSplitConcatenation {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetConcatenation(tt.concatenation)
+
+			smsParts, err := splitter.Split("from", []string{"to"}, message)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.expectedLengths), len(smsParts))
+
+			for idx, sms := range smsParts {
+				assert.Equal(t, tt.expectedLengths[idx], len(sms.GetContent()))
+				assert.Equal(t, tt.concatenation, sms.GetConcatenation())
+				assert.Equal(t, tt.expectedUDH, sms.GetUDH() != "")
+
+				segment, ok := sms.GetSegment()
+				assert.Equal(t, tt.expectedSegment, ok)
+				if ok {
+					assert.Equal(t, byte(len(smsParts)), segment.Total)
+					assert.Equal(t, byte(idx+1), segment.Sequence)
+					assert.Equal(t, smsParts[0].segment.Reference, segment.Reference)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitPayloadKeepsSegmentLimit(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetConcatenation(ConcatenationPayload)
+	splitter.SetMaxSegments(2)
+
+	_, err := splitter.Split("from", []string{"to"}, strings.Repeat("a", 400))
+	assert.Equal(t, &TooManySegmentsError{segments: 3, maxSegments: 2}, err)
+
+	splitter.SetConcatenation(ConcatenationSAR)
+	smsParts, err := splitter.Split("from", []string{"to"}, strings.Repeat("a", 320))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(smsParts))
+}
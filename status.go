@@ -0,0 +1,190 @@
+package gosms
+
+import "time"
+
+const (
+	statusReportQualifierMask byte = 0x20
+
+	parameterProtocolIdentifier byte = 0x01
+	parameterDataCoding         byte = 0x02
+	parameterUserData           byte = 0x04
+	parameterExtensionMask      byte = 0x80
+
+	statusTemporaryError      byte = 0x20
+	statusPermanentError      byte = 0x40
+	statusTemporaryErrorFinal byte = 0x60
+	statusReserved            byte = 0x80
+)
+
+// DeliveryState is the state of a message as reported by an SMSC
+type DeliveryState int
+
+const (
+	// DeliveryStatePending means that the SMSC is still trying to deliver the message
+	DeliveryStatePending DeliveryState = iota
+
+	// DeliveryStateDelivered means that the message was delivered
+	DeliveryStateDelivered
+
+	// DeliveryStateFailed means that the SMSC has given up delivering the message
+	DeliveryStateFailed
+)
+
+var deliveryStateNames = map[DeliveryState]string{
+	DeliveryStatePending:   "Pending",
+	DeliveryStateDelivered: "Delivered",
+	DeliveryStateFailed:    "Failed",
+}
+
+// String returns the name of the delivery state
+func (s DeliveryState) String() string {
+	if name, ok := deliveryStateNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// StatusReport is an SMS-STATUS-REPORT TPDU, which reports the outcome of an SMS-SUBMIT
+type StatusReport struct {
+	messageReference byte
+	recipient        Address
+	timestamp        time.Time
+	dischargeTime    time.Time
+	status           byte
+	commandQualifier bool
+	sms              SMS
+}
+
+// GetMessageReference returns the TP-MR of the SMS-SUBMIT being reported on
+func (r *StatusReport) GetMessageReference() byte {
+	return r.messageReference
+}
+
+// GetRecipient returns the TP-RA of the StatusReport
+func (r *StatusReport) GetRecipient() Address {
+	return r.recipient
+}
+
+// GetTimestamp returns the TP-SCTS of the StatusReport, which is when the SMSC received the SMS-SUBMIT
+func (r *StatusReport) GetTimestamp() time.Time {
+	return r.timestamp
+}
+
+// GetDischargeTime returns the TP-DT of the StatusReport, which is when the status last changed
+func (r *StatusReport) GetDischargeTime() time.Time {
+	return r.dischargeTime
+}
+
+// GetStatus returns the TP-ST of the StatusReport
+func (r *StatusReport) GetStatus() byte {
+	return r.status
+}
+
+// GetState returns the delivery state described by the TP-ST of the StatusReport
+func (r *StatusReport) GetState() DeliveryState {
+	switch {
+	case r.status < statusTemporaryError:
+		return DeliveryStateDelivered
+	case r.status < statusPermanentError:
+		return DeliveryStatePending
+	}
+	return DeliveryStateFailed
+}
+
+// GetCommandQualifier returns true if the StatusReport is the result of an SMS-COMMAND rather than an SMS-SUBMIT
+func (r *StatusReport) GetCommandQualifier() bool {
+	return r.commandQualifier
+}
+
+// GetSMS returns the user data of the StatusReport, if it has any
+func (r *StatusReport) GetSMS() SMS {
+	return r.sms
+}
+
+// Unmarshal decodes a StatusReport from a TPDU
+func (r *StatusReport) Unmarshal(data []byte) error {
+	if len(data) < 2 || MessageType(data[0]&messageTypeMask) != MessageTypeStatusReport {
+		return ErrInvalidTPDU
+	}
+	firstOctet := data[0]
+
+	recipient, length, err := unmarshalTPAddress(data[2:])
+	if err != nil {
+		return err
+	}
+	idx := 2 + length
+	if idx+2*timestampLength >= len(data) {
+		return ErrInvalidTPDU
+	}
+	timestamp, err := decodeTimestamp(data[idx:])
+	if err != nil {
+		return err
+	}
+	dischargeTime, err := decodeTimestamp(data[idx+timestampLength:])
+	if err != nil {
+		return err
+	}
+	idx += 2 * timestampLength
+
+	status := data[idx]
+	if status >= statusReserved {
+		return ErrInvalidTPDU
+	}
+	idx++
+
+	sms, err := unmarshalStatusReportParameters(recipient.value, firstOctet&userDataHeaderMask != 0, data[idx:])
+	if err != nil {
+		return err
+	}
+
+	*r = StatusReport{
+		messageReference: data[1],
+		recipient:        recipient,
+		timestamp:        timestamp,
+		dischargeTime:    dischargeTime,
+		status:           status,
+		commandQualifier: firstOctet&statusReportQualifierMask != 0,
+		sms:              sms,
+	}
+	return nil
+}
+
+// unmarshalStatusReportParameters decodes the optional TP-PI, TP-PID, TP-DCS and
+// TP-UD which may follow the TP-ST of a StatusReport
+func unmarshalStatusReportParameters(to string, hasUDH bool, data []byte) (SMS, error) {
+	var dataCoding DCS
+
+	if len(data) == 0 {
+		return SMS{}, nil
+	}
+
+	// skip extended parameter indicators
+	parameters := data[0]
+	idx := 1
+	for data[idx-1]&parameterExtensionMask != 0 {
+		if idx >= len(data) {
+			return SMS{}, ErrInvalidTPDU
+		}
+		idx++
+	}
+
+	if parameters&parameterProtocolIdentifier != 0 {
+		idx++
+	}
+	if parameters&parameterDataCoding != 0 {
+		if idx >= len(data) {
+			return SMS{}, ErrInvalidTPDU
+		}
+		if err := dataCoding.Unmarshal(data[idx]); err != nil {
+			return SMS{}, err
+		}
+		idx++
+	}
+	if parameters&parameterUserData == 0 {
+		return SMS{}, nil
+	}
+	if idx >= len(data) {
+		return SMS{}, ErrInvalidTPDU
+	}
+	return unmarshalUserData("", to, dataCoding, hasUDH, int(data[idx]), data[idx+1:])
+}
@@ -0,0 +1,117 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// marshalStatusReport builds an SMS-STATUS-REPORT TPDU followed by parameters
+func marshalStatusReport(t *testing.T, messageReference byte, recipient string, status byte, parameters ...byte) []byte {
+	address, err := ParseAddress(recipient, "")
+	assert.NoError(t, err)
+	recipientField, err := address.MarshalTPAddress()
+	assert.NoError(t, err)
+
+	tpdu := append([]byte{byte(MessageTypeStatusReport), messageReference}, recipientField...)
+	tpdu = append(tpdu, encodeTimestamp(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))...)
+	tpdu = append(tpdu, encodeTimestamp(time.Date(2021, 3, 4, 5, 7, 8, 0, time.UTC))...)
+	tpdu = append(tpdu, status)
+	return append(tpdu, parameters...)
+}
+
+func TestStatusReportUnmarshal(t *testing.T) {
+	var report StatusReport
+	assert.NoError(t, report.Unmarshal(marshalStatusReport(t, 0x2A, "+14155550123", 0x00)))
+
+	assert.Equal(t, byte(0x2A), report.GetMessageReference())
+	recipient := report.GetRecipient()
+	assert.Equal(t, "+14155550123", recipient.GetValue())
+	assert.True(t, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC).Equal(report.GetTimestamp()))
+	assert.True(t, time.Date(2021, 3, 4, 5, 7, 8, 0, time.UTC).Equal(report.GetDischargeTime()))
+	assert.Equal(t, byte(0x00), report.GetStatus())
+	assert.Equal(t, DeliveryStateDelivered, report.GetState())
+	assert.False(t, report.GetCommandQualifier())
+	sms := report.GetSMS()
+	assert.Equal(t, "", sms.GetContent())
+}
+
+func TestStatusReportGetState(t *testing.T) {
+	var TestStatusReportGetState = []struct {
+		name     string
+		status   byte
+		expected DeliveryState
+	}{
+		{"received by the recipient", 0x00, DeliveryStateDelivered},
+		{"forwarded but unconfirmed", 0x01, DeliveryStateDelivered},
+		{"replaced", 0x02, DeliveryStateDelivered},
+		{"congestion, still trying", 0x20, DeliveryStatePending},
+		{"recipient busy, still trying", 0x21, DeliveryStatePending},
+		{"remote procedure error", 0x40, DeliveryStateFailed},
+		{"validity period expired", 0x46, DeliveryStateFailed},
+		{"congestion, no longer trying", 0x60, DeliveryStateFailed},
+	}
+
+	for _, tt := range TestStatusReportGetState {
+		t.Run(tt.name, func(t *testing.T) {
+			var report StatusReport
+			assert.NoError(t, report.Unmarshal(marshalStatusReport(t, 0x00, "+14155550123", tt.status)))
+			assert.Equal(t, tt.expected, report.GetState())
+		})
+	}
+}
+
+func TestStatusReportUnmarshalParameters(t *testing.T) {
+	var TestStatusReportUnmarshalParameters = []struct {
+		name            string
+		parameters      []byte
+		expectedContent string
+	}{
+		{"no user data", []byte{0x03, 0x00, 0x00}, ""},
+		{"GSM user data", []byte{0x06, 0x00, 0x0A, 0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37}, "hellohello"},
+		{"UCS-2 user data", []byte{0x07, 0x00, 0x08, 0x04, 0x00, 0x68, 0x00, 0x69}, "hi"},
+		{"extended parameter indicator", []byte{0x86, 0x00, 0x08, 0x04, 0x00, 0x68, 0x00, 0x69}, "hi"},
+	}
+
+	for _, tt := range TestStatusReportUnmarshalParameters {
+		t.Run(tt.name, func(t *testing.T) {
+			var report StatusReport
+			assert.NoError(t, report.Unmarshal(marshalStatusReport(t, 0x00, "+14155550123", 0x00, tt.parameters...)))
+			sms := report.GetSMS()
+			assert.Equal(t, tt.expectedContent, sms.GetContent())
+		})
+	}
+}
+
+func TestStatusReportUnmarshalReturnsErrorForMalformedTPDU(t *testing.T) {
+	report := marshalStatusReport(t, 0x00, "+14155550123", 0x00)
+
+	var TestStatusReportUnmarshalReturnsErrorForMalformedTPDU = []struct {
+		name string
+		tpdu []byte
+	}{
+		{"empty", []byte{}},
+		{"SMS-DELIVER", append([]byte{0x00}, report[1:]...)},
+		{"truncated address", report[:5]},
+		{"missing status", report[:len(report)-1]},
+		{"reserved status", append(append([]byte{}, report[:len(report)-1]...), 0x80)},
+		{"truncated extended parameter indicator", append(append([]byte{}, report...), 0x80)},
+		{"missing data coding", append(append([]byte{}, report...), 0x02)},
+		{"missing user data length", append(append([]byte{}, report...), 0x04)},
+	}
+
+	for _, tt := range TestStatusReportUnmarshalReturnsErrorForMalformedTPDU {
+		t.Run(tt.name, func(t *testing.T) {
+			var report StatusReport
+			assert.Equal(t, ErrInvalidTPDU, report.Unmarshal(tt.tpdu))
+		})
+	}
+}
+
+func TestDeliveryStateString(t *testing.T) {
+	assert.Equal(t, "Pending", DeliveryStatePending.String())
+	assert.Equal(t, "Delivered", DeliveryStateDelivered.String())
+	assert.Equal(t, "Failed", DeliveryStateFailed.String())
+	assert.Equal(t, "Unknown", DeliveryState(-1).String())
+}
@@ -0,0 +1,195 @@
+package gosms
+
+// SMSSubmit is an SMS-SUBMIT TPDU, which carries a message from a mobile station to an SMSC
+type SMSSubmit struct {
+	sms                SMS
+	destination        Address
+	messageReference   byte
+	rejectDuplicates   bool
+	statusReport       bool
+	replyPath          bool
+	protocolIdentifier byte
+	validityPeriod     ValidityPeriod
+}
+
+// NewSMSSubmit creates a new SMSSubmit for an SMS generated by a Splitter. A
+// national recipient is interpreted using defaultRegion, an ISO 3166-1 alpha-2
+// code such as "US", and the recipient cannot be alphanumeric.
+func NewSMSSubmit(sms SMS, defaultRegion string) (SMSSubmit, error) {
+	destination, err := ParseAddress(sms.to, defaultRegion)
+	if err != nil {
+		return SMSSubmit{}, err
+	}
+	if destination.addressType == AddressTypeAlphanumeric {
+		return SMSSubmit{}, ErrAlphanumericRecipient
+	}
+	return SMSSubmit{sms: sms, destination: destination}, nil
+}
+
+// GetSMS returns the SMS carried by the SMSSubmit
+func (s *SMSSubmit) GetSMS() SMS {
+	return s.sms
+}
+
+// GetDestination returns the TP-DA of the SMSSubmit
+func (s *SMSSubmit) GetDestination() Address {
+	return s.destination
+}
+
+// SetDestination sets the TP-DA of the SMSSubmit
+func (s *SMSSubmit) SetDestination(destination Address) {
+	s.destination = destination
+}
+
+// GetMessageReference returns the TP-MR of the SMSSubmit
+func (s *SMSSubmit) GetMessageReference() byte {
+	return s.messageReference
+}
+
+// SetMessageReference sets the TP-MR of the SMSSubmit
+func (s *SMSSubmit) SetMessageReference(messageReference byte) {
+	s.messageReference = messageReference
+}
+
+// GetRejectDuplicates returns the TP-RD of the SMSSubmit
+func (s *SMSSubmit) GetRejectDuplicates() bool {
+	return s.rejectDuplicates
+}
+
+// SetRejectDuplicates sets whether the SMSC should reject duplicates of the SMSSubmit
+func (s *SMSSubmit) SetRejectDuplicates(rejectDuplicates bool) {
+	s.rejectDuplicates = rejectDuplicates
+}
+
+// GetStatusReport returns the TP-SRR of the SMSSubmit
+func (s *SMSSubmit) GetStatusReport() bool {
+	return s.statusReport
+}
+
+// SetStatusReport sets whether a status report is requested for the SMSSubmit
+func (s *SMSSubmit) SetStatusReport(statusReport bool) {
+	s.statusReport = statusReport
+}
+
+// GetReplyPath returns the TP-RP of the SMSSubmit
+func (s *SMSSubmit) GetReplyPath() bool {
+	return s.replyPath
+}
+
+// SetReplyPath sets whether a reply path is requested for the SMSSubmit
+func (s *SMSSubmit) SetReplyPath(replyPath bool) {
+	s.replyPath = replyPath
+}
+
+// GetProtocolIdentifier returns the TP-PID of the SMSSubmit
+func (s *SMSSubmit) GetProtocolIdentifier() byte {
+	return s.protocolIdentifier
+}
+
+// SetProtocolIdentifier sets the TP-PID of the SMSSubmit
+func (s *SMSSubmit) SetProtocolIdentifier(protocolIdentifier byte) {
+	s.protocolIdentifier = protocolIdentifier
+}
+
+// GetValidityPeriod returns the TP-VP of the SMSSubmit
+func (s *SMSSubmit) GetValidityPeriod() ValidityPeriod {
+	return s.validityPeriod
+}
+
+// SetValidityPeriod sets the TP-VP of the SMSSubmit
+func (s *SMSSubmit) SetValidityPeriod(validityPeriod ValidityPeriod) {
+	s.validityPeriod = validityPeriod
+}
+
+// Marshal encodes the SMSSubmit as a TPDU, as described in 3GPP TS 23.040
+func (s *SMSSubmit) Marshal() ([]byte, error) {
+	firstOctet := byte(MessageTypeSubmit) | byte(s.validityPeriod.format)<<validityPeriodFormatShift
+	if s.rejectDuplicates {
+		firstOctet |= rejectDuplicatesMask
+	}
+	if s.statusReport {
+		firstOctet |= statusReportMask
+	}
+	if len(s.sms.udh) > 0 {
+		firstOctet |= userDataHeaderMask
+	}
+	if s.replyPath {
+		firstOctet |= replyPathMask
+	}
+
+	destination, err := s.destination.MarshalTPAddress()
+	if err != nil {
+		return nil, err
+	}
+	dcs, err := s.sms.GetDataCoding()
+	if err != nil {
+		return nil, err
+	}
+	dataCoding, err := dcs.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	userDataLength, userData, err := marshalUserData(s.sms)
+	if err != nil {
+		return nil, err
+	}
+
+	tpdu := []byte{firstOctet, s.messageReference}
+	tpdu = append(tpdu, destination...)
+	tpdu = append(tpdu, s.protocolIdentifier, dataCoding)
+	tpdu = append(tpdu, s.validityPeriod.marshal()...)
+	tpdu = append(tpdu, userDataLength)
+	return append(tpdu, userData...), nil
+}
+
+// Unmarshal decodes an SMSSubmit from a TPDU
+func (s *SMSSubmit) Unmarshal(data []byte) error {
+	if len(data) < 2 || MessageType(data[0]&messageTypeMask) != MessageTypeSubmit {
+		return ErrInvalidTPDU
+	}
+	firstOctet := data[0]
+	messageReference := data[1]
+
+	destination, length, err := unmarshalTPAddress(data[2:])
+	if err != nil {
+		return err
+	}
+	idx := 2 + length
+	if idx+2 > len(data) {
+		return ErrInvalidTPDU
+	}
+	protocolIdentifier := data[idx]
+	var dataCoding DCS
+	if err := dataCoding.Unmarshal(data[idx+1]); err != nil {
+		return err
+	}
+	idx += 2
+
+	format := ValidityPeriodFormat((firstOctet & validityPeriodFormatMask) >> validityPeriodFormatShift)
+	validityPeriod, length, err := unmarshalValidityPeriod(format, data[idx:])
+	if err != nil {
+		return err
+	}
+	idx += length
+	if idx >= len(data) {
+		return ErrInvalidTPDU
+	}
+
+	hasUDH := firstOctet&userDataHeaderMask != 0
+	sms, err := unmarshalUserData("", destination.value, dataCoding, hasUDH, int(data[idx]), data[idx+1:])
+	if err != nil {
+		return err
+	}
+
+	*s = SMSSubmit{
+		sms:                sms,
+		destination:        destination,
+		messageReference:   messageReference,
+		rejectDuplicates:   firstOctet&rejectDuplicatesMask != 0,
+		statusReport:       firstOctet&statusReportMask != 0,
+		replyPath:          firstOctet&replyPathMask != 0,
+		protocolIdentifier: protocolIdentifier,
+		validityPeriod:     validityPeriod,
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package gosms
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test encodes the well known "hellohello" SMS-SUBMIT
+func TestSMSSubmitMarshal(t *testing.T) {
+	smsParts, err := NewSplitter().Split("from", []string{"+46708251358"}, "hellohello")
+	assert.NoError(t, err)
+
+	submit, err := NewSMSSubmit(smsParts[0], "")
+	assert.NoError(t, err)
+
+	tpdu, err := submit.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00, 0x0A,
+		0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37,
+	}, tpdu)
+}
+
+func TestSMSSubmitMarshalSetsFirstOctet(t *testing.T) {
+	var TestSMSSubmitMarshalSetsFirstOctet = []struct {
+		name     string
+		setup    func(submit *SMSSubmit)
+		expected byte
+	}{
+		{"defaults", func(submit *SMSSubmit) {}, 0x01},
+		{"reject duplicates", func(submit *SMSSubmit) { submit.SetRejectDuplicates(true) }, 0x05},
+		{"relative validity period", func(submit *SMSSubmit) { submit.SetValidityPeriod(NewRelativeValidityPeriod(time.Hour)) }, 0x11},
+		{"enhanced validity period", func(submit *SMSSubmit) { submit.SetValidityPeriod(NewEnhancedValidityPeriod(time.Minute, false)) }, 0x09},
+		{"absolute validity period", func(submit *SMSSubmit) { submit.SetValidityPeriod(NewAbsoluteValidityPeriod(time.Now())) }, 0x19},
+		{"status report", func(submit *SMSSubmit) { submit.SetStatusReport(true) }, 0x21},
+		{"reply path", func(submit *SMSSubmit) { submit.SetReplyPath(true) }, 0x81},
+	}
+
+	smsParts, _ := NewSplitter().Split("from", []string{"+46708251358"}, "hellohello")
+	for _, tt := range TestSMSSubmitMarshalSetsFirstOctet {
+		t.Run(tt.name, func(t *testing.T) {
+			submit, _ := NewSMSSubmit(smsParts[0], "")
+			tt.setup(&submit)
+
+			tpdu, err := submit.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tpdu[0])
+		})
+	}
+}
+
+func TestSMSSubmitMarshalUserDataLength(t *testing.T) {
+	var TestSMSSubmitMarshalUserDataLength = []struct {
+		name               string
+		encoder            Encoder
+		message            string
+		expectedDataCoding byte
+		expectedLengths    []byte
+	}{
+		// a 6 byte UDH takes up 7 septets, leaving 153 for content
+		{"GSM", NewGSM(), strings.Repeat("A", 200), 0x00, []byte{160, 7 + 47}},
+		// a 6 byte UDH leaves 134 bytes, or 67 characters, for content
+		{"UTF-16", NewUTF16(), strings.Repeat("é", 100), 0x08, []byte{140, 6 + 66}},
+		{"Latin-1", NewLatin1(), strings.Repeat("é", 200), 0x04, []byte{140, 6 + 66}},
+	}
+
+	for _, tt := range TestSMSSubmitMarshalUserDataLength {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			smsParts, err := splitter.Split("from", []string{"+46708251358"}, tt.message)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.expectedLengths), len(smsParts))
+
+			for idx, sms := range smsParts {
+				submit, _ := NewSMSSubmit(sms, "")
+				tpdu, err := submit.Marshal()
+				assert.NoError(t, err)
+
+				// first octet, TP-MR, 8 byte TP-DA, TP-PID, then TP-DCS and TP-UDL
+				assert.Equal(t, byte(userDataHeaderMask|0x01), tpdu[0])
+				assert.Equal(t, tt.expectedDataCoding, tpdu[11])
+				assert.Equal(t, tt.expectedLengths[idx], tpdu[12])
+				assert.Equal(t, sms.GetUserData(), tpdu[13:])
+			}
+		})
+	}
+}
+
+func TestSMSSubmitRoundTrip(t *testing.T) {
+	turkish, _ := NewNationalGSM(NationalLanguageTurkish, NationalLanguageTurkish)
+	expiry := time.Date(2030, 6, 15, 13, 45, 30, 0, time.FixedZone("", -5*60*60))
+
+	var TestSMSSubmitRoundTrip = []struct {
+		name           string
+		encoder        Encoder
+		to             string
+		message        string
+		validityPeriod ValidityPeriod
+	}{
+		{"GSM", NewGSM(), "+14155550123", "This message has [extended] characters and is long enough to be split into parts.", ValidityPeriod{}},
+		{"GSM national language", turkish, "+905321234567", "Şişli'de buluşalım mı? Saat üçte geliyorum, gecikirsem ararım.", NewRelativeValidityPeriod(24 * time.Hour)},
+		{"UTF-16", NewUTF16(), "12345", "This message has emoji 😀 and is long enough to be split into parts.", NewAbsoluteValidityPeriod(expiry)},
+		{"Latin-1", NewLatin1(), "+14155550123", "Ce message est assez long pour être découpé en plusieurs parties.", NewEnhancedValidityPeriod(90*time.Minute, true)},
+	}
+
+	for _, tt := range TestSMSSubmitRoundTrip {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			splitter.SetMessageBytes(40)
+			smsParts, err := splitter.Split("from", []string{tt.to}, tt.message)
+			assert.NoError(t, err)
+			assert.True(t, len(smsParts) > 1)
+
+			for idx, sms := range smsParts {
+				submit, err := NewSMSSubmit(sms, "")
+				assert.NoError(t, err)
+				submit.SetMessageReference(byte(idx))
+				submit.SetStatusReport(true)
+				submit.SetProtocolIdentifier(0x41)
+				submit.SetValidityPeriod(tt.validityPeriod)
+
+				tpdu, err := submit.Marshal()
+				assert.NoError(t, err)
+
+				var decoded SMSSubmit
+				assert.NoError(t, decoded.Unmarshal(tpdu))
+
+				decodedSMS := decoded.GetSMS()
+				assert.Equal(t, sms.GetContent(), decodedSMS.GetContent())
+				assert.Equal(t, sms.GetUDH(), decodedSMS.GetUDH())
+				assert.Equal(t, sms.GetBytes(), decodedSMS.GetBytes())
+				assert.Equal(t, sms.GetEncoder().GetEncoderName(), decodedSMS.GetEncoder().GetEncoderName())
+				assert.Equal(t, tt.to, decodedSMS.GetTo())
+				assert.Equal(t, submit.GetDestination(), decoded.GetDestination())
+				assert.Equal(t, byte(idx), decoded.GetMessageReference())
+				assert.True(t, decoded.GetStatusReport())
+				assert.False(t, decoded.GetRejectDuplicates())
+				assert.False(t, decoded.GetReplyPath())
+				assert.Equal(t, byte(0x41), decoded.GetProtocolIdentifier())
+
+				validityPeriod := decoded.GetValidityPeriod()
+				assert.Equal(t, tt.validityPeriod.GetFormat(), validityPeriod.GetFormat())
+				assert.Equal(t, tt.validityPeriod.GetDuration(), validityPeriod.GetDuration())
+				assert.Equal(t, tt.validityPeriod.GetSingleShot(), validityPeriod.GetSingleShot())
+				assert.True(t, tt.validityPeriod.GetTime().Equal(validityPeriod.GetTime()))
+			}
+		})
+	}
+}
+
+// this test ensures that a national recipient is interpreted using the default region
+func TestNewSMSSubmitNationalRecipient(t *testing.T) {
+	submit, err := NewSMSSubmit(newSMS("from", "(415) 555-0123", "content", ""), "US")
+
+	assert.NoError(t, err)
+	destination := submit.GetDestination()
+	assert.Equal(t, "+14155550123", destination.GetValue())
+	assert.Equal(t, TypeOfNumberInternational, destination.GetTypeOfNumber())
+}
+
+func TestNewSMSSubmitReturnsErrorForInvalidRecipient(t *testing.T) {
+	var TestNewSMSSubmitReturnsErrorForInvalidRecipient = []struct {
+		name        string
+		to          string
+		expectedErr error
+	}{
+		{"national number", "4155550123", ErrUnsupportedRegion},
+		{"alphanumeric", "TextNow", ErrAlphanumericRecipient},
+	}
+
+	for _, tt := range TestNewSMSSubmitReturnsErrorForInvalidRecipient {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSMSSubmit(newSMS("from", tt.to, "content", ""), "")
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestSMSSubmitMarshalReturnsError(t *testing.T) {
+	var TestSMSSubmitMarshalReturnsError = []struct {
+		name        string
+		sms         SMS
+		expectedErr error
+	}{
+		{"unencoded SMS", newSMS("from", "+14155550123", "content", ""), ErrUnsupportedDataCoding},
+		{"user data too long", SMS{to: "+14155550123", data: make([]byte, 141), encoder: NewLatin1()}, ErrUserDataTooLong},
+	}
+
+	for _, tt := range TestSMSSubmitMarshalReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			submit, err := NewSMSSubmit(tt.sms, "")
+			assert.NoError(t, err)
+
+			_, err = submit.Marshal()
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestSMSSubmitUnmarshalReturnsErrorForMalformedTPDU(t *testing.T) {
+	var TestSMSSubmitUnmarshalReturnsErrorForMalformedTPDU = []struct {
+		name        string
+		tpdu        []byte
+		expectedErr error
+	}{
+		{"empty", []byte{}, ErrInvalidTPDU},
+		{"SMS-DELIVER", []byte{0x00, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00, 0x00}, ErrInvalidTPDU},
+		{"truncated address", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07}, ErrInvalidTPDU},
+		{"missing data coding", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00}, ErrInvalidTPDU},
+		{"missing validity period", []byte{0x19, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00, 0x01}, ErrInvalidTPDU},
+		{"missing user data length", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00}, ErrInvalidTPDU},
+		{"user data shorter than its length", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x00, 0x0A, 0xE8, 0x32}, ErrInvalidTPDU},
+		{"UCS-2 user data shorter than its length", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x08, 0x04, 0x00}, ErrInvalidTPDU},
+		{"unsupported data coding", []byte{0x01, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x0C, 0x00}, ErrUnsupportedDataCoding},
+		{"malformed UDH", []byte{0x41, 0x00, 0x0B, 0x91, 0x64, 0x07, 0x28, 0x15, 0x53, 0xF8, 0x00, 0x04, 0x02, 0x05, 0x00}, ErrInvalidUDH},
+	}
+
+	for _, tt := range TestSMSSubmitUnmarshalReturnsErrorForMalformedTPDU {
+		t.Run(tt.name, func(t *testing.T) {
+			var submit SMSSubmit
+			assert.Equal(t, tt.expectedErr, submit.Unmarshal(tt.tpdu))
+		})
+	}
+}
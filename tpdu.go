@@ -0,0 +1,388 @@
+package gosms
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	maxUserDataBytes int = 140
+
+	messageTypeMask          byte = 0x03
+	rejectDuplicatesMask     byte = 0x04
+	moreMessagesToSendMask   byte = 0x04
+	validityPeriodFormatMask byte = 0x18
+	statusReportMask         byte = 0x20
+	userDataHeaderMask       byte = 0x40
+	replyPathMask            byte = 0x80
+
+	validityPeriodFormatShift uint = 3
+
+	timestampLength       int  = 7
+	timezoneSignMask      byte = 0x08
+	quarterHour                = 15 * time.Minute
+	timestampCenturyPivot int  = 2070
+
+	enhancedExtensionMask  byte = 0x80
+	enhancedSingleShotMask byte = 0x40
+	enhancedFormatMask     byte = 0x07
+)
+
+var (
+	// ErrInvalidTPDU indicates that a TPDU is truncated or malformed
+	ErrInvalidTPDU = errors.New("the TPDU is malformed")
+
+	// ErrUnsupportedDataCoding indicates that an encoder or data coding scheme has no TPDU equivalent
+	ErrUnsupportedDataCoding = errors.New("the data coding scheme is not supported")
+
+	// ErrUserDataTooLong indicates that user data does not fit in a single TPDU
+	ErrUserDataTooLong = errors.New("the user data is longer than 140 bytes")
+)
+
+// MessageType is the TP-MTI of a TPDU
+type MessageType byte
+
+const (
+	// MessageTypeDeliver identifies an SMS-DELIVER sent to a mobile station
+	MessageTypeDeliver MessageType = 0x00
+
+	// MessageTypeSubmit identifies an SMS-SUBMIT sent by a mobile station
+	MessageTypeSubmit MessageType = 0x01
+
+	// MessageTypeStatusReport identifies an SMS-STATUS-REPORT sent to a mobile station
+	MessageTypeStatusReport MessageType = 0x02
+)
+
+// ValidityPeriodFormat is the TP-VPF of an SMS-SUBMIT
+type ValidityPeriodFormat byte
+
+const (
+	// ValidityPeriodNone means that no validity period is given
+	ValidityPeriodNone ValidityPeriodFormat = 0x00
+
+	// ValidityPeriodEnhanced is a validity period in the enhanced format
+	ValidityPeriodEnhanced ValidityPeriodFormat = 0x01
+
+	// ValidityPeriodRelative is a validity period relative to when the SMSC receives the message
+	ValidityPeriodRelative ValidityPeriodFormat = 0x02
+
+	// ValidityPeriodAbsolute is a validity period ending at a point in time
+	ValidityPeriodAbsolute ValidityPeriodFormat = 0x03
+)
+
+// enhanced validity period formats
+const (
+	enhancedNone     byte = 0x00
+	enhancedRelative byte = 0x01
+	enhancedSeconds  byte = 0x02
+	enhancedHours    byte = 0x03
+)
+
+// ValidityPeriod is how long an SMSC should try to deliver a message
+type ValidityPeriod struct {
+	format     ValidityPeriodFormat
+	duration   time.Duration
+	time       time.Time
+	singleShot bool
+}
+
+// NewRelativeValidityPeriod returns a relative validity period. Since only some
+// durations can be represented, duration is rounded up to the next one.
+func NewRelativeValidityPeriod(duration time.Duration) ValidityPeriod {
+	return ValidityPeriod{format: ValidityPeriodRelative, duration: duration}
+}
+
+// NewAbsoluteValidityPeriod returns a validity period ending at expiry, to the second
+func NewAbsoluteValidityPeriod(expiry time.Time) ValidityPeriod {
+	return ValidityPeriod{format: ValidityPeriodAbsolute, time: expiry}
+}
+
+// NewEnhancedValidityPeriod returns a validity period in the enhanced format.
+// Single shot messages are only attempted once.
+func NewEnhancedValidityPeriod(duration time.Duration, singleShot bool) ValidityPeriod {
+	return ValidityPeriod{format: ValidityPeriodEnhanced, duration: duration, singleShot: singleShot}
+}
+
+// GetFormat returns the format of the validity period
+func (v *ValidityPeriod) GetFormat() ValidityPeriodFormat {
+	return v.format
+}
+
+// GetDuration returns the duration of a relative or enhanced validity period
+func (v *ValidityPeriod) GetDuration() time.Duration {
+	return v.duration
+}
+
+// GetTime returns the expiry of an absolute validity period
+func (v *ValidityPeriod) GetTime() time.Time {
+	return v.time
+}
+
+// GetSingleShot returns true if an enhanced validity period is single shot
+func (v *ValidityPeriod) GetSingleShot() bool {
+	return v.singleShot
+}
+
+// marshal encodes the validity period as its TP-VP field
+func (v *ValidityPeriod) marshal() []byte {
+	switch v.format {
+	case ValidityPeriodRelative:
+		return []byte{encodeRelativeValidity(v.duration)}
+	case ValidityPeriodAbsolute:
+		return encodeTimestamp(v.time)
+	case ValidityPeriodEnhanced:
+		return v.marshalEnhanced()
+	}
+	return nil
+}
+
+// marshalEnhanced encodes an enhanced validity period, using whole seconds or
+// hours, minutes and seconds where possible
+func (v *ValidityPeriod) marshalEnhanced() []byte {
+	field := make([]byte, timestampLength)
+
+	seconds := int((v.duration + time.Second - 1) / time.Second)
+	switch {
+	case seconds <= 0xFF:
+		field[0] = enhancedSeconds
+		field[1] = byte(seconds)
+	case seconds < 100*60*60:
+		field[0] = enhancedHours
+		field[1] = encodeSemiOctet(seconds / 3600)
+		field[2] = encodeSemiOctet(seconds / 60 % 60)
+		field[3] = encodeSemiOctet(seconds % 60)
+	default:
+		field[0] = enhancedRelative
+		field[1] = encodeRelativeValidity(v.duration)
+	}
+	if v.singleShot {
+		field[0] |= enhancedSingleShotMask
+	}
+	return field
+}
+
+// unmarshalValidityPeriod decodes a TP-VP field of format from the start of data,
+// and returns the number of bytes it takes up
+func unmarshalValidityPeriod(format ValidityPeriodFormat, data []byte) (ValidityPeriod, int, error) {
+	switch format {
+	case ValidityPeriodRelative:
+		if len(data) < 1 {
+			return ValidityPeriod{}, 0, ErrInvalidTPDU
+		}
+		return ValidityPeriod{format: format, duration: decodeRelativeValidity(data[0])}, 1, nil
+	case ValidityPeriodAbsolute:
+		expiry, err := decodeTimestamp(data)
+		if err != nil {
+			return ValidityPeriod{}, 0, err
+		}
+		return ValidityPeriod{format: format, time: expiry}, timestampLength, nil
+	case ValidityPeriodEnhanced:
+		if len(data) < timestampLength || data[0]&enhancedExtensionMask != 0 {
+			return ValidityPeriod{}, 0, ErrInvalidTPDU
+		}
+		validityPeriod := ValidityPeriod{format: format, singleShot: data[0]&enhancedSingleShotMask != 0}
+		switch data[0] & enhancedFormatMask {
+		case enhancedNone:
+		case enhancedRelative:
+			validityPeriod.duration = decodeRelativeValidity(data[1])
+		case enhancedSeconds:
+			validityPeriod.duration = time.Duration(data[1]) * time.Second
+		case enhancedHours:
+			validityPeriod.duration = time.Duration(decodeSemiOctet(data[1]))*time.Hour +
+				time.Duration(decodeSemiOctet(data[2]))*time.Minute +
+				time.Duration(decodeSemiOctet(data[3]))*time.Second
+		default:
+			return ValidityPeriod{}, 0, ErrInvalidTPDU
+		}
+		return validityPeriod, timestampLength, nil
+	}
+	return ValidityPeriod{}, 0, nil
+}
+
+// encodeRelativeValidity returns the smallest relative TP-VP value lasting at least duration
+func encodeRelativeValidity(duration time.Duration) byte {
+	for value := 0; value < 0xFF; value++ {
+		if decodeRelativeValidity(byte(value)) >= duration {
+			return byte(value)
+		}
+	}
+	return 0xFF
+}
+
+// decodeRelativeValidity returns the duration of a relative TP-VP value
+func decodeRelativeValidity(value byte) time.Duration {
+	const day = 24 * time.Hour
+
+	switch {
+	case value <= 143:
+		return time.Duration(value+1) * 5 * time.Minute
+	case value <= 167:
+		return 12*time.Hour + time.Duration(value-143)*30*time.Minute
+	case value <= 196:
+		return time.Duration(value-166) * day
+	}
+	return time.Duration(value-192) * 7 * day
+}
+
+// encodeTimestamp encodes a time as swapped semi-octets of its year, month, day,
+// hour, minute, second and time zone in quarter hours
+func encodeTimestamp(timestamp time.Time) []byte {
+	_, offset := timestamp.Zone()
+	quarters := offset / int(quarterHour/time.Second)
+
+	timezone := encodeSemiOctet(quarters)
+	if quarters < 0 {
+		timezone = encodeSemiOctet(-quarters) | timezoneSignMask
+	}
+	return []byte{
+		encodeSemiOctet(timestamp.Year() % 100),
+		encodeSemiOctet(int(timestamp.Month())),
+		encodeSemiOctet(timestamp.Day()),
+		encodeSemiOctet(timestamp.Hour()),
+		encodeSemiOctet(timestamp.Minute()),
+		encodeSemiOctet(timestamp.Second()),
+		timezone,
+	}
+}
+
+// decodeTimestamp decodes a timestamp from the start of data. Two digit years
+// from 70 onwards are taken to be in the 20th century.
+func decodeTimestamp(data []byte) (time.Time, error) {
+	if len(data) < timestampLength {
+		return time.Time{}, ErrInvalidTPDU
+	}
+
+	year := 2000 + decodeSemiOctet(data[0])
+	if year >= timestampCenturyPivot {
+		year -= 100
+	}
+
+	quarters := decodeSemiOctet(data[6] &^ timezoneSignMask)
+	if data[6]&timezoneSignMask != 0 {
+		quarters = -quarters
+	}
+	location := time.FixedZone("", quarters*int(quarterHour/time.Second))
+
+	return time.Date(
+		year,
+		time.Month(decodeSemiOctet(data[1])),
+		decodeSemiOctet(data[2]),
+		decodeSemiOctet(data[3]),
+		decodeSemiOctet(data[4]),
+		decodeSemiOctet(data[5]),
+		0,
+		location,
+	), nil
+}
+
+// encodeSemiOctet encodes a two digit number as swapped semi-octets
+func encodeSemiOctet(value int) byte {
+	return byte(value%10)<<uint(semiOctetBits) | byte(value/10%10)
+}
+
+// decodeSemiOctet decodes a two digit number from swapped semi-octets
+func decodeSemiOctet(value byte) int {
+	return int(value&0x0F)*10 + int(value>>uint(semiOctetBits))
+}
+
+// marshalUserData returns the TP-UDL and TP-UD of an SMS. The length of GSM user
+// data is counted in septets, including those taken up by the UDH and fill bits.
+func marshalUserData(sms SMS) (byte, []byte, error) {
+	userData := sms.GetUserData()
+	if len(userData) > maxUserDataBytes {
+		return 0, nil, ErrUserDataTooLong
+	}
+
+	gsm, isGSM := sms.encoder.(*GSM)
+	if !isGSM {
+		return byte(len(userData)), userData, nil
+	}
+
+	septets, err := gsm.EncodeSeptets(sms.content)
+	if err != nil {
+		return 0, nil, err
+	}
+	return byte(UDHSeptets(len(sms.udh)) + len(septets)), userData, nil
+}
+
+// ParseUserData parses an SMS from from to to out of TP-UD. For GSM, length is
+// the TP-UDL in septets, including those taken up by the UDH and fill bits, and
+// otherwise in octets. National language shift tables identified in the UDH are
+// used to decode GSM content.
+func ParseUserData(from string, to string, dataCoding DCS, hasUDH bool, length int, userData []byte) (SMS, error) {
+	return unmarshalUserData(from, to, dataCoding, hasUDH, length, userData)
+}
+
+// unmarshalUserData decodes TP-UD into an SMS from from to to. National language
+// shift tables identified in the UDH are used to decode GSM content.
+func unmarshalUserData(from string, to string, dataCoding DCS, hasUDH bool, length int, userData []byte) (SMS, error) {
+	var udh UDH
+
+	if dataCoding.compressed {
+		return SMS{}, ErrUnsupportedDataCoding
+	}
+	if hasUDH {
+		if err := udh.Unmarshal(userData); err != nil {
+			return SMS{}, err
+		}
+	}
+
+	var encoder Encoder
+	switch dataCoding.characterSet {
+	case CharacterSetGSM:
+		lockingShift, singleShift := NationalLanguageDefault, NationalLanguageDefault
+		for _, infoElement := range udh.InformationElements {
+			switch shift := infoElement.(type) {
+			case NationalLanguageLockingShift:
+				lockingShift = shift.Language
+			case NationalLanguageSingleShift:
+				singleShift = shift.Language
+			}
+		}
+		gsm, err := NewNationalGSM(lockingShift, singleShift)
+		if err != nil {
+			return SMS{}, err
+		}
+		sms, err := unmarshalGSMUserData(from, to, hasUDH, length, userData, gsm.(*GSM))
+		sms.messageClass = dataCoding.messageClass
+		return sms, err
+	case CharacterSet8Bit:
+		encoder = NewLatin1()
+	case CharacterSetUCS2:
+		encoder = NewUTF16()
+	default:
+		return SMS{}, ErrUnsupportedDataCoding
+	}
+
+	sms, err := ParseSMS(from, to, userData, hasUDH, length, encoder)
+	sms.messageClass = dataCoding.messageClass
+	return sms, err
+}
+
+// unmarshalGSMUserData decodes GSM TP-UD whose length is given in septets
+func unmarshalGSMUserData(from string, to string, hasUDH bool, septetCount int, userData []byte, gsm *GSM) (SMS, error) {
+	var udhLength int
+
+	if (septetCount*septetBits+byteLength-1)/byteLength > len(userData) {
+		return SMS{}, ErrInvalidTPDU
+	}
+	if hasUDH {
+		udhLength = int(userData[0]) + 1
+	}
+
+	udhSeptets := UDHSeptets(udhLength)
+	if udhSeptets > septetCount {
+		return SMS{}, ErrInvalidTPDU
+	}
+	data := userData[udhLength:]
+	septets := UnpackSeptets(data, FillBits(udhLength), septetCount-udhSeptets)
+	content, err := gsm.DecodeSeptets(septets)
+	if err != nil {
+		return SMS{}, err
+	}
+
+	sms := newSMS(from, to, content, string(userData[:udhLength]))
+	sms.data = append([]byte(nil), data[:(FillBits(udhLength)+len(septets)*septetBits+byteLength-1)/byteLength]...)
+	sms.encoder = gsm
+	return sms, nil
+}
@@ -0,0 +1,142 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelativeValidityPeriod(t *testing.T) {
+	var TestRelativeValidityPeriod = []struct {
+		name             string
+		duration         time.Duration
+		expectedValue    byte
+		expectedDuration time.Duration
+	}{
+		{"zero", 0, 0, 5 * time.Minute},
+		{"five minutes", 5 * time.Minute, 0, 5 * time.Minute},
+		{"rounded up to five minutes", 6 * time.Minute, 1, 10 * time.Minute},
+		{"twelve hours", 12 * time.Hour, 143, 12 * time.Hour},
+		{"half hours", 13 * time.Hour, 145, 13 * time.Hour},
+		{"one day", 24 * time.Hour, 167, 24 * time.Hour},
+		{"days", 3 * 24 * time.Hour, 169, 3 * 24 * time.Hour},
+		{"thirty days", 30 * 24 * time.Hour, 196, 30 * 24 * time.Hour},
+		{"weeks", 31 * 24 * time.Hour, 197, 35 * 24 * time.Hour},
+		{"longest", 63 * 7 * 24 * time.Hour, 255, 63 * 7 * 24 * time.Hour},
+		{"capped", 100 * 7 * 24 * time.Hour, 255, 63 * 7 * 24 * time.Hour},
+	}
+
+	for _, tt := range TestRelativeValidityPeriod {
+		t.Run(tt.name, func(t *testing.T) {
+			validityPeriod := NewRelativeValidityPeriod(tt.duration)
+			field := validityPeriod.marshal()
+			assert.Equal(t, []byte{tt.expectedValue}, field)
+
+			decoded, length, err := unmarshalValidityPeriod(ValidityPeriodRelative, field)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, length)
+			assert.Equal(t, tt.expectedDuration, decoded.GetDuration())
+		})
+	}
+}
+
+func TestEnhancedValidityPeriod(t *testing.T) {
+	var TestEnhancedValidityPeriod = []struct {
+		name             string
+		duration         time.Duration
+		singleShot       bool
+		expected         []byte
+		expectedDuration time.Duration
+	}{
+		{"seconds", 90 * time.Second, false, []byte{0x02, 0x5A, 0, 0, 0, 0, 0}, 90 * time.Second},
+		{"single shot", 90 * time.Second, true, []byte{0x42, 0x5A, 0, 0, 0, 0, 0}, 90 * time.Second},
+		{"hours minutes and seconds", 12*time.Hour + 34*time.Minute + 56*time.Second, false, []byte{0x03, 0x21, 0x43, 0x65, 0, 0, 0}, 12*time.Hour + 34*time.Minute + 56*time.Second},
+		{"relative", 30 * 24 * time.Hour, false, []byte{0x01, 196, 0, 0, 0, 0, 0}, 30 * 24 * time.Hour},
+	}
+
+	for _, tt := range TestEnhancedValidityPeriod {
+		t.Run(tt.name, func(t *testing.T) {
+			validityPeriod := NewEnhancedValidityPeriod(tt.duration, tt.singleShot)
+			field := validityPeriod.marshal()
+			assert.Equal(t, tt.expected, field)
+
+			decoded, length, err := unmarshalValidityPeriod(ValidityPeriodEnhanced, field)
+			assert.NoError(t, err)
+			assert.Equal(t, timestampLength, length)
+			assert.Equal(t, tt.expectedDuration, decoded.GetDuration())
+			assert.Equal(t, tt.singleShot, decoded.GetSingleShot())
+		})
+	}
+}
+
+func TestUnmarshalValidityPeriodReturnsError(t *testing.T) {
+	var TestUnmarshalValidityPeriodReturnsError = []struct {
+		name   string
+		format ValidityPeriodFormat
+		data   []byte
+	}{
+		{"missing relative", ValidityPeriodRelative, []byte{}},
+		{"truncated absolute", ValidityPeriodAbsolute, []byte{0x03, 0x60, 0x51}},
+		{"truncated enhanced", ValidityPeriodEnhanced, []byte{0x02, 0x5A}},
+		{"extended enhanced", ValidityPeriodEnhanced, []byte{0x82, 0x5A, 0, 0, 0, 0, 0}},
+		{"reserved enhanced format", ValidityPeriodEnhanced, []byte{0x07, 0x5A, 0, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range TestUnmarshalValidityPeriodReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := unmarshalValidityPeriod(tt.format, tt.data)
+			assert.Equal(t, ErrInvalidTPDU, err)
+		})
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	var TestTimestamp = []struct {
+		name      string
+		timestamp time.Time
+		expected  []byte
+	}{
+		{"UTC", time.Date(2030, 6, 15, 13, 45, 30, 0, time.UTC), []byte{0x03, 0x60, 0x51, 0x31, 0x54, 0x03, 0x00}},
+		{"ahead of UTC", time.Date(2021, 12, 1, 9, 5, 0, 0, time.FixedZone("", 5*60*60+30*60)), []byte{0x12, 0x21, 0x10, 0x90, 0x50, 0x00, 0x22}},
+		{"behind UTC", time.Date(2021, 1, 31, 23, 59, 59, 0, time.FixedZone("", -8*60*60)), []byte{0x12, 0x10, 0x13, 0x32, 0x95, 0x95, 0x2B}},
+	}
+
+	for _, tt := range TestTimestamp {
+		t.Run(tt.name, func(t *testing.T) {
+			field := encodeTimestamp(tt.timestamp)
+			assert.Equal(t, tt.expected, field)
+
+			decoded, err := decodeTimestamp(field)
+			assert.NoError(t, err)
+			assert.True(t, tt.timestamp.Equal(decoded))
+
+			_, offset := decoded.Zone()
+			_, expectedOffset := tt.timestamp.Zone()
+			assert.Equal(t, expectedOffset, offset)
+		})
+	}
+}
+
+func TestParseUserData(t *testing.T) {
+	var TestParseUserData = []struct {
+		name            string
+		dataCoding      DCS
+		length          int
+		userData        []byte
+		expectedContent string
+	}{
+		// a trailing @ is kept since the septet count is known
+		{"GSM", NewDCS(CharacterSetGSM, MessageClassNone), 8, []byte{0xE8, 0x32, 0x9B, 0xFD, 0x06, 0x00, 0x00}, "hello@@@"},
+		{"UCS-2", NewDCS(CharacterSetUCS2, MessageClass1), 4, []byte{0x00, 0x68, 0x00, 0x69}, "hi"},
+	}
+
+	for _, tt := range TestParseUserData {
+		t.Run(tt.name, func(t *testing.T) {
+			sms, err := ParseUserData("from", "to", tt.dataCoding, false, tt.length, tt.userData)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, sms.GetContent())
+			assert.Equal(t, tt.dataCoding.GetMessageClass(), sms.GetMessageClass())
+		})
+	}
+}
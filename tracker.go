@@ -0,0 +1,283 @@
+package gosms
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTrackingTimeout is how long a message is tracked while waiting for status reports
+const DefaultTrackingTimeout = 72 * time.Hour
+
+// ErrUnknownReport indicates that a status report does not match a tracked SMS
+var ErrUnknownReport = errors.New("the status report does not match a tracked SMS")
+
+// trackingKey identifies an SMS-SUBMIT by the digits of its recipient and its
+// TP-MR, since SMSCs may give the TP-RA with a different type of number
+type trackingKey struct {
+	recipient        string
+	messageReference byte
+}
+
+// trackedMessage holds the parts of a tracked message and their delivery states
+type trackedMessage struct {
+	id      uint64
+	parts   []SMS
+	keys    []trackingKey
+	states  []DeliveryState
+	tracked time.Time
+	element *list.Element
+}
+
+// getState rolls the states of the parts up into the state of the whole message.
+// A message has failed if any part failed, and is delivered once every part is.
+func (m *trackedMessage) getState() DeliveryState {
+	state := DeliveryStateDelivered
+	for _, partState := range m.states {
+		switch partState {
+		case DeliveryStateFailed:
+			return DeliveryStateFailed
+		case DeliveryStatePending:
+			state = DeliveryStatePending
+		}
+	}
+	return state
+}
+
+// isFinal returns true once no part of the message is pending
+func (m *trackedMessage) isFinal() bool {
+	for _, partState := range m.states {
+		if partState == DeliveryStatePending {
+			return false
+		}
+	}
+	return true
+}
+
+// DeliveryReceipt links a status report to the SMS part it acknowledges
+type DeliveryReceipt struct {
+	messageID    uint64
+	sms          SMS
+	part         int
+	report       StatusReport
+	messageState DeliveryState
+}
+
+// GetMessageID returns the ID DeliveryTracker.Track returned for the message
+func (r *DeliveryReceipt) GetMessageID() uint64 {
+	return r.messageID
+}
+
+// GetSMS returns the SMS part the status report acknowledges
+func (r *DeliveryReceipt) GetSMS() SMS {
+	return r.sms
+}
+
+// GetPart returns the index of the SMS part within its message
+func (r *DeliveryReceipt) GetPart() int {
+	return r.part
+}
+
+// GetReport returns the status report
+func (r *DeliveryReceipt) GetReport() StatusReport {
+	return r.report
+}
+
+// GetPartState returns the delivery state of the SMS part
+func (r *DeliveryReceipt) GetPartState() DeliveryState {
+	return r.report.GetState()
+}
+
+// GetMessageState returns the delivery state of the whole message
+func (r *DeliveryReceipt) GetMessageState() DeliveryState {
+	return r.messageState
+}
+
+// DeliveryTracker assigns message references to SMS parts and correlates status
+// reports with them. Since a TP-MR is a single byte, a message is forgotten once
+// the reference of one of its parts is reused for the same recipient. Messages
+// still waiting for status reports are also forgotten once the timeout passes.
+type DeliveryTracker struct {
+	mutex            sync.Mutex
+	messageReference byte
+	defaultRegion    string
+	nextID           uint64
+	timeout          time.Duration
+	expiryHandler    func(uint64)
+	now              func() time.Time
+	parts            map[trackingKey]*trackedMessage
+	messages         map[uint64]*trackedMessage
+	order            *list.List
+}
+
+// NewDeliveryTracker creates a new DeliveryTracker
+func NewDeliveryTracker() *DeliveryTracker {
+	return &DeliveryTracker{
+		timeout:  DefaultTrackingTimeout,
+		now:      time.Now,
+		parts:    make(map[trackingKey]*trackedMessage),
+		messages: make(map[uint64]*trackedMessage),
+		order:    list.New(),
+	}
+}
+
+// SetTimeout sets how long a message is tracked after it is submitted
+func (t *DeliveryTracker) SetTimeout(timeout time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.timeout = timeout
+}
+
+// SetExpiryHandler sets a function which is called with the ID of each message
+// which expired before all of its status reports arrived
+func (t *DeliveryTracker) SetExpiryHandler(expiryHandler func(uint64)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.expiryHandler = expiryHandler
+}
+
+// SetDefaultRegion sets the ISO 3166-1 alpha-2 region used to interpret national recipients
+func (t *DeliveryTracker) SetDefaultRegion(defaultRegion string) {
+	t.defaultRegion = defaultRegion
+}
+
+// Track assigns a message reference to each SMS part generated for a message,
+// and returns an ID for the message and the parts as SMSSubmits requesting
+// status reports
+func (t *DeliveryTracker) Track(smsParts []SMS) (uint64, []SMSSubmit, error) {
+	t.Expire()
+
+	var submits []SMSSubmit
+
+	for _, sms := range smsParts {
+		submit, err := NewSMSSubmit(sms, t.defaultRegion)
+		if err != nil {
+			return 0, nil, err
+		}
+		submit.SetStatusReport(true)
+		submits = append(submits, submit)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.nextID++
+	message := &trackedMessage{
+		id:      t.nextID,
+		parts:   smsParts,
+		keys:    make([]trackingKey, len(smsParts)),
+		states:  make([]DeliveryState, len(smsParts)),
+		tracked: t.now(),
+	}
+	for idx := range submits {
+		submits[idx].SetMessageReference(t.messageReference)
+		key := trackingKey{recipient: submits[idx].destination.GetDigits(), messageReference: t.messageReference}
+		t.messageReference++
+
+		// the reference is being reused, so the message it belonged to can no longer be tracked
+		if previous, ok := t.parts[key]; ok {
+			t.forget(previous)
+		}
+		t.parts[key] = message
+		message.keys[idx] = key
+	}
+	t.messages[message.id] = message
+	message.element = t.order.PushBack(message)
+	return message.id, submits, nil
+}
+
+// Correlate links a status report to the SMS part it acknowledges, and returns
+// ErrUnknownReport if there is none. Messages are forgotten once none of their
+// parts are pending.
+func (t *DeliveryTracker) Correlate(report StatusReport) (DeliveryReceipt, error) {
+	t.Expire()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := trackingKey{recipient: report.recipient.GetDigits(), messageReference: report.messageReference}
+	message, ok := t.parts[key]
+	if !ok {
+		return DeliveryReceipt{}, ErrUnknownReport
+	}
+
+	part := 0
+	for idx, partKey := range message.keys {
+		if partKey == key {
+			part = idx
+		}
+	}
+	message.states[part] = report.GetState()
+
+	if message.isFinal() {
+		t.forget(message)
+	}
+	return DeliveryReceipt{
+		messageID:    message.id,
+		sms:          message.parts[part],
+		part:         part,
+		report:       report,
+		messageState: message.getState(),
+	}, nil
+}
+
+// GetMessageState returns the delivery state of a tracked message, and false if
+// the message is not being tracked
+func (t *DeliveryTracker) GetMessageState(messageID uint64) (DeliveryState, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	message, ok := t.messages[messageID]
+	if !ok {
+		return DeliveryStatePending, false
+	}
+	return message.getState(), true
+}
+
+// Expire forgets messages whose timeout has passed, passing the ID of each to
+// the expiry handler. It is called by Track and Correlate, and can be called
+// periodically to expire messages when none are being sent.
+func (t *DeliveryTracker) Expire() {
+	var expired []uint64
+
+	t.mutex.Lock()
+	now := t.now()
+	// messages are kept in the order they were tracked, so the oldest expire first
+	for front := t.order.Front(); front != nil; front = t.order.Front() {
+		message := front.Value.(*trackedMessage)
+		if now.Sub(message.tracked) < t.timeout {
+			break
+		}
+		t.forget(message)
+		expired = append(expired, message.id)
+	}
+	expiryHandler := t.expiryHandler
+	t.mutex.Unlock()
+
+	// the handler is called without holding the lock so that it may use the DeliveryTracker
+	if expiryHandler == nil {
+		return
+	}
+	for _, messageID := range expired {
+		expiryHandler(messageID)
+	}
+}
+
+// GetTracked returns the number of messages being tracked
+func (t *DeliveryTracker) GetTracked() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.messages)
+}
+
+// forget stops tracking message
+func (t *DeliveryTracker) forget(message *trackedMessage) {
+	for _, key := range message.keys {
+		if t.parts[key] == message {
+			delete(t.parts, key)
+		}
+	}
+	delete(t.messages, message.id)
+	t.order.Remove(message.element)
+}
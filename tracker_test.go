@@ -0,0 +1,217 @@
+package gosms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const trackerMessage = "This message is long enough that it has to be split into several parts."
+
+// splitForTracking splits trackerMessage into parts for to
+func splitForTracking(t *testing.T, to string) []SMS {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+
+	smsParts, err := splitter.Split("from", []string{to}, trackerMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(smsParts))
+	return smsParts
+}
+
+// correlate decodes a status report for a submit and correlates it
+func correlate(t *testing.T, tracker *DeliveryTracker, submit SMSSubmit, status byte) (DeliveryReceipt, error) {
+	destination := submit.GetDestination()
+
+	var report StatusReport
+	assert.NoError(t, report.Unmarshal(marshalStatusReport(t, submit.GetMessageReference(), destination.GetValue(), status)))
+	return tracker.Correlate(report)
+}
+
+func TestDeliveryTrackerAssignsMessageReferences(t *testing.T) {
+	tracker := NewDeliveryTracker()
+
+	firstID, firstSubmits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+	secondID, secondSubmits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, firstID, secondID)
+	for idx, submit := range append(firstSubmits, secondSubmits...) {
+		assert.Equal(t, byte(idx), submit.GetMessageReference())
+		assert.True(t, submit.GetStatusReport())
+	}
+}
+
+func TestDeliveryTrackerRollsUpReceipts(t *testing.T) {
+	var TestDeliveryTrackerRollsUpReceipts = []struct {
+		name                  string
+		statuses              []byte
+		expectedPartStates    []DeliveryState
+		expectedMessageStates []DeliveryState
+	}{
+		{
+			"all parts delivered",
+			[]byte{0x00, 0x00},
+			[]DeliveryState{DeliveryStateDelivered, DeliveryStateDelivered},
+			[]DeliveryState{DeliveryStatePending, DeliveryStateDelivered},
+		},
+		{
+			"one part failed",
+			[]byte{0x00, 0x46},
+			[]DeliveryState{DeliveryStateDelivered, DeliveryStateFailed},
+			[]DeliveryState{DeliveryStatePending, DeliveryStateFailed},
+		},
+		{
+			"failure before other parts",
+			[]byte{0x41, 0x20},
+			[]DeliveryState{DeliveryStateFailed, DeliveryStatePending},
+			[]DeliveryState{DeliveryStateFailed, DeliveryStateFailed},
+		},
+	}
+
+	for _, tt := range TestDeliveryTrackerRollsUpReceipts {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewDeliveryTracker()
+			smsParts := splitForTracking(t, "+14155550123")
+			messageID, submits, err := tracker.Track(smsParts)
+			assert.NoError(t, err)
+
+			for idx, submit := range submits {
+				receipt, err := correlate(t, tracker, submit, tt.statuses[idx])
+				assert.NoError(t, err)
+				assert.Equal(t, messageID, receipt.GetMessageID())
+				assert.Equal(t, idx, receipt.GetPart())
+				assert.Equal(t, smsParts[idx], receipt.GetSMS())
+				assert.Equal(t, submit.GetMessageReference(), receipt.GetReport().messageReference)
+				assert.Equal(t, tt.expectedPartStates[idx], receipt.GetPartState())
+				assert.Equal(t, tt.expectedMessageStates[idx], receipt.GetMessageState())
+			}
+		})
+	}
+}
+
+func TestDeliveryTrackerForgetsFinalMessages(t *testing.T) {
+	tracker := NewDeliveryTracker()
+	messageID, submits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+
+	correlate(t, tracker, submits[0], 0x20)
+	state, ok := tracker.GetMessageState(messageID)
+	assert.True(t, ok)
+	assert.Equal(t, DeliveryStatePending, state)
+
+	correlate(t, tracker, submits[0], 0x00)
+	correlate(t, tracker, submits[1], 0x00)
+	_, ok = tracker.GetMessageState(messageID)
+	assert.False(t, ok)
+
+	// late duplicate reports no longer match
+	_, err = correlate(t, tracker, submits[1], 0x00)
+	assert.Equal(t, ErrUnknownReport, err)
+}
+
+func TestDeliveryTrackerMatchesRecipients(t *testing.T) {
+	tracker := NewDeliveryTracker()
+	_, submits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+
+	// the same reference for another recipient does not match
+	var report StatusReport
+	assert.NoError(t, report.Unmarshal(marshalStatusReport(t, submits[0].GetMessageReference(), "+14155550199", 0x00)))
+	_, err = tracker.Correlate(report)
+	assert.Equal(t, ErrUnknownReport, err)
+}
+
+func TestDeliveryTrackerMatchesRecipientsWithOtherTypeOfNumber(t *testing.T) {
+	tracker := NewDeliveryTracker()
+	_, submits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+
+	// the SMSC gives the TP-RA with an unknown type of number
+	tpdu := []byte{byte(MessageTypeStatusReport), submits[0].GetMessageReference(), 11, 0x81}
+	tpdu = append(tpdu, encodeSemiOctets("14155550123")...)
+	tpdu = append(tpdu, encodeTimestamp(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))...)
+	tpdu = append(tpdu, encodeTimestamp(time.Date(2021, 3, 4, 5, 7, 8, 0, time.UTC))...)
+	tpdu = append(tpdu, 0x00)
+
+	var report StatusReport
+	assert.NoError(t, report.Unmarshal(tpdu))
+	recipient := report.GetRecipient()
+	assert.Equal(t, TypeOfNumberNational, recipient.GetTypeOfNumber())
+
+	receipt, err := tracker.Correlate(report)
+	assert.NoError(t, err)
+	assert.Equal(t, DeliveryStateDelivered, receipt.GetPartState())
+}
+
+func TestDeliveryTrackerForgetsMessagesWhenReferencesAreReused(t *testing.T) {
+	tracker := NewDeliveryTracker()
+	firstID, _, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+
+	// use up the remaining references
+	for idx := 0; idx < 127; idx++ {
+		_, _, err := tracker.Track(splitForTracking(t, "+14155550123"))
+		assert.NoError(t, err)
+	}
+	_, ok := tracker.GetMessageState(firstID)
+	assert.True(t, ok)
+
+	reusedID, submits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0), submits[0].GetMessageReference())
+	_, ok = tracker.GetMessageState(firstID)
+	assert.False(t, ok)
+
+	receipt, err := correlate(t, tracker, submits[0], 0x00)
+	assert.NoError(t, err)
+	assert.Equal(t, reusedID, receipt.GetMessageID())
+}
+
+func TestDeliveryTrackerExpiresMessages(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tracker := NewDeliveryTracker()
+	tracker.now = func() time.Time { return now }
+	tracker.SetTimeout(time.Hour)
+
+	var expired []uint64
+	tracker.SetExpiryHandler(func(messageID uint64) {
+		expired = append(expired, messageID)
+	})
+
+	firstID, firstSubmits, err := tracker.Track(splitForTracking(t, "+14155550123"))
+	assert.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	secondID, _, err := tracker.Track(splitForTracking(t, "+14155550199"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tracker.GetTracked())
+
+	// only the first message has been tracked for the timeout
+	now = now.Add(30 * time.Minute)
+	tracker.Expire()
+	assert.Equal(t, []uint64{firstID}, expired)
+	assert.Equal(t, 1, tracker.GetTracked())
+	_, ok := tracker.GetMessageState(firstID)
+	assert.False(t, ok)
+	_, ok = tracker.GetMessageState(secondID)
+	assert.True(t, ok)
+
+	// late reports for an expired message no longer match
+	_, err = correlate(t, tracker, firstSubmits[0], 0x00)
+	assert.Equal(t, ErrUnknownReport, err)
+
+	now = now.Add(30 * time.Minute)
+	tracker.Expire()
+	assert.Equal(t, []uint64{firstID, secondID}, expired)
+	assert.Equal(t, 0, tracker.GetTracked())
+}
+
+func TestDeliveryTrackerReturnsErrorForInvalidRecipient(t *testing.T) {
+	tracker := NewDeliveryTracker()
+
+	_, _, err := tracker.Track([]SMS{newSMS("from", "TextNow", "content", "")})
+
+	assert.Equal(t, ErrAlphanumericRecipient, err)
+}
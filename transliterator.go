@@ -0,0 +1,140 @@
+package gosms
+
+import "strings"
+
+const (
+	fullWidthStart  rune = 0xFF01
+	fullWidthEnd    rune = 0xFF5E
+	fullWidthOffset rune = 0xFEE0
+)
+
+// transliterations maps characters outside of the GSM alphabet to GSM replacements
+// which preserve their meaning
+var transliterations = map[rune]string{
+	// quotes and primes
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '`': "'", '´': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	// dashes and punctuation
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'…': "...", '•': "*", '·': ".", '÷': "/", '×': "x",
+	// spaces
+	'\u00A0': " ", '\u2000': " ", '\u2001': " ", '\u2002': " ", '\u2003': " ",
+	'\u2004': " ", '\u2005': " ", '\u2006': " ", '\u2007': " ", '\u2008': " ",
+	'\u2009': " ", '\u200A': " ", '\u202F': " ", '\u205F': " ", '\u3000': " ",
+	'\t': " ",
+	// invisible characters
+	'\u200B': "", '\uFEFF': "", '\u00AD': "",
+	// symbols
+	'©': "(C)", '®': "(R)", '™': "TM", '¢': "c",
+	// accented latin letters
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'č': "c", 'Ć': "C", 'Č': "C",
+	'ď': "d", 'Ď': "D", 'ð': "d", 'Ð': "D",
+	'È': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ę': "E", 'Ě': "E",
+	'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'Ğ': "G",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'İ': "I",
+	'í': "i", 'î': "i", 'ï': "i", 'ı': "i",
+	'ł': "l", 'Ł': "L",
+	'ń': "n", 'ň': "n", 'Ń': "N", 'Ň': "N",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ő': "O",
+	'ó': "o", 'ô': "o", 'õ': "o", 'ő': "o",
+	'œ': "oe", 'Œ': "OE",
+	'ř': "r", 'Ř': "R",
+	'ś': "s", 'ş': "s", 'š': "s", 'Ś': "S", 'Ş': "S", 'Š': "S",
+	'ť': "t", 'Ť': "T", 'þ': "th", 'Þ': "Th",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ů': "U", 'Ű': "U",
+	'ú': "u", 'û': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y", 'Ÿ': "Y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+}
+
+// Substitution describes a character replaced during transliteration
+type Substitution struct {
+	char        rune
+	replacement string
+	count       int
+}
+
+// GetChar returns the character which was replaced
+func (s *Substitution) GetChar() rune {
+	return s.char
+}
+
+// GetReplacement returns the string which replaced the character
+func (s *Substitution) GetReplacement() string {
+	return s.replacement
+}
+
+// GetCount returns the number of times the character was replaced
+func (s *Substitution) GetCount() int {
+	return s.count
+}
+
+// Transliterator replaces characters with equivalents from the GSM alphabet
+type Transliterator struct {
+	mappings map[rune]string
+}
+
+// NewTransliterator returns a new Transliterator with the built-in mappings for
+// smart quotes, dashes, ellipses, spaces, accented latin letters and full-width forms
+func NewTransliterator() *Transliterator {
+	mappings := make(map[rune]string, len(transliterations))
+	for char, replacement := range transliterations {
+		mappings[char] = replacement
+	}
+	return &Transliterator{mappings: mappings}
+}
+
+// Register maps char to replacement, overriding any existing mapping
+func (t *Transliterator) Register(char rune, replacement string) {
+	t.mappings[char] = replacement
+}
+
+// Transliterate replaces mapped characters in message, and returns the
+// substitutions that were made in order of their first appearance
+func (t *Transliterator) Transliterate(message string) (string, []Substitution) {
+	return t.transliterate(message, nil)
+}
+
+// transliterate replaces mapped characters in message which encoder cannot
+// encode. A nil encoder replaces every mapped character.
+func (t *Transliterator) transliterate(message string, encoder Encoder) (string, []Substitution) {
+	var transliterated strings.Builder
+	var substitutions []Substitution
+	substitutionIndex := make(map[rune]int)
+
+	for _, char := range message {
+		replacement, ok := t.getReplacement(char)
+		if ok && encoder != nil {
+			_, err := encoder.GetCodePoints(char)
+			ok = err != nil
+		}
+		if !ok {
+			transliterated.WriteRune(char)
+			continue
+		}
+		transliterated.WriteString(replacement)
+
+		if idx, seen := substitutionIndex[char]; seen {
+			substitutions[idx].count++
+			continue
+		}
+		substitutionIndex[char] = len(substitutions)
+		substitutions = append(substitutions, Substitution{char: char, replacement: replacement, count: 1})
+	}
+	return transliterated.String(), substitutions
+}
+
+// getReplacement returns the replacement for char, if there is one
+func (t *Transliterator) getReplacement(char rune) (string, bool) {
+	if replacement, ok := t.mappings[char]; ok {
+		return replacement, true
+	}
+	// full-width forms map onto ascii
+	if char >= fullWidthStart && char <= fullWidthEnd {
+		return string(char - fullWidthOffset), true
+	}
+	return "", false
+}
@@ -0,0 +1,182 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this test ensures that the built-in mappings only replace non-GSM characters with GSM strings
+func TestTransliterationsAreGSM(t *testing.T) {
+	encoder := NewGSM()
+
+	for char, replacement := range transliterations {
+		assert.False(t, encoder.CheckEncodability(string(char)), string(char))
+		assert.True(t, encoder.CheckEncodability(replacement), string(char))
+	}
+}
+
+// this test ensures that Transliterate replaces characters and reports the substitutions
+func TestTransliterate(t *testing.T) {
+	var TestTransliterate = []struct {
+		name                  string
+		message               string
+		expected              string
+		expectedSubstitutions []Substitution
+	}{
+		{
+			"nothing to replace",
+			"Hello world",
+			"Hello world",
+			nil,
+		},
+		{
+			"smart quotes, dashes and ellipses",
+			"“Don’t” — it’s fine…",
+			"\"Don't\" - it's fine...",
+			[]Substitution{
+				{char: '“', replacement: "\"", count: 1},
+				{char: '’', replacement: "'", count: 2},
+				{char: '”', replacement: "\"", count: 1},
+				{char: '—', replacement: "-", count: 1},
+				{char: '…', replacement: "...", count: 1},
+			},
+		},
+		{
+			"non-breaking and zero width spaces",
+			"10 km​",
+			"10 km",
+			[]Substitution{
+				{char: ' ', replacement: " ", count: 1},
+				{char: '​', replacement: "", count: 1},
+			},
+		},
+		{
+			"accented latin letters",
+			"Canción Łódź",
+			"Cancion Lodz",
+			[]Substitution{
+				{char: 'ó', replacement: "o", count: 2},
+				{char: 'Ł', replacement: "L", count: 1},
+				{char: 'ź', replacement: "z", count: 1},
+			},
+		},
+		{
+			"full-width forms",
+			"ＳＭＳ！",
+			"SMS!",
+			[]Substitution{
+				{char: 'Ｓ', replacement: "S", count: 2},
+				{char: 'Ｍ', replacement: "M", count: 1},
+				{char: '！', replacement: "!", count: 1},
+			},
+		},
+		{
+			"unmapped characters are kept",
+			"你好’",
+			"你好'",
+			[]Substitution{
+				{char: '’', replacement: "'", count: 1},
+			},
+		},
+	}
+
+	for _, tt := range TestTransliterate {
+		transliterated, substitutions := NewTransliterator().Transliterate(tt.message)
+
+		assert.Equal(t, tt.expected, transliterated, tt.name)
+		assert.Equal(t, tt.expectedSubstitutions, substitutions, tt.name)
+	}
+
+	// check the substitution accessors
+	_, substitutions := NewTransliterator().Transliterate("’’")
+	assert.Equal(t, '’', substitutions[0].GetChar())
+	assert.Equal(t, "'", substitutions[0].GetReplacement())
+	assert.Equal(t, 2, substitutions[0].GetCount())
+}
+
+// this test ensures that custom mappings can be registered
+func TestTransliteratorRegister(t *testing.T) {
+	transliterator := NewTransliterator()
+	transliterator.Register('♥', "<3")
+	transliterator.Register('’', "`")
+
+	transliterated, substitutions := transliterator.Transliterate("I ♥ it’s")
+
+	assert.Equal(t, "I <3 it`s", transliterated)
+	assert.Equal(t, 2, len(substitutions))
+}
+
+// this test ensures that Split transliterates messages only when that keeps them in GSM
+func TestSplitTransliterates(t *testing.T) {
+	const from = "from"
+	const to = "to"
+
+	var TestSplitTransliterates = []struct {
+		name            string
+		message         string
+		autoSelect      bool
+		expected        string
+		expectedEncoder string
+	}{
+		{
+			"GSM messages are unchanged",
+			"Hello world",
+			false,
+			"Hello world",
+			EncoderNameGSM,
+		},
+		{
+			"curly quote no longer forces UTF-16",
+			"It’s here",
+			false,
+			"It's here",
+			EncoderNameGSM,
+		},
+		{
+			"messages which stay UTF-16 are unchanged",
+			"It’s 你好",
+			false,
+			"It’s 你好",
+			EncoderNameUTF16,
+		},
+		{
+			"national language characters are kept when auto-selecting",
+			"Işık’",
+			true,
+			"Işık'",
+			"GSM (Turkish single shift)",
+		},
+	}
+
+	for _, tt := range TestSplitTransliterates {
+		splitter := NewSplitter()
+		splitter.SetAutoSelect(tt.autoSelect)
+		splitter.SetTransliterator(NewTransliterator())
+
+		SMSs, err := splitter.Split(from, []string{to}, tt.message)
+		if err != nil {
+			t.Fatalf("an error '%s' was encountered when splitting the message for test '%s'", err, tt.name)
+		}
+
+		assert.Equal(t, 1, len(SMSs), tt.name)
+		assert.Equal(t, tt.expected, SMSs[0].GetContent(), tt.name)
+		assert.Equal(t, tt.expectedEncoder, SMSs[0].GetEncoder().GetEncoderName(), tt.name)
+	}
+
+	// check that Analyze reports the substitutions
+	splitter := NewSplitter()
+	splitter.SetTransliterator(NewTransliterator())
+	analysis, err := splitter.Analyze("It’s – here")
+	assert.Nil(t, err)
+	assert.Equal(t, EncoderNameGSM, analysis.GetEncoder().GetEncoderName())
+	assert.Equal(t, 2, len(analysis.GetSubstitutions()))
+	assert.Nil(t, analysis.GetFallbackChars())
+
+	// check that transliteration is disabled by default
+	splitter = NewSplitter()
+	SMSs, err := splitter.Split(from, []string{to}, "It’s here")
+	assert.Nil(t, err)
+	assert.Equal(t, "It’s here", SMSs[0].GetContent())
+	assert.Equal(t, EncoderNameUTF16, SMSs[0].GetEncoder().GetEncoderName())
+}
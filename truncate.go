@@ -0,0 +1,89 @@
+package gosms
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultMaxSegments is the most parts a concatenated message can have
+	DefaultMaxSegments int = 255
+
+	// DefaultTruncationSuffix is appended to truncated messages
+	DefaultTruncationSuffix = "..."
+)
+
+// TooManySegmentsError indicates that a message needs more segments than the splitter allows
+type TooManySegmentsError struct {
+	segments    int
+	maxSegments int
+}
+
+// Error returns a description of the error
+func (e *TooManySegmentsError) Error() string {
+	return fmt.Sprintf("the message needs %d segments, but at most %d are allowed", e.segments, e.maxSegments)
+}
+
+// GetSegments returns the number of segments the message needs
+func (e *TooManySegmentsError) GetSegments() int {
+	return e.segments
+}
+
+// GetMaxSegments returns the maximum number of segments allowed
+func (e *TooManySegmentsError) GetMaxSegments() int {
+	return e.maxSegments
+}
+
+// getMaxSegments returns the maximum number of segments, which can never exceed
+// the 255 parts a concatenation information element can number
+func (s *Splitter) getMaxSegments() int {
+	if s.maxSegments <= 0 || s.maxSegments > DefaultMaxSegments {
+		return DefaultMaxSegments
+	}
+	return s.maxSegments
+}
+
+// truncateMessage returns the longest prefix of message which, followed by the
+// truncation suffix, fits in the maximum number of segments. Messages are cut
+// at a word boundary if possible, and otherwise between grapheme clusters.
+func (s *Splitter) truncateMessage(message string) (string, error) {
+	var boundaries, wordBoundaries []int
+
+	// find the offsets at which the message can be cut
+	runes := []rune(message)
+	offset := 0
+	for _, cluster := range graphemeClusters(runes) {
+		boundaries = append(boundaries, offset)
+		if offset > 0 && unicode.IsSpace(cluster[0]) {
+			wordBoundaries = append(wordBoundaries, offset)
+		}
+		offset += len(cluster)
+	}
+
+	truncate := func(end int) string {
+		truncated, _ := s.transliterate(strings.TrimRightFunc(string(runes[:end]), unicode.IsSpace) + s.truncationSuffix)
+		return truncated
+	}
+	fits := func(end int) bool {
+		truncated := truncate(end)
+		encoder, err := s.selectEncoder(truncated)
+		if err != nil {
+			return false
+		}
+		messageParts, _, err := s.splitMessage(truncated, encoder)
+		return err == nil && len(messageParts) <= s.getMaxSegments()
+	}
+
+	// shorter prefixes never need more segments, so search for the first which does not fit
+	for _, candidates := range [][]int{wordBoundaries, boundaries} {
+		idx := sort.Search(len(candidates), func(idx int) bool {
+			return !fits(candidates[idx])
+		})
+		if idx > 0 {
+			return truncate(candidates[idx-1]), nil
+		}
+	}
+	return "", ErrNotSplittable
+}
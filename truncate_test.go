@@ -0,0 +1,151 @@
+package gosms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const truncationMessage = "The quick brown fox jumps over the lazy dog, and then the quick brown fox " +
+	"jumps over the lazy dog again, because one jump over the lazy dog was never enough."
+
+func TestSplitReturnsTooManySegmentsError(t *testing.T) {
+	var TestSplitReturnsTooManySegmentsError = []struct {
+		name                string
+		maxSegments         int
+		expectedMaxSegments int
+	}{
+		{"configured maximum", 2, 2},
+		{"single segment", 1, 1},
+	}
+
+	for _, tt := range TestSplitReturnsTooManySegmentsError {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetMessageBytes(40)
+			splitter.SetMaxSegments(tt.maxSegments)
+
+			smsParts, err := splitter.Split("from", []string{"to"}, truncationMessage)
+			assert.Nil(t, smsParts)
+
+			tooManySegments, ok := err.(*TooManySegmentsError)
+			assert.True(t, ok)
+			assert.Equal(t, 5, tooManySegments.GetSegments())
+			assert.Equal(t, tt.expectedMaxSegments, tooManySegments.GetMaxSegments())
+			assert.Equal(t, "the message needs 5 segments, but at most 2 are allowed", (&TooManySegmentsError{5, 2}).Error())
+		})
+	}
+}
+
+// this test ensures that a message can never be split into more than 255 segments
+func TestSplitEnforcesConcatenationLimit(t *testing.T) {
+	var TestSplitEnforcesConcatenationLimit = []int{0, -1, 300}
+
+	message := strings.Repeat("a ", 256*10)
+	for _, maxSegments := range TestSplitEnforcesConcatenationLimit {
+		splitter := NewSplitter()
+		splitter.SetMessageBytes(16)
+		splitter.SetMaxSegments(maxSegments)
+
+		_, err := splitter.Split("from", []string{"to"}, message)
+
+		tooManySegments, ok := err.(*TooManySegmentsError)
+		assert.True(t, ok)
+		assert.Equal(t, DefaultMaxSegments, tooManySegments.GetMaxSegments())
+	}
+}
+
+func TestSplitTruncatesMessage(t *testing.T) {
+	var TestSplitTruncatesMessage = []struct {
+		name            string
+		maxSegments     int
+		suffix          string
+		message         string
+		expectedContent string
+		expectedEncoder string
+	}{
+		{
+			"truncated at a word boundary",
+			2,
+			DefaultTruncationSuffix,
+			truncationMessage,
+			"The quick brown fox jumps over the lazy dog, and then the quick brown...",
+			EncoderNameGSM,
+		},
+		{
+			"single segment",
+			1,
+			DefaultTruncationSuffix,
+			truncationMessage,
+			"The quick brown fox jumps over the lazy...",
+			EncoderNameGSM,
+		},
+		{
+			"no suffix",
+			1,
+			"",
+			truncationMessage,
+			"The quick brown fox jumps over the lazy dog,",
+			EncoderNameGSM,
+		},
+		{
+			"suffix outside the GSM alphabet",
+			1,
+			"…",
+			truncationMessage,
+			"The quick brown fox…",
+			EncoderNameUTF16,
+		},
+		{
+			"no word boundary",
+			1,
+			DefaultTruncationSuffix,
+			strings.Repeat("a", 100),
+			strings.Repeat("a", 42) + "...",
+			EncoderNameGSM,
+		},
+		{
+			"message which fits",
+			1,
+			DefaultTruncationSuffix,
+			"short message",
+			"short message",
+			EncoderNameGSM,
+		},
+	}
+
+	for _, tt := range TestSplitTruncatesMessage {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := NewSplitter()
+			splitter.SetMessageBytes(40)
+			splitter.SetMaxSegments(tt.maxSegments)
+			splitter.SetTruncate(true)
+			splitter.SetTruncationSuffix(tt.suffix)
+
+			smsParts, err := splitter.Split("from", []string{"to"}, tt.message)
+			assert.NoError(t, err)
+			assert.True(t, len(smsParts) <= tt.maxSegments)
+
+			var content string
+			for _, sms := range smsParts {
+				content += sms.GetContent()
+				assert.Equal(t, tt.expectedEncoder, sms.GetEncoder().GetEncoderName())
+			}
+			assert.Equal(t, tt.expectedContent, content)
+		})
+	}
+}
+
+// this test ensures that a suffix which can never fit is reported
+func TestSplitReturnsErrorForOversizeTruncationSuffix(t *testing.T) {
+	splitter := NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetMaxSegments(1)
+	splitter.SetTruncate(true)
+	splitter.SetTruncationSuffix(strings.Repeat(".", 50))
+
+	_, err := splitter.Split("from", []string{"to"}, truncationMessage)
+
+	assert.Equal(t, ErrNotSplittable, err)
+}
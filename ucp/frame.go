@@ -0,0 +1,176 @@
+// Package ucp encodes SMS parts generated by a gosms Splitter as UCP/EMI
+// operation 51 frames, and decodes the results an SMSC returns for them
+package ucp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	stx byte = 0x02
+	etx byte = 0x03
+
+	separator      string = "/"
+	headerFields   int    = 4
+	headerLength   int    = len("00/00000/O/00/")
+	lengthDigits   int    = 5
+	checksumDigits int    = 2
+
+	// MaxTransactionRef is the highest transaction reference number, after which numbering wraps to 00
+	MaxTransactionRef int = 99
+
+	// MaxFrameLength is the longest frame the LEN field can describe, excluding STX and ETX
+	MaxFrameLength int = 99999
+)
+
+var (
+	// ErrInvalidFrame indicates that a frame is truncated or malformed
+	ErrInvalidFrame = errors.New("the UCP frame is malformed")
+
+	// ErrChecksum indicates that the checksum of a frame does not match its content
+	ErrChecksum = errors.New("the UCP frame checksum does not match")
+
+	// ErrInvalidTransactionRef indicates that a transaction reference number is outside 00 to 99
+	ErrInvalidTransactionRef = errors.New("the transaction reference number must be between 0 and 99")
+
+	// ErrFrameTooLong indicates that a frame is longer than the LEN field can describe
+	ErrFrameTooLong = errors.New("the UCP frame is too long")
+)
+
+// FrameType is the O/R field, which tells operations from results
+type FrameType byte
+
+const (
+	// FrameOperation is an operation sent to the SMSC
+	FrameOperation FrameType = 'O'
+
+	// FrameResult is the result of an operation
+	FrameResult FrameType = 'R'
+)
+
+// Operation is the OT field, which identifies the operation of a frame
+type Operation int
+
+const (
+	// OperationSubmit is operation 51, submit short message
+	OperationSubmit Operation = 51
+)
+
+// Frame is a UCP message, made up of a header, data fields and a checksum
+type Frame struct {
+	TransactionRef int
+	Type           FrameType
+	Operation      Operation
+	Fields         []string
+}
+
+// Marshal encodes the frame between STX and ETX, filling in its LEN and checksum
+func (f *Frame) Marshal() ([]byte, error) {
+	if f.TransactionRef < 0 || f.TransactionRef > MaxTransactionRef {
+		return nil, ErrInvalidTransactionRef
+	}
+
+	data := strings.Join(f.Fields, separator) + separator
+	// the length counts everything between STX and ETX, including itself
+	length := headerLength + len(data) + checksumDigits
+	if length > MaxFrameLength {
+		return nil, ErrFrameTooLong
+	}
+
+	body := fmt.Sprintf("%02d/%05d/%c/%02d/%s", f.TransactionRef, length, f.Type, int(f.Operation), data)
+	frame := append([]byte{stx}, body...)
+	frame = append(frame, checksum(body)...)
+	return append(frame, etx), nil
+}
+
+// Unmarshal decodes a frame from between STX and ETX, checking its LEN and checksum
+func (f *Frame) Unmarshal(data []byte) error {
+	if len(data) < 2 || data[0] != stx || data[len(data)-1] != etx {
+		return ErrInvalidFrame
+	}
+	body := string(data[1 : len(data)-1])
+	if len(body) < checksumDigits {
+		return ErrInvalidFrame
+	}
+
+	fields := strings.Split(body, separator)
+	if len(fields) < headerFields+1 {
+		return ErrInvalidFrame
+	}
+
+	// the checksum covers everything before it
+	checked := body[:len(body)-len(fields[len(fields)-1])]
+	if len(fields[len(fields)-1]) != checksumDigits || !strings.EqualFold(fields[len(fields)-1], checksum(checked)) {
+		return ErrChecksum
+	}
+
+	transactionRef, err := parseDigits(fields[0], 2)
+	if err != nil {
+		return err
+	}
+	length, err := parseDigits(fields[1], lengthDigits)
+	if err != nil || length != len(body) {
+		return ErrInvalidFrame
+	}
+	if len(fields[2]) != 1 || (FrameType(fields[2][0]) != FrameOperation && FrameType(fields[2][0]) != FrameResult) {
+		return ErrInvalidFrame
+	}
+	operation, err := parseDigits(fields[3], 2)
+	if err != nil {
+		return err
+	}
+
+	f.TransactionRef = transactionRef
+	f.Type = FrameType(fields[2][0])
+	f.Operation = Operation(operation)
+	f.Fields = fields[headerFields : len(fields)-1]
+	return nil
+}
+
+// checksum returns the sum of the bytes of body, modulo 256, as two hexadecimal digits
+func checksum(body string) string {
+	var sum byte
+	for idx := 0; idx < len(body); idx++ {
+		sum += body[idx]
+	}
+	return fmt.Sprintf("%02X", sum)
+}
+
+// parseDigits parses a fixed width decimal field
+func parseDigits(field string, width int) (int, error) {
+	if len(field) != width {
+		return 0, ErrInvalidFrame
+	}
+	for _, char := range field {
+		if char < '0' || char > '9' {
+			return 0, ErrInvalidFrame
+		}
+	}
+	value, _ := strconv.Atoi(field)
+	return value, nil
+}
+
+// TransactionCounter numbers the operations sent over a connection, wrapping from 99 back to 00
+type TransactionCounter struct {
+	mutex sync.Mutex
+	next  int
+}
+
+// NewTransactionCounter creates a new TransactionCounter starting from 00
+func NewTransactionCounter() *TransactionCounter {
+	return &TransactionCounter{next: 0}
+}
+
+// Next returns the next transaction reference number
+func (c *TransactionCounter) Next() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	transactionRef := c.next
+	c.next = (c.next + 1) % (MaxTransactionRef + 1)
+	return transactionRef
+}
@@ -0,0 +1,93 @@
+package ucp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	var TestFrameRoundTrip = []struct {
+		name     string
+		frame    Frame
+		expected string
+	}{
+		{"acknowledgement", Frame{TransactionRef: 1, Type: FrameResult, Operation: OperationSubmit, Fields: []string{"A", "", "31612345678:270101120000"}},
+			"\x0201/00044/R/51/A//31612345678:270101120000/61\x03"},
+		{"negative acknowledgement", Frame{TransactionRef: 2, Type: FrameResult, Operation: OperationSubmit, Fields: []string{"N", "06", " AdC invalid"}},
+			"\x0202/00034/R/51/N/06/ AdC invalid/1D\x03"},
+	}
+
+	for _, tt := range TestFrameRoundTrip {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.frame.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+
+			var decoded Frame
+			assert.NoError(t, decoded.Unmarshal(data))
+			assert.Equal(t, tt.frame, decoded)
+		})
+	}
+}
+
+func TestFrameMarshalReturnsError(t *testing.T) {
+	var TestFrameMarshalReturnsError = []struct {
+		name     string
+		frame    Frame
+		expected error
+	}{
+		{"negative transaction reference", Frame{TransactionRef: -1, Type: FrameOperation, Operation: OperationSubmit}, ErrInvalidTransactionRef},
+		{"transaction reference above 99", Frame{TransactionRef: 100, Type: FrameOperation, Operation: OperationSubmit}, ErrInvalidTransactionRef},
+		{"too long", Frame{Type: FrameOperation, Operation: OperationSubmit, Fields: []string{string(make([]byte, MaxFrameLength))}}, ErrFrameTooLong},
+	}
+
+	for _, tt := range TestFrameMarshalReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.frame.Marshal()
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestFrameUnmarshalReturnsError(t *testing.T) {
+	var TestFrameUnmarshalReturnsError = []struct {
+		name     string
+		data     string
+		expected error
+	}{
+		{"missing STX", "01/00044/R/51/A//31612345678:270101120000/61\x03", ErrInvalidFrame},
+		{"missing ETX", "\x0201/00044/R/51/A//31612345678:270101120000/61", ErrInvalidFrame},
+		{"empty", "\x02\x03", ErrInvalidFrame},
+		{"wrong checksum", "\x0201/00044/R/51/A//31612345678:270101120000/62\x03", ErrChecksum},
+		{"corrupted content", "\x0201/00044/R/51/A//31612345679:270101120000/61\x03", ErrChecksum},
+		{"wrong length", "\x0201/00045/R/51/A//31612345678:270101120000/62\x03", ErrInvalidFrame},
+		{"non-numeric transaction reference", "\x020A/00044/R/51/A//31612345678:270101120000/71\x03", ErrInvalidFrame},
+		{"unknown type", "\x0201/00044/X/51/A//31612345678:270101120000/67\x03", ErrInvalidFrame},
+		{"too few fields", "\x0201/00015/R/44\x03", ErrInvalidFrame},
+	}
+
+	for _, tt := range TestFrameUnmarshalReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			var frame Frame
+			assert.Equal(t, tt.expected, frame.Unmarshal([]byte(tt.data)))
+		})
+	}
+}
+
+func TestFrameUnmarshalAcceptsLowerCaseChecksum(t *testing.T) {
+	var frame Frame
+	assert.NoError(t, frame.Unmarshal([]byte("\x0299/00098/O/51/31612345678/12345/////////////////4/96/041F04400438043204350442//////////020108///cf\x03")))
+	assert.Equal(t, 99, frame.TransactionRef)
+	assert.Equal(t, 33, len(frame.Fields))
+}
+
+func TestTransactionCounterWraps(t *testing.T) {
+	counter := NewTransactionCounter()
+
+	for idx := 0; idx <= MaxTransactionRef; idx++ {
+		assert.Equal(t, idx, counter.Next())
+	}
+	assert.Equal(t, 0, counter.Next())
+	assert.Equal(t, 1, counter.Next())
+}
@@ -0,0 +1,144 @@
+package ucp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	ack  string = "A"
+	nack string = "N"
+
+	resultFields int = 3
+)
+
+// ErrUnexpectedFrame indicates that a frame is not the result of the expected operation
+var ErrUnexpectedFrame = errors.New("the UCP frame is not the expected result")
+
+// ErrorCode is the EC field of a negative result
+type ErrorCode int
+
+const (
+	// ErrorChecksum means that the SMSC found a checksum error
+	ErrorChecksum ErrorCode = 1
+
+	// ErrorSyntax means that the SMSC found a syntax error
+	ErrorSyntax ErrorCode = 2
+
+	// ErrorOperationNotSupported means that the SMSC does not support the operation
+	ErrorOperationNotSupported ErrorCode = 3
+
+	// ErrorOperationNotAllowed means that the operation is not allowed
+	ErrorOperationNotAllowed ErrorCode = 4
+
+	// ErrorCallBarring means that call barring is active
+	ErrorCallBarring ErrorCode = 5
+
+	// ErrorInvalidAdC means that the recipient address is invalid
+	ErrorInvalidAdC ErrorCode = 6
+
+	// ErrorAuthentication means that authentication failed
+	ErrorAuthentication ErrorCode = 7
+
+	// ErrorLegitimisationCode means that the legitimisation code for all calls failed
+	ErrorLegitimisationCode ErrorCode = 8
+
+	// ErrorMessageTypeNotSupported means that the SMSC does not support the message type
+	ErrorMessageTypeNotSupported ErrorCode = 23
+
+	// ErrorMessageTooLong means that the message is too long
+	ErrorMessageTooLong ErrorCode = 24
+
+	// ErrorMessageTypeNotValid means that the message type is not valid for the pager type
+	ErrorMessageTypeNotValid ErrorCode = 26
+)
+
+// errorCodeNames are the descriptions of the known error codes
+var errorCodeNames = map[ErrorCode]string{
+	ErrorChecksum:                "checksum error",
+	ErrorSyntax:                  "syntax error",
+	ErrorOperationNotSupported:   "operation not supported by system",
+	ErrorOperationNotAllowed:     "operation not allowed",
+	ErrorCallBarring:             "call barring active",
+	ErrorInvalidAdC:              "AdC invalid",
+	ErrorAuthentication:          "authentication failure",
+	ErrorLegitimisationCode:      "legitimisation code for all calls failure",
+	ErrorMessageTypeNotSupported: "message type not supported by system",
+	ErrorMessageTooLong:          "message too long",
+	ErrorMessageTypeNotValid:     "message type not valid for the pager type",
+}
+
+// String returns the error code and its description, if it is known
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return fmt.Sprintf("%02d %s", int(c), name)
+	}
+	return fmt.Sprintf("%02d", int(c))
+}
+
+// Result is the result of an operation 51, acknowledging or rejecting the submission
+type Result struct {
+	transactionRef int
+	ack            bool
+	validityPeriod string
+	errorCode      ErrorCode
+	systemMessage  string
+}
+
+// GetTransactionRef returns the transaction reference number of the operation the result is for
+func (r *Result) GetTransactionRef() int {
+	return r.transactionRef
+}
+
+// IsAck returns true if the SMSC accepted the submission
+func (r *Result) IsAck() bool {
+	return r.ack
+}
+
+// GetValidityPeriod returns the validity period the SMSC applied, if it modified the requested one
+func (r *Result) GetValidityPeriod() string {
+	return r.validityPeriod
+}
+
+// GetErrorCode returns the reason the SMSC rejected the submission
+func (r *Result) GetErrorCode() ErrorCode {
+	return r.errorCode
+}
+
+// GetSystemMessage returns the SM field. For an acknowledgement it is usually
+// the recipient and the SMSC timestamp separated by a colon, which identify
+// the message in later notifications.
+func (r *Result) GetSystemMessage() string {
+	return r.systemMessage
+}
+
+// UnmarshalResult decodes the result of an operation 51
+func UnmarshalResult(data []byte) (Result, error) {
+	var frame Frame
+	if err := frame.Unmarshal(data); err != nil {
+		return Result{}, err
+	}
+	if frame.Type != FrameResult || frame.Operation != OperationSubmit {
+		return Result{}, ErrUnexpectedFrame
+	}
+	if len(frame.Fields) != resultFields {
+		return Result{}, ErrInvalidFrame
+	}
+
+	result := Result{transactionRef: frame.TransactionRef, systemMessage: frame.Fields[2]}
+	switch frame.Fields[0] {
+	case ack:
+		result.ack = true
+		result.validityPeriod = frame.Fields[1]
+	case nack:
+		errorCode, err := strconv.Atoi(frame.Fields[1])
+		if err != nil {
+			return Result{}, ErrInvalidFrame
+		}
+		result.errorCode = ErrorCode(errorCode)
+	default:
+		return Result{}, ErrInvalidFrame
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package ucp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalResult(t *testing.T) {
+	var TestUnmarshalResult = []struct {
+		name                   string
+		data                   string
+		expectedTransactionRef int
+		expectedAck            bool
+		expectedValidity       string
+		expectedErrorCode      ErrorCode
+		expectedSystemMessage  string
+	}{
+		{"acknowledgement", "\x0201/00044/R/51/A//31612345678:270101120000/61\x03",
+			1, true, "", 0, "31612345678:270101120000"},
+		{"acknowledgement with modified validity period", "\x0203/00054/R/51/A/0201271200/31612345678:270101120000/53\x03",
+			3, true, "0201271200", 0, "31612345678:270101120000"},
+		{"negative acknowledgement", "\x0202/00034/R/51/N/06/ AdC invalid/1D\x03",
+			2, false, "", ErrorInvalidAdC, " AdC invalid"},
+	}
+
+	for _, tt := range TestUnmarshalResult {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := UnmarshalResult([]byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTransactionRef, result.GetTransactionRef())
+			assert.Equal(t, tt.expectedAck, result.IsAck())
+			assert.Equal(t, tt.expectedValidity, result.GetValidityPeriod())
+			assert.Equal(t, tt.expectedErrorCode, result.GetErrorCode())
+			assert.Equal(t, tt.expectedSystemMessage, result.GetSystemMessage())
+		})
+	}
+}
+
+func TestUnmarshalResultReturnsError(t *testing.T) {
+	var TestUnmarshalResultReturnsError = []struct {
+		name     string
+		frame    Frame
+		expected error
+	}{
+		{"operation", Frame{Type: FrameOperation, Operation: OperationSubmit, Fields: []string{"A", "", ""}}, ErrUnexpectedFrame},
+		{"result of another operation", Frame{Type: FrameResult, Operation: 31, Fields: []string{"A", ""}}, ErrUnexpectedFrame},
+		{"missing field", Frame{Type: FrameResult, Operation: OperationSubmit, Fields: []string{"A", ""}}, ErrInvalidFrame},
+		{"unknown acknowledgement", Frame{Type: FrameResult, Operation: OperationSubmit, Fields: []string{"X", "", ""}}, ErrInvalidFrame},
+		{"non-numeric error code", Frame{Type: FrameResult, Operation: OperationSubmit, Fields: []string{"N", "XX", ""}}, ErrInvalidFrame},
+	}
+
+	for _, tt := range TestUnmarshalResultReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.frame.Marshal()
+			assert.NoError(t, err)
+
+			_, err = UnmarshalResult(data)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+
+	_, err := UnmarshalResult([]byte("\x0201/00044/R/51/A//31612345678:270101120000/00\x03"))
+	assert.Equal(t, ErrChecksum, err)
+}
+
+func TestErrorCodeString(t *testing.T) {
+	assert.Equal(t, "06 AdC invalid", ErrorInvalidAdC.String())
+	assert.Equal(t, "24 message too long", ErrorMessageTooLong.String())
+	assert.Equal(t, "99", ErrorCode(99).String())
+}
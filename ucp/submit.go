@@ -0,0 +1,256 @@
+package ucp
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/textnow/gosms"
+)
+
+// ErrUnsupportedConcatenation indicates that an SMS part is numbered alongside
+// its user data, which UCP has no field for
+var ErrUnsupportedConcatenation = errors.New("UCP can only number SMS parts with a UDH")
+
+// MessageType is the MT field of an operation 51, which gives the encoding of its Msg field
+type MessageType int
+
+const (
+	// MessageTypeNumeric is a numeric message
+	MessageTypeNumeric MessageType = 2
+
+	// MessageTypeAlphanumeric is text in IRA (ITU-T T.50) characters, one character per octet
+	MessageTypeAlphanumeric MessageType = 3
+
+	// MessageTypeTransparent is user data coded as given by the XSer DCS
+	MessageTypeTransparent MessageType = 4
+)
+
+// extra service types carried in the XSer field
+const (
+	// XSerUDH carries the UDH of the message, starting with the UDHL
+	XSerUDH byte = 0x01
+
+	// XSerDCS carries the TP-DCS of the message
+	XSerDCS byte = 0x02
+)
+
+// originator types of address given in the OTOA field
+const (
+	// OriginatorTypeInternational is an international number
+	OriginatorTypeInternational string = "1139"
+
+	// OriginatorTypeAlphanumeric is an alphanumeric address coded as packed GSM septets
+	OriginatorTypeAlphanumeric string = "5039"
+)
+
+// notification types which can be combined in the NT field
+const (
+	// NotificationDelivered requests a notification once the message is delivered
+	NotificationDelivered int = 1
+
+	// NotificationNonDelivered requests a notification if the message cannot be delivered
+	NotificationNonDelivered int = 2
+
+	// NotificationBuffered requests a notification if the message is buffered for later delivery
+	NotificationBuffered int = 4
+)
+
+// positions of the operation 51 fields
+const (
+	fieldAdC = iota
+	fieldOAdC
+	fieldAC
+	fieldNRq
+	fieldNAdC
+	fieldNT
+	fieldNPID
+	fieldLRq
+	fieldLRAd
+	fieldLPID
+	fieldDD
+	fieldDDT
+	fieldVP
+	fieldRPID
+	fieldSCTS
+	fieldDst
+	fieldRsn
+	fieldDSCTS
+	fieldMT
+	fieldNB
+	fieldMsg
+	fieldMMS
+	fieldPR
+	fieldDCs
+	fieldMCLs
+	fieldRPI
+	fieldCPg
+	fieldRPLy
+	fieldOTOA
+	fieldHPLMN
+	fieldXSer
+	fieldRES4
+	fieldRES5
+	submitFields
+)
+
+// XSer is an extra service of an operation 51
+type XSer struct {
+	Type byte
+	Data []byte
+}
+
+// Submit is an operation 51, submit short message. Fields which are not
+// modelled are left empty.
+type Submit struct {
+	TransactionRef      int
+	Recipient           string
+	Originator          string
+	OriginatorType      string
+	NotificationRequest bool
+	NotificationAddress string
+	NotificationType    int
+	ValidityPeriod      string
+	MessageType         MessageType
+	Bits                int
+	Message             []byte
+	MessageClass        gosms.MessageClass
+	XSer                []XSer
+}
+
+// NewSubmit converts an SMS generated by a gosms Splitter into a Submit. GSM
+// content made up of IRA characters is sent as alphanumeric text with any
+// message class in the MCLs field. Other GSM content and other encodings are
+// sent as transparent data with their TP-DCS in the XSer field. The UDH is
+// also carried in the XSer field. National numbers are
+// interpreted using defaultRegion, an ISO 3166-1 alpha-2 code such as "US", and
+// the recipient cannot be alphanumeric.
+func NewSubmit(sms gosms.SMS, defaultRegion string) (*Submit, error) {
+	if _, ok := sms.GetSegment(); ok {
+		return nil, ErrUnsupportedConcatenation
+	}
+
+	destination, err := gosms.ParseAddress(sms.GetTo(), defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+	if destination.GetType() == gosms.AddressTypeAlphanumeric {
+		return nil, gosms.ErrAlphanumericRecipient
+	}
+	source, err := gosms.ParseAddress(sms.GetFrom(), defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+	originator, originatorType, err := encodeOriginator(source)
+	if err != nil {
+		return nil, err
+	}
+
+	submit := &Submit{
+		Recipient:      destination.GetDigits(),
+		Originator:     originator,
+		OriginatorType: originatorType,
+	}
+	if udh := sms.GetUDH(); udh != "" {
+		submit.XSer = append(submit.XSer, XSer{Type: XSerUDH, Data: []byte(udh)})
+	}
+
+	_, isGSM := sms.GetEncoder().(*gosms.GSM)
+	if isGSM && isIRA(sms.GetContent()) {
+		submit.MessageType = MessageTypeAlphanumeric
+		submit.Message = []byte(sms.GetContent())
+		submit.MessageClass = sms.GetMessageClass()
+		return submit, nil
+	}
+
+	dcs, err := sms.GetDataCoding()
+	if err != nil {
+		return nil, err
+	}
+	dataCoding, err := dcs.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	submit.MessageType = MessageTypeTransparent
+	submit.Message = sms.GetBytes()
+	submit.Bits = len(submit.Message) * 8
+	if isGSM {
+		// packed septets, and the fill bits which follow the UDH
+		submit.Bits = sms.GetUserDataLength()*7 - len(sms.GetUDH())*8
+	}
+	submit.XSer = append(submit.XSer, XSer{Type: XSerDCS, Data: []byte{dataCoding}})
+	return submit, nil
+}
+
+// isIRA returns true if every character of str is in the international
+// reference version of ITU-T T.50, which is US-ASCII
+func isIRA(str string) bool {
+	for _, char := range str {
+		if char >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeOriginator returns the OAdC and OTOA fields for source. Alphanumeric
+// addresses are coded as in a TP-OA, with the number of useful semi-octets
+// followed by packed GSM septets.
+func encodeOriginator(source gosms.Address) (string, string, error) {
+	switch source.GetType() {
+	case gosms.AddressTypeAlphanumeric:
+		field, err := source.MarshalTPAddress()
+		if err != nil {
+			return "", "", err
+		}
+		// drop the type of address, which the OTOA replaces
+		return encodeHex(append([]byte{field[0]}, field[2:]...)), OriginatorTypeAlphanumeric, nil
+	case gosms.AddressTypeInternational:
+		return source.GetDigits(), OriginatorTypeInternational, nil
+	}
+	return source.GetDigits(), "", nil
+}
+
+// Marshal encodes the Submit as an operation 51 frame
+func (s *Submit) Marshal() ([]byte, error) {
+	fields := make([]string, submitFields)
+
+	fields[fieldAdC] = s.Recipient
+	fields[fieldOAdC] = s.Originator
+	if s.NotificationRequest {
+		fields[fieldNRq] = "1"
+		fields[fieldNAdC] = s.NotificationAddress
+		fields[fieldNT] = strconv.Itoa(s.NotificationType)
+	}
+	fields[fieldVP] = s.ValidityPeriod
+	fields[fieldMT] = strconv.Itoa(int(s.MessageType))
+	if s.MessageType == MessageTypeTransparent {
+		fields[fieldNB] = strconv.Itoa(s.Bits)
+	}
+	fields[fieldMsg] = encodeHex(s.Message)
+	if s.MessageClass != gosms.MessageClassNone {
+		fields[fieldMCLs] = strconv.Itoa(int(s.MessageClass - gosms.MessageClass0))
+	}
+	fields[fieldOTOA] = s.OriginatorType
+	fields[fieldXSer] = encodeXSer(s.XSer)
+
+	frame := Frame{TransactionRef: s.TransactionRef, Type: FrameOperation, Operation: OperationSubmit, Fields: fields}
+	return frame.Marshal()
+}
+
+// encodeXSer encodes extra services as their type, length and data in hexadecimal
+func encodeXSer(xser []XSer) string {
+	var data []byte
+	for _, service := range xser {
+		data = append(data, service.Type, byte(len(service.Data)))
+		data = append(data, service.Data...)
+	}
+	return encodeHex(data)
+}
+
+// encodeHex encodes data as upper case hexadecimal
+func encodeHex(data []byte) string {
+	return strings.ToUpper(hex.EncodeToString(data))
+}
@@ -0,0 +1,176 @@
+package ucp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/textnow/gosms"
+)
+
+// splitMessage splits message from from to to with encoder
+func splitMessage(t *testing.T, encoder gosms.Encoder, from string, to string, message string) []gosms.SMS {
+	splitter := gosms.NewSplitter()
+	splitter.SetEncoder(encoder)
+	splitter.SetMessageBytes(40)
+
+	smsParts, err := splitter.Split(from, []string{to}, message)
+	assert.NoError(t, err)
+	return smsParts
+}
+
+func TestNewSubmitGoldenFrames(t *testing.T) {
+	var TestNewSubmitGoldenFrames = []struct {
+		name           string
+		encoder        gosms.Encoder
+		from           string
+		message        string
+		transactionRef int
+		expected       string
+	}{
+		{"GSM from alphanumeric", gosms.NewGSM(), "TextNow", "Hello world", 1,
+			"\x0201/00103/O/51/31612345678/0DD4329EEE7CDF01/////////////////3//48656C6C6F20776F726C64////////5039/////B2\x03"},
+		{"UCS-2 from short code", gosms.NewUTF16(), "12345", "Привет", 99,
+			"\x0299/00098/O/51/31612345678/12345/////////////////4/96/041F04400438043204350442//////////020108///CF\x03"},
+		{"GSM symbols as IRA", gosms.NewGSM(), "12345", "@$_ok", 2,
+			"\x0202/00076/O/51/31612345678/12345/////////////////3//40245F6F6B/////////////88\x03"},
+		{"GSM outside IRA as transparent data", gosms.NewGSM(), "12345", "£5 Δ", 2,
+			"\x0202/00082/O/51/31612345678/12345/////////////////4/28/811A0802//////////020100///7F\x03"},
+	}
+
+	for _, tt := range TestNewSubmitGoldenFrames {
+		t.Run(tt.name, func(t *testing.T) {
+			smsParts := splitMessage(t, tt.encoder, tt.from, "+31612345678", tt.message)
+			assert.Equal(t, 1, len(smsParts))
+
+			submit, err := NewSubmit(smsParts[0], "")
+			assert.NoError(t, err)
+			submit.TransactionRef = tt.transactionRef
+			data, err := submit.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestSubmitMarshal(t *testing.T) {
+	submit := Submit{
+		TransactionRef:      42,
+		Recipient:           "31612345678",
+		Originator:          "447700900123",
+		OriginatorType:      OriginatorTypeInternational,
+		NotificationRequest: true,
+		NotificationType:    NotificationDelivered | NotificationNonDelivered,
+		ValidityPeriod:      "0101271200",
+		MessageType:         MessageTypeAlphanumeric,
+		Message:             []byte("Part one"),
+		MessageClass:        gosms.MessageClass0,
+		XSer:                []XSer{{Type: XSerUDH, Data: []byte{0x05, 0x00, 0x03, 0x2A, 0x02, 0x01}}},
+	}
+
+	data, err := submit.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, "\x0242/00122/O/51/31612345678/447700900123//1//3///////0101271200//////3//50617274206F6E65////0////1139//01060500032A0201///8A\x03", string(data))
+}
+
+func TestNewSubmitCarriesUDHInXSer(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), "+447700900123", "+31612345678", "This message is long enough that it has to be split into several parts.")
+	assert.True(t, len(smsParts) > 1)
+
+	for _, sms := range smsParts {
+		submit, err := NewSubmit(sms, "")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "447700900123", submit.Originator)
+		assert.Equal(t, OriginatorTypeInternational, submit.OriginatorType)
+		assert.Equal(t, MessageTypeAlphanumeric, submit.MessageType)
+		assert.Equal(t, []byte(sms.GetContent()), submit.Message)
+		assert.Equal(t, []XSer{{Type: XSerUDH, Data: []byte(sms.GetUDH())}}, submit.XSer)
+
+		data, err := submit.Marshal()
+		assert.NoError(t, err)
+		var frame Frame
+		assert.NoError(t, frame.Unmarshal(data))
+		assert.Equal(t, encodeHex(append([]byte{XSerUDH, byte(len(sms.GetUDH()))}, sms.GetUDH()...)), frame.Fields[fieldXSer])
+	}
+}
+
+func TestNewSubmitCountsFillBitsOfTransparentGSM(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), "12345", "+31612345678", strings.Repeat("Δ", 50))
+	assert.Equal(t, 2, len(smsParts))
+
+	submit, err := NewSubmit(smsParts[0], "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, MessageTypeTransparent, submit.MessageType)
+	// 38 septets follow a 6 octet UDH and one fill bit
+	assert.Equal(t, 38*7+1, submit.Bits)
+	assert.Equal(t, smsParts[0].GetBytes(), submit.Message)
+}
+
+func TestNewSubmitSetsMessageClass(t *testing.T) {
+	var TestNewSubmitSetsMessageClass = []struct {
+		name         string
+		encoder      gosms.Encoder
+		expectedMCLs string
+		expectedXSer string
+	}{
+		{"GSM", gosms.NewGSM(), "0", ""},
+		{"UCS-2", gosms.NewUTF16(), "", "020118"},
+	}
+
+	for _, tt := range TestNewSubmitSetsMessageClass {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := gosms.NewSplitter()
+			splitter.SetEncoder(tt.encoder)
+			splitter.SetMessageClass(gosms.MessageClass0)
+			smsParts, err := splitter.Split("12345", []string{"+31612345678"}, "Flash")
+			assert.NoError(t, err)
+
+			submit, err := NewSubmit(smsParts[0], "")
+			assert.NoError(t, err)
+			data, err := submit.Marshal()
+			assert.NoError(t, err)
+
+			var frame Frame
+			assert.NoError(t, frame.Unmarshal(data))
+			assert.Equal(t, tt.expectedMCLs, frame.Fields[fieldMCLs])
+			assert.Equal(t, tt.expectedXSer, frame.Fields[fieldXSer])
+		})
+	}
+}
+
+func TestNewSubmitNationalNumbers(t *testing.T) {
+	smsParts := splitMessage(t, gosms.NewGSM(), "020 1234567", "06 12345678", "hello")
+
+	submit, err := NewSubmit(smsParts[0], "NL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "31612345678", submit.Recipient)
+	assert.Equal(t, "31201234567", submit.Originator)
+	assert.Equal(t, OriginatorTypeInternational, submit.OriginatorType)
+}
+
+func TestNewSubmitReturnsError(t *testing.T) {
+	splitter := gosms.NewSplitter()
+	splitter.SetMessageBytes(40)
+	splitter.SetConcatenation(gosms.ConcatenationSAR)
+	sarParts, err := splitter.Split("12345", []string{"+31612345678"}, "This message is long enough that it has to be split into several parts.")
+	assert.NoError(t, err)
+
+	var TestNewSubmitReturnsError = []struct {
+		name     string
+		sms      gosms.SMS
+		expected error
+	}{
+		{"alphanumeric recipient", splitMessage(t, gosms.NewGSM(), "12345", "TextNow", "hello")[0], gosms.ErrAlphanumericRecipient},
+		{"SAR part", sarParts[0], ErrUnsupportedConcatenation},
+	}
+
+	for _, tt := range TestNewSubmitReturnsError {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSubmit(tt.sms, "")
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
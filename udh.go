@@ -0,0 +1,276 @@
+package gosms
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidUDH indicates that a user data header or one of its information elements is malformed
+var ErrInvalidUDH = errors.New("the user data header is malformed")
+
+const (
+	// IEIConcatenated8Bit identifies a concatenated message with an 8-bit reference number
+	IEIConcatenated8Bit byte = 0x00
+
+	// IEISpecialSMSIndication identifies a special SMS message indication
+	IEISpecialSMSIndication byte = 0x01
+
+	// IEIPortAddressing8Bit identifies application port addressing with 8-bit ports
+	IEIPortAddressing8Bit byte = 0x04
+
+	// IEIPortAddressing16Bit identifies application port addressing with 16-bit ports
+	IEIPortAddressing16Bit byte = 0x05
+
+	// IEIConcatenated16Bit identifies a concatenated message with a 16-bit reference number
+	IEIConcatenated16Bit byte = 0x08
+
+	// IEINationalLanguageSingleShift identifies the national language single shift table
+	IEINationalLanguageSingleShift byte = 0x24
+
+	// IEINationalLanguageLockingShift identifies the national language locking shift table
+	IEINationalLanguageLockingShift byte = 0x25
+
+	maxUDHLength             int  = 0xFF
+	specialSMSStoreMask      byte = 0x80
+	specialSMSIndicationMask byte = 0x7F
+)
+
+// informationElementLengths are the data lengths of the modelled information elements
+var informationElementLengths = map[byte]int{
+	IEIConcatenated8Bit:             3,
+	IEISpecialSMSIndication:         2,
+	IEIPortAddressing8Bit:           2,
+	IEIPortAddressing16Bit:          4,
+	IEIConcatenated16Bit:            4,
+	IEINationalLanguageSingleShift:  1,
+	IEINationalLanguageLockingShift: 1,
+}
+
+// InformationElement is a single element of a user data header
+type InformationElement interface {
+	GetIdentifier() byte
+	GetData() []byte
+}
+
+// Concatenated8Bit identifies a part of a concatenated message with an 8-bit reference number
+type Concatenated8Bit struct {
+	Reference byte
+	Total     byte
+	Sequence  byte
+}
+
+// GetIdentifier returns the information element identifier
+func (e Concatenated8Bit) GetIdentifier() byte {
+	return IEIConcatenated8Bit
+}
+
+// GetData returns the information element data
+func (e Concatenated8Bit) GetData() []byte {
+	return []byte{e.Reference, e.Total, e.Sequence}
+}
+
+// Concatenated16Bit identifies a part of a concatenated message with a 16-bit reference number
+type Concatenated16Bit struct {
+	Reference uint16
+	Total     byte
+	Sequence  byte
+}
+
+// GetIdentifier returns the information element identifier
+func (e Concatenated16Bit) GetIdentifier() byte {
+	return IEIConcatenated16Bit
+}
+
+// GetData returns the information element data
+func (e Concatenated16Bit) GetData() []byte {
+	return []byte{byte(e.Reference >> 8), byte(e.Reference), e.Total, e.Sequence}
+}
+
+// PortAddressing8Bit addresses a message to an 8-bit application port
+type PortAddressing8Bit struct {
+	Destination byte
+	Origin      byte
+}
+
+// GetIdentifier returns the information element identifier
+func (e PortAddressing8Bit) GetIdentifier() byte {
+	return IEIPortAddressing8Bit
+}
+
+// GetData returns the information element data
+func (e PortAddressing8Bit) GetData() []byte {
+	return []byte{e.Destination, e.Origin}
+}
+
+// PortAddressing16Bit addresses a message to a 16-bit application port
+type PortAddressing16Bit struct {
+	Destination uint16
+	Origin      uint16
+}
+
+// GetIdentifier returns the information element identifier
+func (e PortAddressing16Bit) GetIdentifier() byte {
+	return IEIPortAddressing16Bit
+}
+
+// GetData returns the information element data
+func (e PortAddressing16Bit) GetData() []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint16(data, e.Destination)
+	binary.BigEndian.PutUint16(data[2:], e.Origin)
+	return data
+}
+
+// NationalLanguageSingleShift identifies the national language single shift table of a message
+type NationalLanguageSingleShift struct {
+	Language NationalLanguage
+}
+
+// GetIdentifier returns the information element identifier
+func (e NationalLanguageSingleShift) GetIdentifier() byte {
+	return IEINationalLanguageSingleShift
+}
+
+// GetData returns the information element data
+func (e NationalLanguageSingleShift) GetData() []byte {
+	return []byte{byte(e.Language)}
+}
+
+// NationalLanguageLockingShift identifies the national language locking shift table of a message
+type NationalLanguageLockingShift struct {
+	Language NationalLanguage
+}
+
+// GetIdentifier returns the information element identifier
+func (e NationalLanguageLockingShift) GetIdentifier() byte {
+	return IEINationalLanguageLockingShift
+}
+
+// GetData returns the information element data
+func (e NationalLanguageLockingShift) GetData() []byte {
+	return []byte{byte(e.Language)}
+}
+
+// SpecialSMSIndication indicates waiting voicemail, fax, email or other messages
+type SpecialSMSIndication struct {
+	Store          bool
+	IndicationType byte
+	Count          byte
+}
+
+// GetIdentifier returns the information element identifier
+func (e SpecialSMSIndication) GetIdentifier() byte {
+	return IEISpecialSMSIndication
+}
+
+// GetData returns the information element data
+func (e SpecialSMSIndication) GetData() []byte {
+	indication := e.IndicationType & specialSMSIndicationMask
+	if e.Store {
+		indication |= specialSMSStoreMask
+	}
+	return []byte{indication, e.Count}
+}
+
+// UnknownInformationElement passes through information elements that are not otherwise modelled
+type UnknownInformationElement struct {
+	Identifier byte
+	Data       []byte
+}
+
+// GetIdentifier returns the information element identifier
+func (e UnknownInformationElement) GetIdentifier() byte {
+	return e.Identifier
+}
+
+// GetData returns the information element data
+func (e UnknownInformationElement) GetData() []byte {
+	return e.Data
+}
+
+// UDH is a user data header made up of information elements
+type UDH struct {
+	InformationElements []InformationElement
+}
+
+// Marshal returns the UDH as bytes, starting with the user data header length
+func (u *UDH) Marshal() ([]byte, error) {
+	udh := []byte{0}
+
+	for _, infoElement := range u.InformationElements {
+		data := infoElement.GetData()
+		if len(data) > maxUDHLength {
+			return nil, ErrInvalidUDH
+		}
+		udh = append(udh, infoElement.GetIdentifier(), byte(len(data)))
+		udh = append(udh, data...)
+	}
+
+	if len(udh)-1 > maxUDHLength {
+		return nil, ErrInvalidUDH
+	}
+	udh[0] = byte(len(udh) - 1)
+	return udh, nil
+}
+
+// Unmarshal parses a UDH from bytes starting with the user data header length.
+// Bytes following the UDH, such as the message content, are ignored.
+func (u *UDH) Unmarshal(data []byte) error {
+	var infoElements []InformationElement
+
+	if len(data) == 0 || int(data[0]) > len(data)-1 {
+		return ErrInvalidUDH
+	}
+	udh := data[1 : int(data[0])+1]
+
+	for idx := 0; idx < len(udh); {
+		// each element has an identifier, a length and its data
+		if idx+2 > len(udh) || idx+2+int(udh[idx+1]) > len(udh) {
+			return ErrInvalidUDH
+		}
+		identifier := udh[idx]
+		elementData := udh[idx+2 : idx+2+int(udh[idx+1])]
+		idx += 2 + len(elementData)
+
+		infoElement, err := unmarshalInformationElement(identifier, elementData)
+		if err != nil {
+			return err
+		}
+		infoElements = append(infoElements, infoElement)
+	}
+
+	u.InformationElements = infoElements
+	return nil
+}
+
+// unmarshalInformationElement returns the typed information element for identifier
+func unmarshalInformationElement(identifier byte, data []byte) (InformationElement, error) {
+	if expectedLength, ok := informationElementLengths[identifier]; ok && expectedLength != len(data) {
+		return nil, ErrInvalidUDH
+	}
+
+	switch identifier {
+	case IEIConcatenated8Bit:
+		return Concatenated8Bit{Reference: data[0], Total: data[1], Sequence: data[2]}, nil
+	case IEISpecialSMSIndication:
+		return SpecialSMSIndication{
+			Store:          data[0]&specialSMSStoreMask != 0,
+			IndicationType: data[0] & specialSMSIndicationMask,
+			Count:          data[1],
+		}, nil
+	case IEIPortAddressing8Bit:
+		return PortAddressing8Bit{Destination: data[0], Origin: data[1]}, nil
+	case IEIPortAddressing16Bit:
+		return PortAddressing16Bit{
+			Destination: binary.BigEndian.Uint16(data),
+			Origin:      binary.BigEndian.Uint16(data[2:]),
+		}, nil
+	case IEIConcatenated16Bit:
+		return Concatenated16Bit{Reference: binary.BigEndian.Uint16(data), Total: data[2], Sequence: data[3]}, nil
+	case IEINationalLanguageSingleShift:
+		return NationalLanguageSingleShift{Language: NationalLanguage(data[0])}, nil
+	case IEINationalLanguageLockingShift:
+		return NationalLanguageLockingShift{Language: NationalLanguage(data[0])}, nil
+	}
+	return UnknownInformationElement{Identifier: identifier, Data: append([]byte(nil), data...)}, nil
+}
@@ -0,0 +1,163 @@
+package gosms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUDHMarshal(t *testing.T) {
+	var TestUDHMarshal = []struct {
+		name     string
+		udh      UDH
+		expected []byte
+	}{
+		{
+			"empty UDH",
+			UDH{},
+			[]byte{0x00},
+		},
+		{
+			"8-bit concatenation",
+			UDH{InformationElements: []InformationElement{Concatenated8Bit{Reference: 0xAB, Total: 3, Sequence: 2}}},
+			[]byte{0x05, 0x00, 0x03, 0xAB, 0x03, 0x02},
+		},
+		{
+			"16-bit concatenation",
+			UDH{InformationElements: []InformationElement{Concatenated16Bit{Reference: 0xABCD, Total: 3, Sequence: 1}}},
+			[]byte{0x06, 0x08, 0x04, 0xAB, 0xCD, 0x03, 0x01},
+		},
+		{
+			"8-bit port addressing",
+			UDH{InformationElements: []InformationElement{PortAddressing8Bit{Destination: 0xF5, Origin: 0x00}}},
+			[]byte{0x04, 0x04, 0x02, 0xF5, 0x00},
+		},
+		{
+			"16-bit port addressing",
+			UDH{InformationElements: []InformationElement{PortAddressing16Bit{Destination: 2948, Origin: 9200}}},
+			[]byte{0x06, 0x05, 0x04, 0x0B, 0x84, 0x23, 0xF0},
+		},
+		{
+			"special SMS indication",
+			UDH{InformationElements: []InformationElement{SpecialSMSIndication{Store: true, IndicationType: 0x00, Count: 4}}},
+			[]byte{0x04, 0x01, 0x02, 0x80, 0x04},
+		},
+		{
+			"national language shifts",
+			UDH{InformationElements: []InformationElement{
+				NationalLanguageSingleShift{Language: NationalLanguageTurkish},
+				NationalLanguageLockingShift{Language: NationalLanguageTurkish},
+			}},
+			[]byte{0x06, 0x24, 0x01, 0x01, 0x25, 0x01, 0x01},
+		},
+		{
+			"unknown information element",
+			UDH{InformationElements: []InformationElement{UnknownInformationElement{Identifier: 0x70, Data: []byte{0x01, 0x02, 0x03}}}},
+			[]byte{0x05, 0x70, 0x03, 0x01, 0x02, 0x03},
+		},
+		{
+			"multiple information elements",
+			UDH{InformationElements: []InformationElement{
+				Concatenated8Bit{Reference: 0x01, Total: 2, Sequence: 1},
+				NationalLanguageSingleShift{Language: NationalLanguageSpanish},
+			}},
+			[]byte{0x08, 0x00, 0x03, 0x01, 0x02, 0x01, 0x24, 0x01, 0x02},
+		},
+	}
+
+	for _, tt := range TestUDHMarshal {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.udh.Marshal()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, data)
+
+			var parsed UDH
+			assert.NoError(t, parsed.Unmarshal(data))
+			assert.Equal(t, len(tt.udh.InformationElements), len(parsed.InformationElements))
+			for idx, infoElement := range tt.udh.InformationElements {
+				assert.Equal(t, infoElement, parsed.InformationElements[idx])
+			}
+		})
+	}
+}
+
+func TestUDHMarshalReturnsErrorForOversizeUDH(t *testing.T) {
+	var TestUDHMarshalReturnsErrorForOversizeUDH = []struct {
+		name string
+		udh  UDH
+	}{
+		{
+			"information element data too long",
+			UDH{InformationElements: []InformationElement{UnknownInformationElement{Identifier: 0x70, Data: make([]byte, 256)}}},
+		},
+		{
+			"header too long",
+			UDH{InformationElements: []InformationElement{
+				UnknownInformationElement{Identifier: 0x70, Data: make([]byte, 200)},
+				UnknownInformationElement{Identifier: 0x71, Data: make([]byte, 200)},
+			}},
+		},
+	}
+
+	for _, tt := range TestUDHMarshalReturnsErrorForOversizeUDH {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.udh.Marshal()
+			assert.Equal(t, ErrInvalidUDH, err)
+		})
+	}
+}
+
+func TestUDHUnmarshalIgnoresTrailingContent(t *testing.T) {
+	var udh UDH
+
+	err := udh.Unmarshal([]byte{0x05, 0x00, 0x03, 0x01, 0x02, 0x02, 'h', 'i'})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []InformationElement{Concatenated8Bit{Reference: 0x01, Total: 2, Sequence: 2}}, udh.InformationElements)
+}
+
+func TestUDHUnmarshalReturnsErrorForMalformedUDH(t *testing.T) {
+	var TestUDHUnmarshalReturnsErrorForMalformedUDH = []struct {
+		name string
+		data []byte
+	}{
+		{"no data", []byte{}},
+		{"header length beyond data", []byte{0x05, 0x00, 0x03, 0x01}},
+		{"truncated information element header", []byte{0x01, 0x00}},
+		{"information element length beyond header", []byte{0x03, 0x00, 0x03, 0x01}},
+		{"short 8-bit concatenation", []byte{0x04, 0x00, 0x02, 0x01, 0x02}},
+		{"long 16-bit concatenation", []byte{0x07, 0x08, 0x05, 0x01, 0x02, 0x03, 0x04, 0x05}},
+		{"empty national language shift", []byte{0x02, 0x24, 0x00}},
+	}
+
+	for _, tt := range TestUDHUnmarshalReturnsErrorForMalformedUDH {
+		t.Run(tt.name, func(t *testing.T) {
+			var udh UDH
+			assert.Equal(t, ErrInvalidUDH, udh.Unmarshal(tt.data))
+		})
+	}
+}
+
+func TestUDHUnmarshalParsesSplitUDH(t *testing.T) {
+	splitter := NewSplitter()
+	encoder, _ := NewNationalGSM(NationalLanguageTurkish, NationalLanguageTurkish)
+	splitter.SetEncoder(encoder)
+	splitter.SetMessageBytes(20)
+
+	smsParts, err := splitter.Split("from", []string{"to"}, "Şişli'de buluşalım mı? Saat üçte geliyorum.")
+	assert.NoError(t, err)
+	assert.True(t, len(smsParts) > 1)
+
+	for idx, sms := range smsParts {
+		var udh UDH
+		assert.NoError(t, udh.Unmarshal(sms.GetUserData()))
+		assert.Equal(t, 3, len(udh.InformationElements))
+
+		concatenation, ok := udh.InformationElements[0].(Concatenated8Bit)
+		assert.True(t, ok)
+		assert.Equal(t, byte(len(smsParts)), concatenation.Total)
+		assert.Equal(t, byte(idx+1), concatenation.Sequence)
+		assert.Equal(t, NationalLanguageSingleShift{Language: NationalLanguageTurkish}, udh.InformationElements[1])
+		assert.Equal(t, NationalLanguageLockingShift{Language: NationalLanguageTurkish}, udh.InformationElements[2])
+	}
+}